@@ -0,0 +1,54 @@
+package xfs
+
+// AccessMode is a set of permissions checked by CanAccess.
+type AccessMode uint32
+
+const (
+	// AccessExists only checks that the file exists.
+	AccessExists AccessMode = 0
+	// AccessExecute checks for execute (or directory search) permission.
+	AccessExecute AccessMode = 1
+	// AccessWrite checks for write permission.
+	AccessWrite AccessMode = 2
+	// AccessRead checks for read permission.
+	AccessRead AccessMode = 4
+)
+
+// CanAccess checks whether the current process is allowed to access the named
+// file with the given mode and returns nil if it is. The check uses the
+// effective user and group IDs and the operating system's permission
+// evaluation, including ACLs, rather than interpreting the mode bits, so it
+// gives the same answer an actual open would.
+//
+// On Unix this uses faccessat(2) with AT_EACCESS, except on AIX, which lacks
+// it and falls back to access(2) with the real IDs. On Windows read and write
+// access are checked by opening the file, or for directories by listing it and
+// creating a temporary file in it.
+//
+// If access is denied, the error is a *PathError that satisfies
+// errors.Is(err, fs.ErrPermission).
+//
+// Parameters:
+//   - filename: the name of the file or directory
+//   - mode: the access to check e.g. AccessRead|AccessWrite
+func CanAccess(filename string, mode AccessMode) error {
+	return canAccess(filename, mode)
+}
+
+// IsReadable reports whether the current process can read the named file or
+// list the named directory.
+//
+// Parameters:
+//   - filename: the name of the file or directory
+func IsReadable(filename string) bool {
+	return canAccess(filename, AccessRead) == nil
+}
+
+// IsWritable reports whether the current process can write the named file or
+// create entries in the named directory.
+//
+// Parameters:
+//   - filename: the name of the file or directory
+func IsWritable(filename string) bool {
+	return canAccess(filename, AccessWrite) == nil
+}
@@ -0,0 +1,19 @@
+//go:build aix
+
+package xfs
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// canAccess uses access(2) because AIX has no AT_EACCESS, so the check is made
+// with the real rather than the effective user and group IDs.
+func canAccess(filename string, mode AccessMode) error {
+	if err := unix.Access(filename, uint32(mode)); err != nil {
+		return &os.PathError{Op: "access", Path: filename, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+//go:build !unix
+
+package xfs
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+func canAccess(filename string, mode AccessMode) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	if mode&AccessRead != 0 {
+		if info.IsDir() {
+			f, err := os.Open(filename)
+			if err != nil {
+				return err
+			}
+
+			_, err = f.Readdirnames(1)
+			f.Close()
+			if err != nil && !errors.Is(err, io.EOF) {
+				return &os.PathError{Op: "access", Path: filename, Err: os.ErrPermission}
+			}
+		} else {
+			f, err := os.OpenFile(filename, os.O_RDONLY, 0)
+			if err != nil {
+				return err
+			}
+			f.Close()
+		}
+	}
+
+	if mode&AccessWrite != 0 {
+		if info.IsDir() {
+			f, err := os.CreateTemp(filename, ".access-*")
+			if err != nil {
+				return err
+			}
+			f.Close()
+			os.Remove(f.Name())
+		} else {
+			f, err := os.OpenFile(filename, os.O_WRONLY, 0)
+			if err != nil {
+				return err
+			}
+			f.Close()
+		}
+	}
+
+	if mode&AccessExecute != 0 && !info.IsDir() && !isExecutable(filename, info) {
+		return &os.PathError{Op: "access", Path: filename, Err: os.ErrPermission}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanAccess(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+	xfs.WriteTextFile(path, "data", 0644)
+
+	assert.NoError(t, xfs.CanAccess(path, xfs.AccessExists))
+	assert.NoError(t, xfs.CanAccess(path, xfs.AccessRead|xfs.AccessWrite))
+	assert.ErrorIs(t, xfs.CanAccess(filepath.Join(dir, "missing"), xfs.AccessExists), os.ErrNotExist)
+
+	if runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		os.Chmod(path, 0444)
+		assert.ErrorIs(t, xfs.CanAccess(path, xfs.AccessWrite), os.ErrPermission)
+	}
+}
+
+func TestIsReadable(t *testing.T) {
+	dir := t.TempDir()
+	assert.True(t, xfs.IsReadable(dir))
+	assert.False(t, xfs.IsReadable(filepath.Join(dir, "missing")))
+
+	if runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		path := filepath.Join(dir, "secret")
+		xfs.WriteTextFile(path, "data", 0200)
+		assert.False(t, xfs.IsReadable(path))
+	}
+}
+
+func TestIsWritable(t *testing.T) {
+	dir := t.TempDir()
+	assert.True(t, xfs.IsWritable(dir))
+
+	if runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		locked := filepath.Join(dir, "locked")
+		xfs.Mkdir(locked, 0555)
+		defer os.Chmod(locked, 0755)
+		assert.False(t, xfs.IsWritable(locked))
+	}
+}
@@ -0,0 +1,17 @@
+//go:build unix && !aix
+
+package xfs
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func canAccess(filename string, mode AccessMode) error {
+	if err := unix.Faccessat(unix.AT_FDCWD, filename, uint32(mode), unix.AT_EACCESS); err != nil {
+		return &os.PathError{Op: "access", Path: filename, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package xfs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxNameLength is the longest file name, in bytes, accepted by common file
+// systems such as ext4, NTFS (in UTF-16 units) and APFS.
+const maxNameLength = 255
+
+// TreeReport describes the shape of a directory tree as measured by
+// AnalyzeTree. Paths are slash separated and relative to the analyzed root.
+type TreeReport struct {
+	// Files is the number of entries below the root, directories included.
+	Files int
+	// MaxDepth is the nesting depth of the deepest entry; the entries of the
+	// root have depth 1.
+	MaxDepth int
+	// DeepestPath is the first entry found at MaxDepth.
+	DeepestPath string
+	// LongestPath is the entry with the longest relative path.
+	LongestPath string
+	// LongestPathLen is the length of LongestPath in UTF-16 code units, the
+	// measure used by the Windows path limit.
+	LongestPathLen int
+	// LargestDir is the directory with the most entries, "." for the root.
+	LargestDir string
+	// LargestDirEntries is the number of entries in LargestDir.
+	LargestDirEntries int
+	// NonPortable lists the entries whose names cannot be used on every
+	// common operating system.
+	NonPortable []NonPortableName
+}
+
+// NonPortableName is an entry found by AnalyzeTree whose name is not
+// portable, with the reason.
+type NonPortableName struct {
+	Path   string
+	Reason string
+}
+
+// AnalyzeTree walks the tree rooted at root, without following symbolic
+// links, and reports its maximum depth, its longest path, its largest
+// directory and the names that are not portable: names reserved or invalid on
+// Windows, names ending in a dot or a space, names that are not valid UTF-8
+// or longer than 255 bytes, and names that differ from a sibling only in
+// case. It helps to check that a tree survives a checkout on Windows or being
+// written to another file system. Add the length of the destination
+// directory to LongestPathLen to compare it with a path limit.
+//
+// Parameters:
+//   - root: the directory to analyze
+func AnalyzeTree(root string) (*TreeReport, error) {
+	report := &TreeReport{LargestDir: "."}
+	counts := map[string]int{}
+	folded := map[string]string{}
+
+	err := filepath.WalkDir(root, func(path string, d DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		report.Files++
+
+		depth := strings.Count(rel, "/") + 1
+		if depth > report.MaxDepth {
+			report.MaxDepth, report.DeepestPath = depth, rel
+		}
+
+		if n := len(utf16.Encode([]rune(rel))); n > report.LongestPathLen {
+			report.LongestPathLen, report.LongestPath = n, rel
+		}
+
+		parent := "."
+		if i := strings.LastIndex(rel, "/"); i >= 0 {
+			parent = rel[:i]
+		}
+
+		counts[parent]++
+		if counts[parent] > report.LargestDirEntries {
+			report.LargestDir, report.LargestDirEntries = parent, counts[parent]
+		}
+
+		name := d.Name()
+		key := parent + "/" + strings.ToLower(name)
+		reason := nonPortableReason(name)
+		if other, ok := folded[key]; ok && reason == "" {
+			reason = fmt.Sprintf("differs from %q only in case", other)
+		}
+
+		folded[key] = name
+		if reason != "" {
+			report.NonPortable = append(report.NonPortable, NonPortableName{Path: rel, Reason: reason})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// nonPortableReason explains why name is not portable, or returns an empty
+// string.
+func nonPortableReason(name string) string {
+	if !utf8.ValidString(name) {
+		return "not valid UTF-8"
+	}
+
+	if len(name) > maxNameLength {
+		return "longer than 255 bytes"
+	}
+
+	for _, r := range name {
+		if r < 0x20 || strings.ContainsRune(`<>:"\|?*`, r) {
+			return fmt.Sprintf("contains %q", r)
+		}
+	}
+
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return "ends with a dot or a space"
+	}
+
+	stem := strings.ToUpper(name)
+	if i := strings.IndexByte(stem, '.'); i >= 0 {
+		stem = stem[:i]
+	}
+
+	stem = strings.TrimRight(stem, " ")
+	switch stem {
+	case "CON", "PRN", "AUX", "NUL":
+		return "reserved on Windows"
+	}
+
+	if len(stem) == 4 && (strings.HasPrefix(stem, "COM") || strings.HasPrefix(stem, "LPT")) && stem[3] >= '1' && stem[3] <= '9' {
+		return "reserved on Windows"
+	}
+
+	return ""
+}
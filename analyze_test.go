@@ -0,0 +1,56 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeTree(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "a", "b", "c"), 0755)
+	os.WriteFile(filepath.Join(root, "a", "b", "c", "deep.txt"), nil, 0644)
+	os.WriteFile(filepath.Join(root, "x.txt"), nil, 0644)
+	os.WriteFile(filepath.Join(root, "y.txt"), nil, 0644)
+
+	report, err := xfs.AnalyzeTree(root)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, report.Files)
+	assert.Equal(t, 4, report.MaxDepth)
+	assert.Equal(t, "a/b/c/deep.txt", report.DeepestPath)
+	assert.Equal(t, "a/b/c/deep.txt", report.LongestPath)
+	assert.Equal(t, 14, report.LongestPathLen)
+	assert.Equal(t, ".", report.LargestDir)
+	assert.Equal(t, 3, report.LargestDirEntries)
+	assert.Empty(t, report.NonPortable)
+}
+
+func TestAnalyzeTreeNonPortable(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("names cannot be created on this system")
+	}
+
+	root := t.TempDir()
+	for _, name := range []string{"aux.txt", "what?", "trailing.", "Readme", "README", "com1", "com0"} {
+		os.WriteFile(filepath.Join(root, name), nil, 0644)
+	}
+
+	report, err := xfs.AnalyzeTree(root)
+	assert.NoError(t, err)
+
+	reasons := map[string]string{}
+	for _, n := range report.NonPortable {
+		reasons[n.Path] = n.Reason
+	}
+
+	assert.Len(t, reasons, 5)
+	assert.Equal(t, "reserved on Windows", reasons["aux.txt"])
+	assert.Equal(t, "reserved on Windows", reasons["com1"])
+	assert.Equal(t, `contains '?'`, reasons["what?"])
+	assert.Equal(t, "ends with a dot or a space", reasons["trailing."])
+	assert.Equal(t, `differs from "README" only in case`, reasons["Readme"])
+}
@@ -0,0 +1,97 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+)
+
+// errAlreadyLinked is returned when LinkInto is called more than once.
+var errAlreadyLinked = errors.New("anonymous file already linked")
+
+// AnonymousFile is a file that has no name in the file system until LinkInto
+// is called. Data written to it is never visible to other processes while it
+// is incomplete, and if the process crashes the file simply disappears.
+//
+// On Linux the file is created with O_TMPFILE. Elsewhere, or when the file
+// system does not support O_TMPFILE, a hidden temporary file in the same
+// directory is used instead and LinkInto renames it into place.
+type AnonymousFile struct {
+	*File
+	dir     string
+	tmpName string
+	linked  bool
+}
+
+// CreateAnonymous creates an unnamed file in dir, opened for reading and
+// writing with mode 0600 (before umask). Call LinkInto to give it a name once
+// it is complete, and Close to release it.
+//
+// Parameters:
+//   - dir: the directory, and file system, in which to create the file
+func CreateAnonymous(dir string) (*AnonymousFile, error) {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	if f, err := openTmpfile(dir, 0600); err == nil {
+		return &AnonymousFile{File: f, dir: dir}, nil
+	}
+
+	f, err := os.CreateTemp(dir, ".anon-*")
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnonymousFile{File: f, dir: dir, tmpName: f.Name()}, nil
+}
+
+// LinkInto atomically gives the file the name path, replacing any existing
+// file. The path must be on the same file system as the directory passed to
+// CreateAnonymous. The file stays open and can be linked only once.
+//
+// Parameters:
+//   - path: the final name of the file
+func (a *AnonymousFile) LinkInto(path string) error {
+	if a.linked {
+		return &os.LinkError{Op: "link", Old: a.Name(), New: path, Err: errAlreadyLinked}
+	}
+
+	if err := a.Sync(); err != nil {
+		return err
+	}
+
+	if a.tmpName != "" {
+		if err := os.Rename(a.tmpName, path); err != nil {
+			return err
+		}
+
+		a.linked = true
+		return nil
+	}
+
+	err := linkFile(a.File, path)
+	if errors.Is(err, os.ErrExist) {
+		// linkat never replaces, so link to a unique temporary name and
+		// rename it over the destination instead.
+		err = replaceLink(path, func(tmp string) error {
+			return linkFile(a.File, tmp)
+		})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	a.linked = true
+	return nil
+}
+
+// Close closes the file. If the file was never linked, its data is discarded.
+func (a *AnonymousFile) Close() error {
+	err := a.File.Close()
+	if a.tmpName != "" && !a.linked {
+		os.Remove(a.tmpName)
+	}
+
+	return err
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package xfs
+
+import (
+	"errors"
+)
+
+func openTmpfile(dir string, perm FileMode) (*File, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func linkFile(f *File, path string) error {
+	return errors.ErrUnsupported
+}
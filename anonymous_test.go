@@ -0,0 +1,51 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAnonymous(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "result.txt")
+
+	f, err := xfs.CreateAnonymous(dir)
+	assert.NoError(t, err)
+	_, err = f.WriteString("complete")
+	assert.NoError(t, err)
+	assert.False(t, xfs.Exists(target))
+
+	assert.NoError(t, f.LinkInto(target))
+	assert.Error(t, f.LinkInto(target))
+	assert.NoError(t, f.Close())
+
+	data, err := xfs.ReadTextFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "complete", data)
+
+	// Linking over an existing file replaces it.
+	f, err = xfs.CreateAnonymous(dir)
+	assert.NoError(t, err)
+	f.WriteString("replaced")
+	assert.NoError(t, f.LinkInto(target))
+	f.Close()
+
+	data, err = xfs.ReadTextFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "replaced", data)
+
+	// An unlinked file leaves nothing behind.
+	f, err = xfs.CreateAnonymous(dir)
+	assert.NoError(t, err)
+	f.WriteString("discarded")
+	assert.NoError(t, f.Close())
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "result.txt", entries[0].Name())
+}
@@ -0,0 +1,141 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// ConfigDir returns the directory in which the application app should store
+// its user configuration, creating it with mode 0700 if it does not exist.
+//
+// It is $XDG_CONFIG_HOME/app (default ~/.config/app) on Linux and other Unix
+// systems, ~/Library/Application Support/app on macOS and %APPDATA%\app on
+// Windows.
+//
+// Parameters:
+//   - app: the application name
+func ConfigDir(app string) (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return ensureAppDir(base, app)
+}
+
+// CacheDir returns the directory in which the application app should store
+// cached data that can be recreated, creating it with mode 0700 if it does not
+// exist.
+//
+// It is $XDG_CACHE_HOME/app (default ~/.cache/app) on Linux and other Unix
+// systems, ~/Library/Caches/app on macOS and %LOCALAPPDATA%\app on Windows.
+//
+// Parameters:
+//   - app: the application name
+func CacheDir(app string) (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return ensureAppDir(base, app)
+}
+
+// DataDir returns the directory in which the application app should store
+// user data, creating it with mode 0700 if it does not exist.
+//
+// It is $XDG_DATA_HOME/app (default ~/.local/share/app) on Linux and other
+// Unix systems, ~/Library/Application Support/app on macOS and
+// %APPDATA%\app on Windows.
+//
+// Parameters:
+//   - app: the application name
+func DataDir(app string) (string, error) {
+	var base string
+	switch runtime.GOOS {
+	case "windows":
+		base = os.Getenv("APPDATA")
+		if base == "" {
+			return "", errors.New("%AppData% is not defined")
+		}
+	case "darwin", "ios":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		base = filepath.Join(home, "Library", "Application Support")
+	default:
+		dir, err := xdgDir("XDG_DATA_HOME", ".local", "share")
+		if err != nil {
+			return "", err
+		}
+
+		base = dir
+	}
+
+	return ensureAppDir(base, app)
+}
+
+// StateDir returns the directory in which the application app should store
+// state that persists between runs but is not important enough for DataDir,
+// such as logs and history, creating it with mode 0700 if it does not exist.
+//
+// It is $XDG_STATE_HOME/app (default ~/.local/state/app) on Linux and other
+// Unix systems, ~/Library/Application Support/app on macOS and
+// %LOCALAPPDATA%\app on Windows.
+//
+// Parameters:
+//   - app: the application name
+func StateDir(app string) (string, error) {
+	var base string
+	switch runtime.GOOS {
+	case "windows":
+		base = os.Getenv("LOCALAPPDATA")
+		if base == "" {
+			return "", errors.New("%LocalAppData% is not defined")
+		}
+	case "darwin", "ios":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		base = filepath.Join(home, "Library", "Application Support")
+	default:
+		dir, err := xdgDir("XDG_STATE_HOME", ".local", "state")
+		if err != nil {
+			return "", err
+		}
+
+		base = dir
+	}
+
+	return ensureAppDir(base, app)
+}
+
+// xdgDir returns the value of the XDG variable env if it is an absolute path,
+// and otherwise the default below the home directory.
+func xdgDir(env string, defaults ...string) (string, error) {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(append([]string{home}, defaults...)...), nil
+}
+
+func ensureAppDir(base, app string) (string, error) {
+	dir := filepath.Join(base, app)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
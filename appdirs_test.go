@@ -0,0 +1,74 @@
+package xfs_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the test checks the XDG layout")
+	}
+
+	base := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", base)
+
+	dir, err := xfs.ConfigDir("myapp")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "myapp"), dir)
+	assert.True(t, xfs.IsDir(dir))
+}
+
+func TestCacheDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the test checks the XDG layout")
+	}
+
+	base := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", base)
+
+	dir, err := xfs.CacheDir("myapp")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "myapp"), dir)
+	assert.True(t, xfs.IsDir(dir))
+}
+
+func TestDataDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the test checks the XDG layout")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+
+	dir, err := xfs.DataDir("myapp")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".local", "share", "myapp"), dir)
+	assert.True(t, xfs.IsDir(dir))
+}
+
+func TestStateDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the test checks the XDG layout")
+	}
+
+	base := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", base)
+
+	dir, err := xfs.StateDir("myapp")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "myapp"), dir)
+
+	// Relative XDG paths are ignored, as the specification requires.
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", "relative/state")
+	dir, err = xfs.StateDir("myapp")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".local", "state", "myapp"), dir)
+}
@@ -0,0 +1,257 @@
+package xfs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// errWriterClosed is returned when an AtomicWriter is used after it was
+// committed or aborted.
+var errWriterClosed = errors.New("atomic writer already closed")
+
+// AtomicOptions controls how an AtomicWriter publishes its file.
+type AtomicOptions struct {
+	// Sync flushes the file to stable storage before it is renamed into place
+	// and then flushes the containing directory, so that the new content
+	// survives a power loss once Commit returns.
+	Sync bool
+	// CreateDirs creates the missing parent directories of the destination
+	// with permissions 0755 (before umask).
+	CreateDirs bool
+	// Owner sets the numeric owner and group of the temporary file before it
+	// is renamed into place, so the destination never becomes visible with
+	// the wrong owner. A UID or GID of -1 keeps that value. Because changing
+	// the owner clears the setuid and setgid bits, the file is then chmod-ed
+	// to exactly the requested permissions, regardless of the umask. Names
+	// are ignored. Setting an owner fails on Windows and Plan 9.
+	Owner *FileOwner
+}
+
+// AtomicWriter writes a file by writing to a temporary file in the same
+// directory and renaming it over the destination on Commit. Readers observe
+// either the old content or the complete new content, never a partial write.
+//
+// An AtomicWriter must be finished with Commit or Abort. Close aborts an
+// uncommitted writer, which makes `defer w.Close()` a safe cleanup.
+type AtomicWriter struct {
+	f      *File
+	path   string
+	perm   FileMode
+	opts   AtomicOptions
+	closed bool
+}
+
+// NewAtomicWriter starts an atomic write of the named file. The temporary
+// file is created with permissions perm (before umask) and becomes the
+// destination, permissions included, on Commit.
+//
+// Parameters:
+//   - filename: the name of the destination file
+//   - perm: the file permissions
+//   - opts: the write options, nil uses the defaults
+func NewAtomicWriter(filename string, perm FileMode, opts *AtomicOptions) (*AtomicWriter, error) {
+	w := &AtomicWriter{path: filename, perm: perm}
+	if opts != nil {
+		w.opts = *opts
+	}
+
+	if w.opts.CreateDirs {
+		if err := EnsureParentDir(filename, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := createSibling(filename, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	w.f = f
+	return w, nil
+}
+
+// Name returns the name of the temporary file being written.
+func (w *AtomicWriter) Name() string {
+	return w.f.Name()
+}
+
+// Write writes p to the temporary file.
+//
+// Parameters:
+//   - p: the data to write
+func (w *AtomicWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, &os.PathError{Op: "write", Path: w.path, Err: errWriterClosed}
+	}
+
+	return w.f.Write(p)
+}
+
+// WriteString writes s to the temporary file.
+//
+// Parameters:
+//   - s: the text to write
+func (w *AtomicWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Commit closes the temporary file and renames it over the destination. If
+// the Sync option is set, the file and its directory are flushed to stable
+// storage. On failure the temporary file is removed.
+func (w *AtomicWriter) Commit() error {
+	if w.closed {
+		return &os.PathError{Op: "commit", Path: w.path, Err: errWriterClosed}
+	}
+
+	w.closed = true
+	err := w.commit()
+	if err != nil {
+		os.Remove(w.f.Name())
+	}
+
+	return err
+}
+
+func (w *AtomicWriter) commit() error {
+	if err := w.finish(); err != nil {
+		return err
+	}
+
+	return w.publish()
+}
+
+// finish flushes and closes the temporary file.
+func (w *AtomicWriter) finish() error {
+	if w.opts.Sync || DefaultDurability >= DurabilityDataOnly {
+		if err := w.f.Sync(); err != nil {
+			w.f.Close()
+			return err
+		}
+	}
+
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+
+	if w.opts.Owner == nil {
+		return nil
+	}
+
+	if err := os.Chown(w.f.Name(), w.opts.Owner.UID, w.opts.Owner.GID); err != nil {
+		return err
+	}
+
+	return os.Chmod(w.f.Name(), w.perm)
+}
+
+// publish renames the closed temporary file over the destination.
+func (w *AtomicWriter) publish() error {
+	if w.opts.Sync || DefaultDurability == DurabilityDataAndDir {
+		return RenameAtomic(w.f.Name(), w.path)
+	}
+
+	return os.Rename(w.f.Name(), w.path)
+}
+
+// Abort discards the temporary file and leaves the destination untouched.
+// Aborting a committed or aborted writer is a no-op.
+func (w *AtomicWriter) Abort() error {
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+	w.f.Close()
+	return os.Remove(w.f.Name())
+}
+
+// Close aborts the write unless it was committed.
+func (w *AtomicWriter) Close() error {
+	return w.Abort()
+}
+
+// WriteFileAtomic writes data to the named file atomically: the data is
+// written to a temporary file in the same directory which is then renamed
+// over the destination.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - data: the data to write
+//   - perm: the file permissions
+//   - opts: the write options, nil uses the defaults
+func WriteFileAtomic(filename string, data []byte, perm FileMode, opts *AtomicOptions) error {
+	done := instrument("writefile", filename)
+	err := writeFileAtomic(filename, data, perm, opts)
+	done(int64(len(data)), err)
+	return err
+}
+
+func writeFileAtomic(filename string, data []byte, perm FileMode, opts *AtomicOptions) error {
+	w, err := NewAtomicWriter(filename, perm, opts)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	return w.Commit()
+}
+
+// WriteFileAs writes data to the named file atomically, owned by uid and gid
+// and with exactly the permissions perm, for tools running as root that
+// provision files for other accounts. The owner and permissions are set
+// before the file becomes visible under its name. A uid or gid of -1 keeps
+// that value.
+//
+// On Windows and Plan 9, WriteFileAs always fails and leaves the destination
+// untouched.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - data: the data to write
+//   - perm: the file permissions
+//   - uid: the numeric user id of the owner
+//   - gid: the numeric group id
+func WriteFileAs(filename string, data []byte, perm FileMode, uid, gid int) error {
+	return WriteFileAtomic(filename, data, perm, &AtomicOptions{Owner: &FileOwner{UID: uid, GID: gid}})
+}
+
+// WriteFileSync writes data to the named file like WriteFile and then flushes
+// the file and its directory to stable storage, so the data survives a power
+// loss once WriteFileSync returns.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - data: the data to write
+//   - perm: the file permissions
+func WriteFileSync(filename string, data []byte, perm FileMode) error {
+	return writeFile(filename, data, perm, DurabilityDataAndDir)
+}
+
+// createSibling creates a new, uniquely named hidden file next to filename.
+// Unlike os.CreateTemp it honors perm, so the umask applies as it would for
+// the destination itself.
+func createSibling(filename string, perm FileMode) (*File, error) {
+	dir, base := filepath.Split(filename)
+	buf := make([]byte, 6)
+	for i := 0; i < maxUniqueAttempts; i++ {
+		rand.Read(buf)
+		name := filepath.Join(dir, "."+base+".tmp-"+hex.EncodeToString(buf))
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+		if err == nil {
+			return f, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, err
+		}
+	}
+
+	return nil, &os.PathError{Op: "createtemp", Path: filename, Err: os.ErrExist}
+}
@@ -0,0 +1,125 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	xfs.WriteTextFile(path, "old", 0644)
+
+	w, err := xfs.NewAtomicWriter(path, 0600, &xfs.AtomicOptions{Sync: true})
+	assert.NoError(t, err)
+	_, err = w.WriteString("new")
+	assert.NoError(t, err)
+
+	data, _ := xfs.ReadTextFile(path)
+	assert.Equal(t, "old", data)
+
+	assert.NoError(t, w.Commit())
+	assert.NoError(t, w.Close())
+	assert.Error(t, w.Commit())
+
+	data, _ = xfs.ReadTextFile(path)
+	assert.Equal(t, "new", data)
+
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(path)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	w, err = xfs.NewAtomicWriter(path, 0644, nil)
+	assert.NoError(t, err)
+	w.WriteString("discarded")
+	assert.NoError(t, w.Close())
+	assert.False(t, xfs.Exists(w.Name()))
+
+	data, _ = xfs.ReadTextFile(path)
+	assert.Equal(t, "new", data)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	err := xfs.WriteFileAtomic(path, []byte("{}"), 0644, nil)
+	assert.NoError(t, err)
+
+	data, _ := xfs.ReadTextFile(path)
+	assert.Equal(t, "{}", data)
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	assert.Len(t, entries, 1)
+}
+
+func TestWriteFileAtomicCreateDirs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "conf", "app", "state.json")
+
+	err := xfs.WriteFileAtomic(path, []byte("{}"), 0644, nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	err = xfs.WriteFileAtomic(path, []byte("{}"), 0644, &xfs.AtomicOptions{CreateDirs: true})
+	assert.NoError(t, err)
+
+	data, _ := xfs.ReadTextFile(path)
+	assert.Equal(t, "{}", data)
+}
+
+func TestWriteFileAs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("chown is not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "service.conf")
+	err := xfs.WriteFileAs(path, []byte("x"), 0640, os.Getuid(), -1)
+	assert.NoError(t, err)
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, xfs.FileMode(0640), info.Mode().Perm())
+
+	owner, err := xfs.Owner(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.Getuid(), owner.UID)
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	assert.Len(t, entries, 1)
+}
+
+func TestWriteFileSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+
+	err := xfs.WriteFileSync(path, []byte("entry"), 0644)
+	assert.NoError(t, err)
+
+	data, _ := xfs.ReadTextFile(path)
+	assert.Equal(t, "entry", data)
+}
+
+func TestSyncDir(t *testing.T) {
+	assert.NoError(t, xfs.SyncDir(t.TempDir()))
+	assert.Error(t, xfs.SyncDir(filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestRenameAtomic(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "staged")
+	dst := filepath.Join(dir, "sub", "final")
+	xfs.MkdirAllDefault(filepath.Join(dir, "sub"))
+	xfs.WriteTextFile(src, "new", 0644)
+	xfs.WriteTextFile(dst, "old", 0644)
+
+	assert.NoError(t, xfs.RenameAtomic(src, dst))
+	assert.False(t, xfs.Exists(src))
+
+	data, _ := xfs.ReadTextFile(dst)
+	assert.Equal(t, "new", data)
+
+	assert.Error(t, xfs.RenameAtomic(src, dst))
+}
@@ -0,0 +1,109 @@
+package xfs
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+)
+
+// PermissionPolicy lists the rules checked by AuditPermissions.
+type PermissionPolicy struct {
+	// NoWorldWritable reports files and directories that any user may write.
+	// Directories with the sticky bit, such as /tmp, are exempt.
+	NoWorldWritable bool
+	// NoSetuid reports files with the setuid or setgid bit.
+	NoSetuid bool
+	// Owner reports entries that are not owned by this user, given as a
+	// name or a numeric user id. Empty disables the check.
+	Owner string
+}
+
+// PermissionViolation is an entry found by AuditPermissions that breaks the
+// policy, with the reason.
+type PermissionViolation struct {
+	Path   string
+	Mode   FileMode
+	Reason string
+}
+
+// AuditPermissions walks the tree rooted at root, root included, and reports
+// the entries that violate policy, e.g. for security scans or CI checks.
+// Symbolic links are not followed and not checked, as their own permissions
+// are meaningless. Windows has no permission bits, so only the owner check
+// applies there, by name.
+//
+// Parameters:
+//   - root: the directory to audit
+//   - policy: the rules to check
+func AuditPermissions(root string, policy *PermissionPolicy) ([]PermissionViolation, error) {
+	if policy == nil {
+		policy = &PermissionPolicy{}
+	}
+
+	uid := -1
+	if policy.Owner != "" {
+		if id, err := strconv.Atoi(policy.Owner); err == nil {
+			uid = id
+		} else if u, err := user.Lookup(policy.Owner); err == nil {
+			uid, _ = strconv.Atoi(u.Uid)
+		}
+	}
+
+	// Go reports writable files on Windows as 0666 and directories as 0777,
+	// which says nothing about who may write them.
+	checkModes := runtime.GOOS != "windows"
+
+	var violations []PermissionViolation
+	err := filepath.WalkDir(root, func(path string, d DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type()&os.ModeSymlink != 0 {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		mode := info.Mode()
+		report := func(reason string) {
+			violations = append(violations, PermissionViolation{Path: path, Mode: mode, Reason: reason})
+		}
+
+		if checkModes && policy.NoWorldWritable && mode.Perm()&0002 != 0 && !(mode.IsDir() && mode&os.ModeSticky != 0) {
+			report("world-writable")
+		}
+
+		if checkModes && policy.NoSetuid && mode&os.ModeSetuid != 0 {
+			report("setuid")
+		}
+
+		if checkModes && policy.NoSetuid && mode&os.ModeSetgid != 0 && !mode.IsDir() {
+			report("setgid")
+		}
+
+		if policy.Owner != "" {
+			o, err := owner(path)
+			if err != nil {
+				return err
+			}
+
+			if o.User != policy.Owner && (uid < 0 || o.UID != uid) {
+				report("not owned by " + policy.Owner)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return violations, nil
+}
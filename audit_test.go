@@ -0,0 +1,62 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not supported on windows")
+	}
+
+	root := t.TempDir()
+	os.Chmod(root, 0755)
+	safe := filepath.Join(root, "safe")
+	open := filepath.Join(root, "open")
+	suid := filepath.Join(root, "suid")
+	shared := filepath.Join(root, "shared")
+	os.WriteFile(safe, nil, 0644)
+	os.WriteFile(open, nil, 0644)
+	os.WriteFile(suid, nil, 0755)
+	os.Mkdir(shared, 0755)
+	os.Chmod(open, 0666)
+	os.Chmod(suid, 0755|os.ModeSetuid)
+	os.Chmod(shared, 0777|os.ModeSticky)
+
+	violations, err := xfs.AuditPermissions(root, &xfs.PermissionPolicy{NoWorldWritable: true, NoSetuid: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []xfs.PermissionViolation{
+		{Path: open, Mode: 0666, Reason: "world-writable"},
+		{Path: suid, Mode: 0755 | os.ModeSetuid, Reason: "setuid"},
+	}, violations)
+
+	violations, err = xfs.AuditPermissions(root, &xfs.PermissionPolicy{Owner: strconv.Itoa(os.Getuid())})
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = xfs.AuditPermissions(root, &xfs.PermissionPolicy{Owner: strconv.Itoa(os.Getuid() + 1)})
+	assert.NoError(t, err)
+	assert.Len(t, violations, 5)
+	assert.Equal(t, "not owned by "+strconv.Itoa(os.Getuid()+1), violations[0].Reason)
+}
+
+func TestAuditPermissionsWindows(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("mode bits are only ignored on windows")
+	}
+
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "file"), nil, 0644)
+	os.Mkdir(filepath.Join(root, "dir"), 0755)
+
+	violations, err := xfs.AuditPermissions(root, &xfs.PermissionPolicy{NoWorldWritable: true, NoSetuid: true})
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+}
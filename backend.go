@@ -0,0 +1,408 @@
+package xfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ObjectInfo describes an object stored in a Backend.
+type ObjectInfo struct {
+	// Key is the slash-separated name of the object.
+	Key string
+	// Size is the length of the object in bytes.
+	Size int64
+	// ModTime is the time the object was last written.
+	ModTime time.Time
+}
+
+// Backend is a minimal remote storage interface in the style of object
+// stores such as S3 or GCS. Objects are addressed by slash-separated keys
+// that are valid fs.FS paths; directories are implied by the keys and do not
+// exist on their own. Adapters for cloud storage implement Backend so that
+// CopyDirToBackend and Sync can target them.
+//
+// Errors for missing objects must wrap fs.ErrNotExist.
+type Backend interface {
+	// Reader opens the object for streaming reads.
+	Reader(key string) (io.ReadCloser, error)
+
+	// Writer streams a new object. The object becomes visible, replacing any
+	// previous one, only when Close returns without error. If the writer
+	// also has an Abort() error method, it is called instead of Close when
+	// the upload fails.
+	Writer(key string) (io.WriteCloser, error)
+
+	// List returns the objects whose key starts with prefix, in any order.
+	List(prefix string) ([]ObjectInfo, error)
+
+	// Stat returns information about the object.
+	Stat(key string) (ObjectInfo, error)
+
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(key string) error
+}
+
+// LocalBackend is a reference Backend that stores objects as files below a
+// local directory. It transfers data in chunks of ChunkSize bytes, which
+// makes it useful for exercising code written against slower, streaming
+// remote backends.
+type LocalBackend struct {
+	root string
+
+	// ChunkSize limits how many bytes a single Read or Write transfers.
+	// Zero means no limit.
+	ChunkSize int
+}
+
+// NewLocalBackend returns a LocalBackend that stores objects below root,
+// creating the directory if needed.
+//
+// Parameters:
+//   - root: the directory holding the objects
+func NewLocalBackend(root string) (*LocalBackend, error) {
+	if err := EnsureDir(root, 0755); err != nil {
+		return nil, err
+	}
+
+	return &LocalBackend{root: root}, nil
+}
+
+// Root returns the directory holding the objects.
+func (b *LocalBackend) Root() string {
+	return b.root
+}
+
+func (b *LocalBackend) path(op, key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." {
+		return "", &os.PathError{Op: op, Path: key, Err: fs.ErrInvalid}
+	}
+
+	return filepath.Join(b.root, filepath.FromSlash(key)), nil
+}
+
+// Reader opens the object for streaming reads.
+//
+// Parameters:
+//   - key: the object key
+func (b *LocalBackend) Reader(key string) (io.ReadCloser, error) {
+	name, err := b.path("open", key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chunkedReader{File: f, chunk: b.ChunkSize}, nil
+}
+
+// Writer streams a new object, which replaces any existing one when Close
+// succeeds.
+//
+// Parameters:
+//   - key: the object key
+func (b *LocalBackend) Writer(key string) (io.WriteCloser, error) {
+	name, err := b.path("create", key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return nil, err
+	}
+
+	w, err := NewAtomicWriter(name, 0644, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chunkedWriter{w: w, chunk: b.ChunkSize}, nil
+}
+
+// List returns the objects whose key starts with prefix, sorted by key.
+//
+// Parameters:
+//   - prefix: the key prefix, "" lists every object
+func (b *LocalBackend) List(prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(b.root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || isAtomicTemp(d.Name()) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(b.root, name)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+
+	return objects, err
+}
+
+// Stat returns information about the object.
+//
+// Parameters:
+//   - key: the object key
+func (b *LocalBackend) Stat(key string) (ObjectInfo, error) {
+	name, err := b.path("stat", key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	if info.IsDir() {
+		return ObjectInfo{}, &os.PathError{Op: "stat", Path: key, Err: fs.ErrNotExist}
+	}
+
+	return ObjectInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Delete removes the object and any directories left empty by it.
+//
+// Parameters:
+//   - key: the object key
+func (b *LocalBackend) Delete(key string) error {
+	name, err := b.path("remove", key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	for dir := path.Dir(key); dir != "."; dir = path.Dir(dir) {
+		if os.Remove(filepath.Join(b.root, filepath.FromSlash(dir))) != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// isAtomicTemp reports whether name is an in-progress AtomicWriter file.
+func isAtomicTemp(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.Contains(name, ".tmp-")
+}
+
+type chunkedReader struct {
+	*File
+	chunk int
+}
+
+func (r *chunkedReader) Read(p []byte) (int, error) {
+	if r.chunk > 0 && len(p) > r.chunk {
+		p = p[:r.chunk]
+	}
+
+	return r.File.Read(p)
+}
+
+type chunkedWriter struct {
+	w     *AtomicWriter
+	chunk int
+}
+
+func (w *chunkedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := len(p)
+		if w.chunk > 0 && n > w.chunk {
+			n = w.chunk
+		}
+
+		n, err := w.w.Write(p[:n])
+		written += n
+		if err != nil {
+			return written, err
+		}
+
+		p = p[n:]
+	}
+
+	return written, nil
+}
+
+func (w *chunkedWriter) Close() error {
+	return w.w.Commit()
+}
+
+// Abort discards the object being written.
+func (w *chunkedWriter) Abort() error {
+	return w.w.Abort()
+}
+
+// CopyDirToBackend uploads the files below src to backend with the same
+// semantics as CopyDir: the key of each file is its path relative to src,
+// joined to prefix, and existing objects are only overwritten if the
+// overwrite parameter is true.
+//
+// Parameters:
+//   - src: the source directory
+//   - backend: the destination backend
+//   - prefix: the key prefix, such as "backups/2024"; "" uploads to the top
+//   - overwrite: whether to overwrite objects that already exist
+func CopyDirToBackend(src string, backend Backend, prefix string, overwrite bool) error {
+	return uploadDir(src, backend, prefix, func(key string, info FileInfo) (bool, error) {
+		if overwrite {
+			return true, nil
+		}
+
+		_, err := backend.Stat(key)
+		if errors.Is(err, fs.ErrNotExist) {
+			return true, nil
+		}
+
+		return false, err
+	})
+}
+
+// SyncOptions controls Sync.
+type SyncOptions struct {
+	// Prefix is prepended to the key of every uploaded file.
+	Prefix string
+	// Delete removes objects below Prefix that have no local counterpart,
+	// making the backend an exact mirror of the directory.
+	Delete bool
+	// Filter restricts the local files that are uploaded. Objects of files
+	// it does not match are left alone, even with Delete.
+	Filter *FileFilter
+}
+
+// Sync uploads the files below localDir that are missing from backend or
+// differ from the stored object, judged by size and by the local file being
+// modified after the object was written. Unchanged files are skipped, which
+// makes repeated calls cheap.
+//
+// Parameters:
+//   - localDir: the source directory
+//   - backend: the destination backend
+//   - opts: the sync options, nil uses the defaults
+func Sync(localDir string, backend Backend, opts *SyncOptions) error {
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+
+	objects, err := backend.List(keyPrefix(opts.Prefix))
+	if err != nil {
+		return err
+	}
+
+	remote := make(map[string]ObjectInfo, len(objects))
+	for _, obj := range objects {
+		remote[obj.Key] = obj
+	}
+
+	seen := map[string]bool{}
+	err = uploadDir(localDir, backend, opts.Prefix, func(key string, info FileInfo) (bool, error) {
+		seen[key] = true
+		if !opts.Filter.Match(info) {
+			return false, nil
+		}
+
+		obj, ok := remote[key]
+		return !ok || obj.Size != info.Size() || info.ModTime().After(obj.ModTime), nil
+	})
+	if err != nil || !opts.Delete {
+		return err
+	}
+
+	for _, obj := range objects {
+		if !seen[obj.Key] {
+			if err := backend.Delete(obj.Key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// uploadDir walks src and uploads each regular file for which upload
+// returns true.
+func uploadDir(src string, backend Backend, prefix string, upload func(key string, info FileInfo) (bool, error)) error {
+	return filepath.Walk(src, func(name string, info FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, name)
+		if err != nil {
+			return err
+		}
+
+		key := keyPrefix(prefix) + filepath.ToSlash(rel)
+		ok, err := upload(key, info)
+		if err != nil || !ok {
+			return err
+		}
+
+		return uploadFile(name, backend, key)
+	})
+}
+
+// keyPrefix turns a directory-like prefix into a key prefix ending in a
+// slash, so that "backups" does not also match "backups2/".
+func keyPrefix(prefix string) string {
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return prefix
+	}
+
+	return prefix + "/"
+}
+
+func uploadFile(name string, backend Backend, key string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := backend.Writer(key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, f); err != nil {
+		if c, ok := w.(interface{ Abort() error }); ok {
+			c.Abort()
+		}
+
+		return err
+	}
+
+	return w.Close()
+}
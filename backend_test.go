@@ -0,0 +1,95 @@
+package xfs_test
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalBackend(t *testing.T) {
+	b, err := xfs.NewLocalBackend(t.TempDir())
+	assert.NoError(t, err)
+	b.ChunkSize = 3
+
+	w, err := b.Writer("a/b/c.txt")
+	assert.NoError(t, err)
+	io.WriteString(w, "hello world")
+
+	_, err = b.Stat("a/b/c.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NoError(t, w.Close())
+
+	info, err := b.Stat("a/b/c.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), info.Size)
+
+	r, err := b.Reader("a/b/c.txt")
+	assert.NoError(t, err)
+	buf := make([]byte, 100)
+	n, _ := r.Read(buf)
+	assert.Equal(t, 3, n)
+	rest, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "hello world", string(buf[:n])+string(rest))
+
+	objects, err := b.List("a/")
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, "a/b/c.txt", objects[0].Key)
+
+	assert.NoError(t, b.Delete("a/b/c.txt"))
+	assert.NoError(t, b.Delete("a/b/c.txt"))
+	assert.False(t, xfs.Exists(filepath.Join(b.Root(), "a")))
+
+	_, err = b.Writer("../escape")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
+
+func TestCopyDirToBackend(t *testing.T) {
+	src := t.TempDir()
+	xfs.MkdirAllDefault(filepath.Join(src, "sub"))
+	os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(src, "sub", "b.txt"), []byte("b"), 0644)
+
+	b, _ := xfs.NewLocalBackend(t.TempDir())
+	assert.NoError(t, xfs.CopyDirToBackend(src, b, "backup", false))
+
+	objects, err := b.List("")
+	assert.NoError(t, err)
+	assert.Equal(t, "backup/a.txt", objects[0].Key)
+	assert.Equal(t, "backup/sub/b.txt", objects[1].Key)
+}
+
+func TestSync(t *testing.T) {
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "keep.txt"), []byte("keep"), 0644)
+	os.WriteFile(filepath.Join(src, "change.txt"), []byte("v1"), 0644)
+
+	b, _ := xfs.NewLocalBackend(t.TempDir())
+	assert.NoError(t, xfs.Sync(src, b, nil))
+
+	w, _ := b.Writer("stale.txt")
+	w.Close()
+	before, _ := b.Stat("keep.txt")
+
+	os.WriteFile(filepath.Join(src, "change.txt"), []byte("v2 longer"), 0644)
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(src, "change.txt"), future, future)
+
+	assert.NoError(t, xfs.Sync(src, b, &xfs.SyncOptions{Delete: true}))
+
+	after, _ := b.Stat("keep.txt")
+	assert.Equal(t, before.ModTime, after.ModTime)
+
+	changed, _ := b.Stat("change.txt")
+	assert.Equal(t, int64(9), changed.Size)
+
+	_, err := b.Stat("stale.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,196 @@
+package xfs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// PathPair is a source and destination path for CopyMany and MoveMany.
+type PathPair struct {
+	Src string
+	Dst string
+}
+
+// BatchOptions controls CopyMany, MoveMany and RemoveMany.
+type BatchOptions struct {
+	// Overwrite replaces destinations that already exist. Replaced
+	// destinations are kept aside until the batch succeeds so that a
+	// rollback can restore them. Without it an existing destination is an
+	// error wrapping fs.ErrExist.
+	Overwrite bool
+	// ContinueOnError attempts every operation instead of rolling back on
+	// the first failure, and returns all errors joined with errors.Join.
+	ContinueOnError bool
+}
+
+// CopyMany copies each Src to its Dst like Copy. Either every copy succeeds
+// or, on the first failure, the copies already made are removed and any
+// replaced destinations are restored before the error is returned.
+//
+// Parameters:
+//   - pairs: the source and destination paths
+//   - opts: the batch options, nil uses the defaults
+func CopyMany(pairs []PathPair, opts *BatchOptions) error {
+	return runBatch(len(pairs), opts, func(b *batch, i int) error {
+		p := pairs[i]
+		if _, err := os.Stat(p.Src); err != nil {
+			return err
+		}
+
+		if err := b.clearDst(p.Dst); err != nil {
+			return err
+		}
+
+		b.undo = append(b.undo, func() error { return os.RemoveAll(p.Dst) })
+		return Copy(p.Src, p.Dst, true)
+	})
+}
+
+// MoveMany renames each Src to its Dst. Either every move succeeds or, on the
+// first failure, the moves already made are reversed and any replaced
+// destinations are restored before the error is returned.
+//
+// Parameters:
+//   - pairs: the source and destination paths
+//   - opts: the batch options, nil uses the defaults
+func MoveMany(pairs []PathPair, opts *BatchOptions) error {
+	return runBatch(len(pairs), opts, func(b *batch, i int) error {
+		p := pairs[i]
+		if _, err := os.Lstat(p.Src); err != nil {
+			return err
+		}
+
+		if err := b.clearDst(p.Dst); err != nil {
+			return err
+		}
+
+		if err := os.Rename(p.Src, p.Dst); err != nil {
+			return err
+		}
+
+		b.undo = append(b.undo, func() error { return os.Rename(p.Dst, p.Src) })
+		return nil
+	})
+}
+
+// RemoveMany removes each path and its children. The paths are first moved
+// aside and only deleted once all of them could be moved, so on failure the
+// paths already handled are put back. Paths that do not exist are ignored.
+//
+// Parameters:
+//   - paths: the files or directories to remove
+//   - opts: the batch options, nil uses the defaults
+func RemoveMany(paths []string, opts *BatchOptions) error {
+	return runBatch(len(paths), opts, func(b *batch, i int) error {
+		if _, err := os.Lstat(paths[i]); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		return b.moveAside(paths[i])
+	})
+}
+
+// batch records how to undo the operations of a batch and which replaced
+// paths to delete once it succeeds.
+type batch struct {
+	overwrite bool
+	undo      []func() error
+	discard   []string
+}
+
+// clearDst makes room for a new destination, moving an existing one aside
+// when overwriting is allowed.
+func (b *batch) clearDst(dst string) error {
+	if _, err := os.Lstat(dst); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	if !b.overwrite {
+		return &os.PathError{Op: "batch", Path: dst, Err: fs.ErrExist}
+	}
+
+	return b.moveAside(dst)
+}
+
+// moveAside renames path to a hidden sibling that is restored on rollback
+// and deleted when the batch succeeds.
+func (b *batch) moveAside(path string) error {
+	dir, base := filepath.Split(path)
+	buf := make([]byte, 6)
+	for i := 0; i < maxUniqueAttempts; i++ {
+		rand.Read(buf)
+		aside := filepath.Join(dir, "."+base+".bak-"+hex.EncodeToString(buf))
+		if _, err := os.Lstat(aside); err == nil {
+			continue
+		}
+
+		if err := os.Rename(path, aside); err != nil {
+			return err
+		}
+
+		b.undo = append(b.undo, func() error { return os.Rename(aside, path) })
+		b.discard = append(b.discard, aside)
+		return nil
+	}
+
+	return &os.PathError{Op: "rename", Path: path, Err: fs.ErrExist}
+}
+
+// rollback undoes the operations recorded after the given marks in reverse
+// order and forgets them.
+func (b *batch) rollback(undoMark, discardMark int) error {
+	var errs []error
+	for i := len(b.undo) - 1; i >= undoMark; i-- {
+		if err := b.undo[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	b.undo = b.undo[:undoMark]
+	b.discard = b.discard[:discardMark]
+	return errors.Join(errs...)
+}
+
+func runBatch(n int, opts *BatchOptions, op func(b *batch, i int) error) error {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+
+	b := &batch{overwrite: opts.Overwrite}
+	var errs []error
+	for i := 0; i < n; i++ {
+		undoMark, discardMark := len(b.undo), len(b.discard)
+		err := op(b, i)
+		if err == nil {
+			continue
+		}
+
+		if !opts.ContinueOnError {
+			return errors.Join(err, b.rollback(0, 0))
+		}
+
+		// Undo the partial steps of the failed operation only, so that a
+		// destination moved aside for it is not discarded below.
+		errs = append(errs, err, b.rollback(undoMark, discardMark))
+	}
+
+	for _, path := range b.discard {
+		if err := os.RemoveAll(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package xfs_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyMany(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	os.WriteFile(a, []byte("a"), 0644)
+	os.WriteFile(b, []byte("b"), 0644)
+	os.WriteFile(filepath.Join(dir, "b.copy"), []byte("old"), 0644)
+
+	err := xfs.CopyMany([]xfs.PathPair{
+		{Src: a, Dst: a + ".copy"},
+		{Src: b, Dst: b + ".copy"},
+		{Src: filepath.Join(dir, "missing"), Dst: filepath.Join(dir, "missing.copy")},
+	}, &xfs.BatchOptions{Overwrite: true})
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.False(t, xfs.Exists(a+".copy"))
+	data, _ := os.ReadFile(b + ".copy")
+	assert.Equal(t, "old", string(data))
+
+	err = xfs.CopyMany([]xfs.PathPair{{Src: b, Dst: b + ".copy"}}, nil)
+	assert.ErrorIs(t, err, fs.ErrExist)
+
+	err = xfs.CopyMany([]xfs.PathPair{
+		{Src: a, Dst: a + ".copy"},
+		{Src: b, Dst: b + ".copy"},
+	}, &xfs.BatchOptions{Overwrite: true})
+	assert.NoError(t, err)
+	data, _ = os.ReadFile(b + ".copy")
+	assert.Equal(t, "b", string(data))
+
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 4)
+}
+
+func TestMoveMany(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	os.WriteFile(a, []byte("a"), 0644)
+	os.WriteFile(b, []byte("b"), 0644)
+
+	err := xfs.MoveMany([]xfs.PathPair{
+		{Src: a, Dst: filepath.Join(dir, "x")},
+		{Src: b, Dst: filepath.Join(dir, "nodir", "y")},
+	}, nil)
+	assert.Error(t, err)
+	assert.True(t, xfs.Exists(a))
+	assert.False(t, xfs.Exists(filepath.Join(dir, "x")))
+
+	err = xfs.MoveMany([]xfs.PathPair{
+		{Src: a, Dst: filepath.Join(dir, "x")},
+		{Src: b, Dst: filepath.Join(dir, "nodir", "y")},
+	}, &xfs.BatchOptions{ContinueOnError: true})
+	assert.Error(t, err)
+	assert.True(t, xfs.Exists(filepath.Join(dir, "x")))
+	assert.True(t, xfs.Exists(b))
+}
+
+func TestRemoveMany(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	sub := filepath.Join(dir, "sub")
+	os.WriteFile(a, []byte("a"), 0644)
+	xfs.MkdirAllDefault(filepath.Join(sub, "child"))
+
+	assert.NoError(t, xfs.RemoveMany([]string{a, sub, filepath.Join(dir, "missing")}, nil))
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries)
+}
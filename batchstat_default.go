@@ -0,0 +1,8 @@
+//go:build !linux || !xfs_iouring
+
+package xfs
+
+// batchStatFast reports that no fast path is compiled in.
+func batchStatFast(results []StatResult) bool {
+	return false
+}
@@ -0,0 +1,262 @@
+//go:build linux && xfs_iouring
+
+package xfs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// io_uring ABI constants, see linux/io_uring.h.
+const (
+	iouringOpStatx        = 21
+	iouringEnterGetEvents = 1
+	iouringOffSQRing      = 0
+	iouringOffCQRing      = 0x8000000
+	iouringOffSQEs        = 0x10000000
+
+	iouringEntries = 256
+	iouringSQELen  = 64
+	iouringCQELen  = 16
+)
+
+type iouringSQOffsets struct {
+	head, tail, ringMask, ringEntries, flags, dropped, array, resv1 uint32
+	userAddr                                                        uint64
+}
+
+type iouringCQOffsets struct {
+	head, tail, ringMask, ringEntries, overflow, cqes, flags, resv1 uint32
+	userAddr                                                        uint64
+}
+
+type iouringParams struct {
+	sqEntries, cqEntries, flags, sqThreadCPU, sqThreadIdle, features, wqFd uint32
+	resv                                                                   [3]uint32
+	sqOff                                                                  iouringSQOffsets
+	cqOff                                                                  iouringCQOffsets
+}
+
+// iouringSQE is a submission queue entry laid out for IORING_OP_STATX.
+type iouringSQE struct {
+	opcode      uint8
+	flags       uint8
+	ioprio      uint16
+	fd          int32
+	off         uint64 // statx buffer
+	addr        uint64 // path name
+	len         uint32 // statx mask
+	opFlags     uint32 // statx flags
+	userData    uint64
+	bufIndex    uint16
+	personality uint16
+	spliceFdIn  int32
+	addr3       uint64
+	pad         uint64
+}
+
+type iouringCQE struct {
+	userData uint64
+	res      int32
+	flags    uint32
+}
+
+type iouring struct {
+	fd     int
+	params iouringParams
+	sq     []byte
+	cq     []byte
+	sqes   []byte
+}
+
+func newIOURing() (*iouring, error) {
+	r := &iouring{}
+	fd, _, errno := unix.Syscall(unix.SYS_IO_URING_SETUP, iouringEntries, uintptr(unsafe.Pointer(&r.params)), 0)
+	if errno != 0 {
+		return nil, errno
+	}
+
+	r.fd = int(fd)
+	p := &r.params
+	var err error
+	r.sq, err = unix.Mmap(r.fd, iouringOffSQRing, int(p.sqOff.array+p.sqEntries*4), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE)
+	if err == nil {
+		r.cq, err = unix.Mmap(r.fd, iouringOffCQRing, int(p.cqOff.cqes+p.cqEntries*iouringCQELen), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE)
+	}
+
+	if err == nil {
+		r.sqes, err = unix.Mmap(r.fd, iouringOffSQEs, int(p.sqEntries*iouringSQELen), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE)
+	}
+
+	if err != nil {
+		r.close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *iouring) close() {
+	for _, m := range [][]byte{r.sq, r.cq, r.sqes} {
+		if m != nil {
+			unix.Munmap(m)
+		}
+	}
+
+	unix.Close(r.fd)
+}
+
+func ringU32(ring []byte, off uint32) *uint32 {
+	return (*uint32)(unsafe.Pointer(&ring[off]))
+}
+
+// statx submits one statx per result and waits for all of them. The number
+// of results must not exceed the submission queue size.
+func (r *iouring) statx(results []StatResult, bufs []unix.Statx_t) error {
+	p := &r.params
+	names := make([][]byte, len(results))
+	mask := *ringU32(r.sq, p.sqOff.ringMask)
+	tail := atomic.LoadUint32(ringU32(r.sq, p.sqOff.tail))
+	array := unsafe.Slice((*uint32)(unsafe.Pointer(&r.sq[p.sqOff.array])), p.sqEntries)
+	for i := range results {
+		name, err := unix.ByteSliceFromString(results[i].Path)
+		if err != nil {
+			return err
+		}
+
+		names[i] = name
+		idx := (tail + uint32(i)) & mask
+		sqe := (*iouringSQE)(unsafe.Pointer(&r.sqes[idx*iouringSQELen]))
+		*sqe = iouringSQE{
+			opcode:   iouringOpStatx,
+			fd:       unix.AT_FDCWD,
+			off:      uint64(uintptr(unsafe.Pointer(&bufs[i]))),
+			addr:     uint64(uintptr(unsafe.Pointer(&name[0]))),
+			len:      unix.STATX_BASIC_STATS,
+			userData: uint64(i),
+		}
+		array[idx] = idx
+	}
+
+	atomic.StoreUint32(ringU32(r.sq, p.sqOff.tail), tail+uint32(len(results)))
+
+	pending := len(results)
+	for pending > 0 {
+		_, _, errno := unix.Syscall6(unix.SYS_IO_URING_ENTER, uintptr(r.fd), uintptr(pending), uintptr(pending), iouringEnterGetEvents, 0, 0)
+		if errno == unix.EINTR {
+			continue
+		}
+
+		if errno != 0 {
+			return errno
+		}
+
+		head := atomic.LoadUint32(ringU32(r.cq, p.cqOff.head))
+		cqTail := atomic.LoadUint32(ringU32(r.cq, p.cqOff.tail))
+		cqMask := *ringU32(r.cq, p.cqOff.ringMask)
+		for ; head != cqTail; head++ {
+			cqe := (*iouringCQE)(unsafe.Pointer(&r.cq[p.cqOff.cqes+(head&cqMask)*iouringCQELen]))
+			i := int(cqe.userData)
+			if cqe.res < 0 {
+				results[i].Err = &os.PathError{Op: "stat", Path: results[i].Path, Err: syscall.Errno(-cqe.res)}
+			} else {
+				results[i].Info = statxFileInfo(results[i].Path, &bufs[i])
+			}
+
+			pending--
+		}
+
+		atomic.StoreUint32(ringU32(r.cq, p.cqOff.head), head)
+	}
+
+	runtime.KeepAlive(names)
+	runtime.KeepAlive(bufs)
+	return nil
+}
+
+func batchStatFast(results []StatResult) bool {
+	r, err := newIOURing()
+	if err != nil {
+		return false
+	}
+	defer r.close()
+
+	bufs := make([]unix.Statx_t, r.params.sqEntries)
+	for start := 0; start < len(results); start += len(bufs) {
+		end := min(start+len(bufs), len(results))
+		if err := r.statx(results[start:end], bufs[:end-start]); err != nil {
+			// Nothing is known about this batch; let the portable path
+			// redo all of it.
+			for i := range results {
+				results[i].Info, results[i].Err = nil, nil
+			}
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// statxInfo implements FileInfo for a statx result. Sys returns the
+// *unix.Statx_t.
+type statxInfo struct {
+	name string
+	mode FileMode
+	stx  unix.Statx_t
+}
+
+func statxFileInfo(path string, stx *unix.Statx_t) FileInfo {
+	fi := &statxInfo{name: filepath.Base(path), stx: *stx}
+	fi.mode = FileMode(stx.Mode & 0777)
+	switch uint32(stx.Mode) & unix.S_IFMT {
+	case unix.S_IFBLK:
+		fi.mode |= fs.ModeDevice
+	case unix.S_IFCHR:
+		fi.mode |= fs.ModeDevice | fs.ModeCharDevice
+	case unix.S_IFDIR:
+		fi.mode |= fs.ModeDir
+	case unix.S_IFIFO:
+		fi.mode |= fs.ModeNamedPipe
+	case unix.S_IFLNK:
+		fi.mode |= fs.ModeSymlink
+	case unix.S_IFSOCK:
+		fi.mode |= fs.ModeSocket
+	}
+
+	if stx.Mode&unix.S_ISUID != 0 {
+		fi.mode |= fs.ModeSetuid
+	}
+
+	if stx.Mode&unix.S_ISGID != 0 {
+		fi.mode |= fs.ModeSetgid
+	}
+
+	if stx.Mode&unix.S_ISVTX != 0 {
+		fi.mode |= fs.ModeSticky
+	}
+
+	return fi
+}
+
+func (fi *statxInfo) Name() string   { return fi.name }
+func (fi *statxInfo) Size() int64    { return int64(fi.stx.Size) }
+func (fi *statxInfo) Mode() FileMode { return fi.mode }
+func (fi *statxInfo) ModTime() time.Time {
+	return time.Unix(fi.stx.Mtime.Sec, int64(fi.stx.Mtime.Nsec))
+}
+func (fi *statxInfo) IsDir() bool { return fi.mode.IsDir() }
+func (fi *statxInfo) Sys() any    { return &fi.stx }
+
+// identity returns the device and inode numbers for fileIdentity.
+func (fi *statxInfo) identity() (dev, ino uint64) {
+	return unix.Mkdev(fi.stx.Dev_major, fi.stx.Dev_minor), fi.stx.Ino
+}
@@ -0,0 +1,72 @@
+package xfs_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchStat(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for i := 0; i < 600; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("f%d", i))
+		os.WriteFile(name, make([]byte, i), 0644)
+		paths = append(paths, name)
+	}
+
+	paths = append(paths, filepath.Join(dir, "missing"), dir)
+	results := xfs.BatchStat(paths)
+	assert.Len(t, results, len(paths))
+
+	for i := 0; i < 600; i++ {
+		assert.NoError(t, results[i].Err)
+		assert.Equal(t, int64(i), results[i].Info.Size())
+		assert.Equal(t, fmt.Sprintf("f%d", i), results[i].Info.Name())
+
+		want, _ := os.Stat(paths[i])
+		wantID, _ := xfs.FileIDInfo(paths[i], want)
+		gotID, _ := xfs.FileIDInfo(paths[i], results[i].Info)
+		assert.Equal(t, wantID, gotID)
+		assert.True(t, want.ModTime().Equal(results[i].Info.ModTime()))
+	}
+
+	assert.ErrorIs(t, results[600].Err, os.ErrNotExist)
+	assert.True(t, results[601].Info.IsDir())
+}
+
+func TestStatMany(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for i := 0; i < 50; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("file%02d", i))
+		os.WriteFile(path, make([]byte, i), 0644)
+		paths = append(paths, path)
+	}
+
+	paths = append(paths, filepath.Join(dir, "missing"))
+	results := xfs.StatMany(paths)
+	assert.Len(t, results, 51)
+	for i, r := range results[:50] {
+		assert.Equal(t, paths[i], r.Path)
+		assert.NoError(t, r.Err)
+		assert.Equal(t, int64(i), r.Info.Size())
+	}
+
+	assert.ErrorIs(t, results[50].Err, os.ErrNotExist)
+
+	direct, _ := os.Stat(paths[3])
+	assert.True(t, os.SameFile(direct, results[3].Info))
+
+	if xfs.CanSymlink() {
+		link := filepath.Join(dir, "link")
+		os.Symlink(paths[10], link)
+		results = xfs.StatManyOpt([]string{link}, &xfs.StatManyOptions{Concurrency: 1, NoFollow: true})
+		assert.NoError(t, results[0].Err)
+		assert.NotZero(t, results[0].Info.Mode()&os.ModeSymlink)
+	}
+}
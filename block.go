@@ -0,0 +1,132 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// errUnclosedBlock is returned when a begin marker has no matching end marker.
+var errUnclosedBlock = errors.New("block begin marker without end marker")
+
+// UpsertBlock inserts or replaces a block of lines delimited by the marker
+// lines markerBegin and markerEnd in the named text file, like Ansible's
+// blockinfile, and reports whether the file changed. If the markers are
+// present, the lines between them are replaced with content; otherwise the
+// markers and content are appended to the file. A missing file is created
+// with permissions 0644 (before umask).
+//
+// Markers are matched against whole lines. A trailing newline in content is
+// ignored, and the block uses the terminator of the file's first line, or
+// DefaultEOL for a file without terminators. The file is rewritten atomically
+// with its current permissions.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - markerBegin: the line opening the block, e.g. "# BEGIN managed"
+//   - markerEnd: the line closing the block, e.g. "# END managed"
+//   - content: the text of the block
+func UpsertBlock(filename, markerBegin, markerEnd, content string) (bool, error) {
+	lines, perm, err := readLinesForEdit(filename)
+	if err != nil {
+		return false, err
+	}
+
+	eol := DefaultEOL
+	if len(lines) > 0 && lines[0].EOL != "" {
+		eol = lines[0].EOL
+	}
+
+	block := []Line{{Text: markerBegin, EOL: eol}}
+	if content != "" {
+		for _, text := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+			block = append(block, Line{Text: strings.TrimSuffix(text, "\r"), EOL: eol})
+		}
+	}
+	block = append(block, Line{Text: markerEnd, EOL: eol})
+
+	begin, end, err := findBlock(filename, lines, markerBegin, markerEnd)
+	if err != nil {
+		return false, err
+	}
+
+	var updated []Line
+	if begin < 0 {
+		if n := len(lines); n > 0 && lines[n-1].EOL == "" {
+			lines[n-1].EOL = eol
+		}
+
+		updated = append(lines, block...)
+	} else {
+		if equalLineText(lines[begin:end+1], block) {
+			return false, nil
+		}
+
+		// Keep the original terminator of the end marker, so a block at the
+		// end of a file without a final newline does not gain one.
+		block[len(block)-1].EOL = lines[end].EOL
+		updated = append(updated, lines[:begin]...)
+		updated = append(updated, block...)
+		updated = append(updated, lines[end+1:]...)
+	}
+
+	return true, writeLinesAtomic(filename, updated, perm)
+}
+
+// RemoveBlock removes the block delimited by the marker lines markerBegin and
+// markerEnd, markers included, from the named file and reports whether the
+// file changed. The file is rewritten atomically with its current
+// permissions.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - markerBegin: the line opening the block
+//   - markerEnd: the line closing the block
+func RemoveBlock(filename, markerBegin, markerEnd string) (bool, error) {
+	lines, perm, err := readLinesForEdit(filename)
+	if err != nil {
+		return false, err
+	}
+
+	begin, end, err := findBlock(filename, lines, markerBegin, markerEnd)
+	if err != nil || begin < 0 {
+		return false, err
+	}
+
+	lines = append(lines[:begin], lines[end+1:]...)
+	return true, writeLinesAtomic(filename, lines, perm)
+}
+
+// findBlock returns the indexes of the first begin marker and the end marker
+// following it, or -1 if there is no begin marker.
+func findBlock(filename string, lines []Line, markerBegin, markerEnd string) (int, int, error) {
+	for i, line := range lines {
+		if line.Text != markerBegin {
+			continue
+		}
+
+		for j := i + 1; j < len(lines); j++ {
+			if lines[j].Text == markerEnd {
+				return i, j, nil
+			}
+		}
+
+		return -1, -1, &os.PathError{Op: "upsertblock", Path: filename, Err: errUnclosedBlock}
+	}
+
+	return -1, -1, nil
+}
+
+func equalLineText(a, b []Line) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Text != b[i].Text {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,45 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertBlock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	os.WriteFile(path, []byte("keep\n"), 0644)
+
+	changed, err := xfs.UpsertBlock(path, "# BEGIN", "# END", "a=1\nb=2\n")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "keep\n# BEGIN\na=1\nb=2\n# END\n", string(data))
+
+	changed, err = xfs.UpsertBlock(path, "# BEGIN", "# END", "a=1\nb=2")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	os.WriteFile(path, append(data, "tail\n"...), 0644)
+	changed, err = xfs.UpsertBlock(path, "# BEGIN", "# END", "c=3")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	data, _ = os.ReadFile(path)
+	assert.Equal(t, "keep\n# BEGIN\nc=3\n# END\ntail\n", string(data))
+
+	changed, err = xfs.RemoveBlock(path, "# BEGIN", "# END")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	data, _ = os.ReadFile(path)
+	assert.Equal(t, "keep\ntail\n", string(data))
+
+	os.WriteFile(path, []byte("# BEGIN\nno end\n"), 0644)
+	_, err = xfs.UpsertBlock(path, "# BEGIN", "# END", "x")
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package xfs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxProbedNameLength bounds the file name length probed by Capabilities.
+const maxProbedNameLength = 1024
+
+// FSCapabilities describes what the file system at a location supports, as
+// found by Capabilities.
+type FSCapabilities struct {
+	// Symlinks reports whether symbolic links can be created, which on
+	// Windows also depends on the privileges of the process.
+	Symlinks bool
+	// HardLinks reports whether hard links can be created.
+	HardLinks bool
+	// CaseSensitive reports whether names differing only in case refer to
+	// different files.
+	CaseSensitive bool
+	// SparseFiles reports whether files can have holes that use no storage.
+	SparseFiles bool
+	// Xattrs reports whether extended attributes are supported. It is only
+	// detected on Linux, macOS and Windows.
+	Xattrs bool
+	// MaxNameLength is the longest file name, in bytes, that can be created.
+	MaxNameLength int
+	// MaxPathLength is the longest path the operating system accepts, or
+	// zero if unknown.
+	MaxPathLength int
+	// AtomicRename reports whether renaming over an existing file replaces
+	// it atomically, so that readers see either the old or the new file.
+	AtomicRename bool
+}
+
+// Capabilities probes the file system containing the directory dir by
+// creating, and then removing, a few files in a temporary directory inside
+// it. Tools can use the result to adapt their behavior, e.g. copy instead of
+// link, rather than failing midway. The process needs write access to dir.
+//
+// Parameters:
+//   - dir: a directory on the file system to probe
+func Capabilities(dir string) (*FSCapabilities, error) {
+	probe, err := os.MkdirTemp(dir, ".xfs-probe-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(probe)
+
+	file := filepath.Join(probe, "probe")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		return nil, err
+	}
+
+	c := &FSCapabilities{
+		MaxPathLength: maxPathLength,
+		AtomicRename:  atomicRename,
+	}
+
+	c.Symlinks = os.Symlink("probe", filepath.Join(probe, "symlink")) == nil
+	c.HardLinks = os.Link(file, filepath.Join(probe, "hardlink")) == nil
+
+	_, err = os.Lstat(filepath.Join(probe, "PROBE"))
+	c.CaseSensitive = os.IsNotExist(err)
+
+	c.SparseFiles, c.Xattrs = probeFSFeatures(probe, file)
+	c.MaxNameLength = probeNameLength(probe)
+	return c, nil
+}
+
+// probeNameLength returns the length of the longest file name that can be
+// created in dir, found by bisection.
+func probeNameLength(dir string) int {
+	fits := func(n int) bool {
+		name := filepath.Join(dir, strings.Repeat("n", n))
+		if err := os.WriteFile(name, nil, 0644); err != nil {
+			return false
+		}
+
+		os.Remove(name)
+		return true
+	}
+
+	lo, hi := 0, maxProbedNameLength+1
+	for lo+1 < hi {
+		mid := (lo + hi) / 2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo
+}
@@ -0,0 +1,7 @@
+//go:build unix && !linux && !darwin && !ios
+
+package xfs
+
+func xattrSupported(file string) bool {
+	return false
+}
@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package xfs
+
+const atomicRename = false
+
+const maxPathLength = 0
+
+func probeFSFeatures(dir, file string) (sparse, xattrs bool) {
+	return false, false
+}
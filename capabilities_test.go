@@ -0,0 +1,28 @@
+package xfs_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilities(t *testing.T) {
+	dir := t.TempDir()
+	c, err := xfs.Capabilities(dir)
+	assert.NoError(t, err)
+
+	assert.Equal(t, xfs.CanSymlink(), c.Symlinks)
+	assert.True(t, c.HardLinks)
+	assert.GreaterOrEqual(t, c.MaxNameLength, 100)
+	assert.Greater(t, c.MaxPathLength, 0)
+
+	if runtime.GOOS == "linux" {
+		assert.True(t, c.CaseSensitive)
+		assert.True(t, c.AtomicRename)
+	}
+
+	names, _ := xfs.ReadDirNames(dir)
+	assert.Empty(t, names)
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package xfs
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// atomicRename is true because POSIX requires rename(2) to replace the
+// destination atomically.
+const atomicRename = true
+
+// maxPathLength is PATH_MAX of the operating system.
+const maxPathLength = unix.PathMax
+
+// probeFSFeatures reports whether the file system supports sparse files, by
+// checking whether a file extended with truncate is allocated, and extended
+// attributes.
+func probeFSFeatures(dir, file string) (sparse, xattrs bool) {
+	const size = 4 << 20
+	if err := os.Truncate(file, size); err == nil {
+		if info, err := os.Stat(file); err == nil {
+			if st, ok := info.Sys().(*syscall.Stat_t); ok {
+				sparse = int64(st.Blocks)*512 < size
+			}
+		}
+
+		os.Truncate(file, 0)
+	}
+
+	return sparse, xattrSupported(file)
+}
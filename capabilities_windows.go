@@ -0,0 +1,40 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// atomicRename is false: MoveFileEx replaces files, but Windows does not
+// guarantee that the replacement is atomic.
+const atomicRename = false
+
+// maxPathLength is the limit of extended-length paths, which package os
+// uses for long paths.
+const maxPathLength = 32767
+
+// probeFSFeatures reads the sparse file and extended attribute support from
+// the volume flags.
+func probeFSFeatures(dir, file string) (sparse, xattrs bool) {
+	name, err := windows.UTF16PtrFromString(fixLongPath(dir))
+	if err != nil {
+		return false, false
+	}
+
+	h, err := windows.CreateFile(name, 0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil, windows.OPEN_EXISTING, windows.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return false, false
+	}
+	defer windows.CloseHandle(h)
+
+	var flags uint32
+	if err := windows.GetVolumeInformationByHandle(h, nil, 0, nil, nil, &flags, nil, 0); err != nil {
+		return false, false
+	}
+
+	return flags&windows.FILE_SUPPORTS_SPARSE_FILES != 0, flags&windows.FILE_SUPPORTS_EXTENDED_ATTRIBUTES != 0
+}
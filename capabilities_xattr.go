@@ -0,0 +1,10 @@
+//go:build linux || darwin || ios
+
+package xfs
+
+import "golang.org/x/sys/unix"
+
+// xattrSupported reports whether an extended attribute can be set on file.
+func xattrSupported(file string) bool {
+	return unix.Setxattr(file, "user.xfs.probe", []byte("1"), 0) == nil
+}
@@ -0,0 +1,178 @@
+package xfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrInvalidKey is returned by CAS methods when a key is not a hex encoded
+// SHA-256 digest.
+var ErrInvalidKey = errors.New("xfs: invalid content key")
+
+// CAS is a content-addressable blob store backed by a directory. Blobs are
+// stored under the hex encoded SHA-256 digest of their content and sharded
+// into subdirectories named after the first two characters of the key.
+//
+// Blobs are written to a temporary file inside the store and renamed into
+// place, so readers never observe a partially written blob.
+type CAS struct {
+	root string
+}
+
+// NewCAS opens the content-addressable store rooted at root, creating the
+// directory if it does not exist.
+//
+// Parameters:
+//   - root: the directory holding the store
+func NewCAS(root string) (*CAS, error) {
+	if err := os.MkdirAll(filepath.Join(root, ".tmp"), 0755); err != nil {
+		return nil, err
+	}
+
+	return &CAS{root: root}, nil
+}
+
+// Root returns the directory holding the store.
+func (c *CAS) Root() string {
+	return c.root
+}
+
+// Put stores the content read from r and returns its key. Storing content
+// that is already present is a no-op that returns the existing key.
+//
+// Parameters:
+//   - r: the content to store
+func (c *CAS) Put(r io.Reader) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Join(c.root, ".tmp"), "blob-*")
+	if err != nil {
+		return "", err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), r); err != nil {
+		return "", err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	key := hex.EncodeToString(h.Sum(nil))
+	dst := c.path(key)
+	if Exists(dst) {
+		return key, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0444); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return "", err
+	}
+
+	committed = true
+	return key, nil
+}
+
+// PutFile stores the content of the named file and returns its key.
+//
+// Parameters:
+//   - filename: the name of the file
+func (c *CAS) PutFile(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return c.Put(f)
+}
+
+// Get opens the blob stored under key for reading. If the blob does not
+// exist, the error wraps fs.ErrNotExist.
+//
+// Parameters:
+//   - key: the content key returned by Put
+func (c *CAS) Get(key string) (*File, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	return os.Open(c.path(key))
+}
+
+// Has reports whether a blob is stored under key.
+//
+// Parameters:
+//   - key: the content key returned by Put
+func (c *CAS) Has(key string) bool {
+	if validateKey(key) != nil {
+		return false
+	}
+
+	return IsFile(c.path(key))
+}
+
+// Path returns the path of the blob stored under key.
+//
+// Parameters:
+//   - key: the content key returned by Put
+func (c *CAS) Path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	return c.path(key), nil
+}
+
+// Delete removes the blob stored under key. Deleting a missing blob is not
+// an error.
+//
+// Parameters:
+//   - key: the content key returned by Put
+func (c *CAS) Delete(key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	err := os.Remove(c.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CAS) path(key string) string {
+	return filepath.Join(c.root, key[:2], key[2:])
+}
+
+func validateKey(key string) error {
+	if len(key) != sha256.Size*2 {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	if _, err := hex.DecodeString(key); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package xfs_test
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCAS(t *testing.T) {
+	store, err := xfs.NewCAS(filepath.Join(t.TempDir(), "store"))
+	assert.NoError(t, err)
+
+	key, err := store.Put(strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", key)
+	assert.True(t, store.Has(key))
+
+	path, err := store.Path(key)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(store.Root(), "2c", key[2:]), path)
+
+	again, err := store.Put(strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, key, again)
+
+	f, err := store.Get(key)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(f)
+	f.Close()
+	assert.Equal(t, "hello", string(data))
+
+	_, err = store.Get("not-a-key")
+	assert.ErrorIs(t, err, xfs.ErrInvalidKey)
+
+	assert.NoError(t, store.Delete(key))
+	assert.False(t, store.Has(key))
+	assert.NoError(t, store.Delete(key))
+}
+
+func TestCASPutFile(t *testing.T) {
+	store, err := xfs.NewCAS(t.TempDir())
+	assert.NoError(t, err)
+
+	src := filepath.Join(t.TempDir(), "src.txt")
+	xfs.WriteTextFile(src, "hello", 0644)
+
+	key, err := store.PutFile(src)
+	assert.NoError(t, err)
+	assert.True(t, store.Has(key))
+}
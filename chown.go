@@ -0,0 +1,18 @@
+package xfs
+
+// ChownName changes the owner and group of the named file using a user name
+// and a group name instead of numeric IDs. An empty name leaves that value
+// unchanged. Names that are decimal numbers are used as IDs directly. If the
+// file is a symbolic link, it changes the owner of the link's target.
+//
+// On Unix the names are resolved with os/user. On Windows they are resolved
+// to security identifiers (SIDs) and set as the owner and primary group of the
+// file's security descriptor, which requires the appropriate privileges.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - username: the name of the new owner e.g. "svc-user"
+//   - groupname: the name of the new group e.g. "svc-group"
+func ChownName(filename, username, groupname string) error {
+	return chownName(filename, username, groupname)
+}
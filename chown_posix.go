@@ -0,0 +1,58 @@
+//go:build !windows
+
+package xfs
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+)
+
+func chownName(filename, username, groupname string) error {
+	uid, gid := -1, -1
+	if username != "" {
+		id, err := lookupUID(username)
+		if err != nil {
+			return &os.PathError{Op: "chown", Path: filename, Err: err}
+		}
+
+		uid = id
+	}
+
+	if groupname != "" {
+		id, err := lookupGID(groupname)
+		if err != nil {
+			return &os.PathError{Op: "chown", Path: filename, Err: err}
+		}
+
+		gid = id
+	}
+
+	return os.Chown(filename, uid, gid)
+}
+
+func lookupUID(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+
+	u, err := user.Lookup(name)
+	if err != nil {
+		return -1, err
+	}
+
+	return strconv.Atoi(u.Uid)
+}
+
+func lookupGID(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return -1, err
+	}
+
+	return strconv.Atoi(g.Gid)
+}
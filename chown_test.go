@@ -0,0 +1,36 @@
+package xfs_test
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChownName(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("changing owners requires privileges on Windows")
+	}
+
+	current, err := user.Current()
+	assert.NoError(t, err)
+	group, err := user.LookupGroupId(current.Gid)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "file.txt")
+	xfs.WriteTextFile(path, "data", 0644)
+
+	assert.NoError(t, xfs.ChownName(path, current.Username, group.Name))
+	assert.NoError(t, xfs.ChownName(path, current.Uid, ""))
+	assert.NoError(t, xfs.ChownName(path, "", ""))
+
+	err = xfs.ChownName(path, "no-such-user-xfs", "")
+	assert.Error(t, err)
+
+	err = xfs.ChownName(filepath.Join(t.TempDir(), "missing"), current.Username, "")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,56 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func chownName(filename, username, groupname string) error {
+	var info windows.SECURITY_INFORMATION
+	var owner, group *windows.SID
+	if username != "" {
+		sid, err := lookupSID(username)
+		if err != nil {
+			return &os.PathError{Op: "chown", Path: filename, Err: err}
+		}
+
+		owner = sid
+		info |= windows.OWNER_SECURITY_INFORMATION
+	}
+
+	if groupname != "" {
+		sid, err := lookupSID(groupname)
+		if err != nil {
+			return &os.PathError{Op: "chown", Path: filename, Err: err}
+		}
+
+		group = sid
+		info |= windows.GROUP_SECURITY_INFORMATION
+	}
+
+	if info == 0 {
+		_, err := os.Stat(filename)
+		return err
+	}
+
+	err := windows.SetNamedSecurityInfo(filename, windows.SE_FILE_OBJECT, info, owner, group, nil, nil)
+	if err != nil {
+		return &os.PathError{Op: "chown", Path: filename, Err: err}
+	}
+
+	return nil
+}
+
+// lookupSID resolves an account name, or a SID in string form, to a SID.
+func lookupSID(name string) (*windows.SID, error) {
+	if sid, err := windows.StringToSid(name); err == nil {
+		return sid, nil
+	}
+
+	sid, _, _, err := windows.LookupSID("", name)
+	return sid, err
+}
@@ -0,0 +1,298 @@
+package xfs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Codec describes a compression format for the compression-aware helpers
+// OpenCompressed, CreateCompressed and ReadFileAuto.
+type Codec struct {
+	// Name identifies the codec, e.g. "zstd".
+	Name string
+	// Extensions are the lower case file extensions, with their leading dot,
+	// that select the codec in CreateCompressed.
+	Extensions []string
+	// Magic is the signature at the start of compressed data that selects
+	// the codec in OpenCompressed. Only the first 16 bytes are examined.
+	Magic []byte
+	// NewReader returns a decompressing reader. Nil means the codec cannot
+	// decompress.
+	NewReader func(r io.Reader) (io.ReadCloser, error)
+	// NewWriter returns a compressing writer, which must flush all data on
+	// Close. Nil means the codec cannot compress.
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+
+	// codecs lists the registered formats. zstd, xz and lz4 are recognized
+	// without an implementation so that they are reported as unsupported
+	// rather than read as plain data until a codec is registered.
+	codecs = []*Codec{
+		{
+			Name:       "gzip",
+			Extensions: []string{".gz", ".tgz"},
+			Magic:      []byte{0x1f, 0x8b},
+			NewReader: func(r io.Reader) (io.ReadCloser, error) {
+				return gzip.NewReader(r)
+			},
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+				return gzip.NewWriter(w), nil
+			},
+		},
+		{
+			Name:       "bzip2",
+			Extensions: []string{".bz2", ".tbz2"},
+			Magic:      []byte("BZh"),
+			NewReader: func(r io.Reader) (io.ReadCloser, error) {
+				return io.NopCloser(bzip2.NewReader(r)), nil
+			},
+		},
+		{
+			Name:       "zstd",
+			Extensions: []string{".zst", ".tzst"},
+			Magic:      []byte{0x28, 0xb5, 0x2f, 0xfd},
+		},
+		{
+			Name:       "xz",
+			Extensions: []string{".xz", ".txz"},
+			Magic:      []byte{0xfd, '7', 'z', 'X', 'Z', 0x00},
+		},
+		{
+			Name:       "lz4",
+			Extensions: []string{".lz4"},
+			Magic:      []byte{0x04, 0x22, 0x4d, 0x18},
+		},
+	}
+)
+
+// RegisterCodec adds a compression format, or replaces the registered codec
+// with the same name, so that formats such as zstd can be supported without
+// this package depending on their implementation. A newly added codec takes
+// precedence over the existing ones when extensions or signatures overlap.
+// RegisterCodec is typically called from an init function.
+//
+// Parameters:
+//   - c: the codec to register
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	for i, existing := range codecs {
+		if existing.Name == c.Name {
+			codecs[i] = &c
+			return
+		}
+	}
+
+	codecs = append([]*Codec{&c}, codecs...)
+}
+
+// maxMagicLen is the number of bytes peeked to detect a compression format.
+const maxMagicLen = 16
+
+func codecByMagic(head []byte) *Codec {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	for _, c := range codecs {
+		if len(c.Magic) > 0 && bytes.HasPrefix(head, c.Magic) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func codecByExt(filename string) *Codec {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, c := range codecs {
+		for _, e := range c.Extensions {
+			if e == ext {
+				return c
+			}
+		}
+	}
+
+	return nil
+}
+
+func unsupportedCodec(op, filename string, c *Codec) error {
+	return &os.PathError{Op: op, Path: filename, Err: &unsupportedError{fmt.Errorf("%s compression is not supported", c.Name)}}
+}
+
+// compressedReader closes both the decompressor, if any, and the underlying
+// file.
+type compressedReader struct {
+	io.Reader
+	dec io.Closer
+	f   *File
+}
+
+func (r *compressedReader) Close() error {
+	var err error
+	if r.dec != nil {
+		err = r.dec.Close()
+	}
+
+	if ferr := r.f.Close(); err == nil {
+		err = ferr
+	}
+
+	return err
+}
+
+// compressedWriter flushes the compressor before closing the underlying file.
+type compressedWriter struct {
+	io.Writer
+	enc io.Closer
+	f   *File
+}
+
+func (w *compressedWriter) Close() error {
+	err := w.enc.Close()
+	if ferr := w.f.Close(); err == nil {
+		err = ferr
+	}
+
+	return err
+}
+
+// OpenCompressed opens the named file for reading and transparently
+// decompresses it. The format is detected from the leading magic bytes, so
+// the file name does not matter; files in no known format are read as is.
+// gzip and bzip2 are built in and more formats can be added with
+// RegisterCodec; recognized formats without a decompressor, such as zstd
+// until a codec for it is registered, return an error matching
+// errors.ErrUnsupported.
+//
+// Parameters:
+//   - filename: the name of the file
+func OpenCompressed(filename string) (io.ReadCloser, error) {
+	done := instrument("open", filename)
+	rc, err := openCompressed(filename)
+	done(0, err)
+	return rc, err
+}
+
+func openCompressed(filename string) (io.ReadCloser, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(f)
+	head, _ := br.Peek(maxMagicLen)
+	c := codecByMagic(head)
+	if c == nil {
+		return &compressedReader{Reader: br, f: f}, nil
+	}
+
+	if c.NewReader == nil {
+		f.Close()
+		return nil, unsupportedCodec("open", filename, c)
+	}
+
+	dec, err := c.NewReader(br)
+	if err != nil {
+		f.Close()
+		return nil, &os.PathError{Op: "open", Path: filename, Err: err}
+	}
+
+	return &compressedReader{Reader: dec, dec: dec, f: f}, nil
+}
+
+// CreateCompressed creates or truncates the named file and returns a writer
+// that compresses according to the file's extension, such as ".gz". Files
+// with no known extension are written as is. The file is complete only once
+// the writer is closed. If the file does not exist, it is created with
+// permissions perm (before umask).
+//
+// Parameters:
+//   - filename: the name of the file
+//   - perm: the file permissions
+func CreateCompressed(filename string, perm FileMode) (io.WriteCloser, error) {
+	c := codecByExt(filename)
+	if c != nil && c.NewWriter == nil {
+		return nil, unsupportedCodec("create", filename, c)
+	}
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	if c == nil {
+		return f, nil
+	}
+
+	enc, err := c.NewWriter(f)
+	if err != nil {
+		f.Close()
+		return nil, &os.PathError{Op: "create", Path: filename, Err: err}
+	}
+
+	return &compressedWriter{Writer: enc, enc: enc, f: f}, nil
+}
+
+// ReadFileAuto reads the named file like ReadFile, decompressing it if it is
+// in a compressed format, see OpenCompressed.
+//
+// Parameters:
+//   - filename: the name of the file
+func ReadFileAuto(filename string) ([]byte, error) {
+	r, err := OpenCompressed(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, &os.PathError{Op: "read", Path: filename, Err: err}
+	}
+
+	return data, nil
+}
+
+// WriteFileGzip writes data gzip compressed to the named file, creating it if
+// necessary. If the file does not exist, it is created with permissions perm
+// (before umask); otherwise it is truncated first, without changing
+// permissions.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - data: the uncompressed data to write
+//   - perm: the file permissions
+func WriteFileGzip(filename string, data []byte, perm FileMode) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(f)
+	if _, err := zw.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
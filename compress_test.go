@@ -0,0 +1,110 @@
+package xfs_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFileAuto(t *testing.T) {
+	dir := t.TempDir()
+	gz := filepath.Join(dir, "log.gz")
+	assert.NoError(t, xfs.WriteFileGzip(gz, []byte("compressed"), 0644))
+
+	raw, _ := os.ReadFile(gz)
+	assert.NotEqual(t, "compressed", string(raw))
+
+	data, err := xfs.ReadFileAuto(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, "compressed", string(data))
+
+	// Detection uses the content, not the name.
+	renamed := filepath.Join(dir, "log.txt")
+	os.Rename(gz, renamed)
+	data, err = xfs.ReadFileAuto(renamed)
+	assert.NoError(t, err)
+	assert.Equal(t, "compressed", string(data))
+
+	plain := filepath.Join(dir, "plain")
+	os.WriteFile(plain, []byte("x"), 0644)
+	data, err = xfs.ReadFileAuto(plain)
+	assert.NoError(t, err)
+	assert.Equal(t, "x", string(data))
+
+	zst := filepath.Join(dir, "data.zst")
+	os.WriteFile(zst, []byte{0x28, 0xb5, 0x2f, 0xfd, 0, 0}, 0644)
+	_, err = xfs.ReadFileAuto(zst)
+	assert.True(t, errors.Is(err, errors.ErrUnsupported))
+}
+
+func TestCreateCompressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.gz")
+	w, err := xfs.CreateCompressed(path, 0644)
+	assert.NoError(t, err)
+	io.WriteString(w, "streamed")
+	assert.NoError(t, w.Close())
+
+	r, err := xfs.OpenCompressed(path)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "streamed", string(data))
+
+	_, err = xfs.CreateCompressed(filepath.Join(t.TempDir(), "out.bz2"), 0644)
+	assert.True(t, errors.Is(err, errors.ErrUnsupported))
+}
+
+// upperCodec is a toy format that stores data upper cased behind a header.
+var upperCodec = xfs.Codec{
+	Name:       "upper",
+	Extensions: []string{".upper"},
+	Magic:      []byte("UPPER:"),
+	NewReader: func(r io.Reader) (io.ReadCloser, error) {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return io.NopCloser(strings.NewReader(strings.ToLower(strings.TrimPrefix(string(data), "UPPER:")))), nil
+	},
+	NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return &upperWriter{w: w}, nil
+	},
+}
+
+type upperWriter struct {
+	w   io.Writer
+	buf strings.Builder
+}
+
+func (u *upperWriter) Write(p []byte) (int, error) {
+	return u.buf.Write(p)
+}
+
+func (u *upperWriter) Close() error {
+	_, err := io.WriteString(u.w, "UPPER:"+strings.ToUpper(u.buf.String()))
+	return err
+}
+
+func TestRegisterCodec(t *testing.T) {
+	xfs.RegisterCodec(upperCodec)
+	path := filepath.Join(t.TempDir(), "data.upper")
+
+	w, err := xfs.CreateCompressed(path, 0644)
+	assert.NoError(t, err)
+	io.WriteString(w, "hello")
+	assert.NoError(t, w.Close())
+
+	raw, _ := os.ReadFile(path)
+	assert.Equal(t, "UPPER:HELLO", string(raw))
+
+	data, err := xfs.ReadFileAuto(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
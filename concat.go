@@ -0,0 +1,118 @@
+package xfs
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// errConcatSelf is returned when ConcatFilesOpt is asked to append a file
+// to itself.
+var errConcatSelf = errors.New("source is the append destination")
+
+// ConcatOptions controls how ConcatFilesOpt assembles its destination.
+type ConcatOptions struct {
+	// Separator is written between two consecutive sources. It is not
+	// written before the first or after the last source.
+	Separator []byte
+	// Append appends the sources to an existing destination instead of
+	// replacing it. The separator is not written before the first source.
+	Append bool
+	// Perm is the permissions of a newly created destination (before umask).
+	// Zero means 0644.
+	Perm FileMode
+}
+
+// ConcatFiles streams the source files, in order, into the destination file.
+// The destination is replaced atomically, so a failure leaves it untouched.
+//
+// Parameters:
+//   - dst: the name of the destination file
+//   - srcs: the names of the source files
+func ConcatFiles(dst string, srcs ...string) error {
+	return ConcatFilesOpt(dst, srcs, nil)
+}
+
+// ConcatFilesOpt streams the source files, in order, into the destination
+// file, optionally separating them and appending to the destination.
+//
+// Unless Append is set, the destination is replaced atomically and may be one
+// of the sources. In append mode the data is written in place, so a failure
+// can leave a partial append behind, and the destination must not be one of
+// the sources.
+//
+// Parameters:
+//   - dst: the name of the destination file
+//   - srcs: the names of the source files
+//   - opts: the concatenation options, nil uses the defaults
+func ConcatFilesOpt(dst string, srcs []string, opts *ConcatOptions) error {
+	o := ConcatOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Perm == 0 {
+		o.Perm = 0644
+	}
+
+	if !o.Append {
+		w, err := NewAtomicWriter(dst, o.Perm, nil)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+
+		if err := concat(w, srcs, o.Separator); err != nil {
+			return err
+		}
+
+		return w.Commit()
+	}
+
+	if dstInfo, err := os.Stat(dst); err == nil {
+		for _, src := range srcs {
+			if info, err := os.Stat(src); err == nil && os.SameFile(info, dstInfo) {
+				return &os.PathError{Op: "concat", Path: src, Err: errConcatSelf}
+			}
+		}
+	}
+
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_APPEND, o.Perm)
+	if err != nil {
+		return err
+	}
+
+	if err := concat(f, srcs, o.Separator); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func concat(w io.Writer, srcs []string, sep []byte) error {
+	for i, src := range srcs {
+		if i > 0 && len(sep) > 0 {
+			if _, err := w.Write(sep); err != nil {
+				return err
+			}
+		}
+
+		if err := concatFile(w, src); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func concatFile(w io.Writer, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,38 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcatFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	dst := filepath.Join(dir, "dst")
+	os.WriteFile(a, []byte("one"), 0644)
+	os.WriteFile(b, []byte("two"), 0644)
+
+	assert.NoError(t, xfs.ConcatFiles(dst, a, b))
+	data, _ := os.ReadFile(dst)
+	assert.Equal(t, "onetwo", string(data))
+
+	assert.NoError(t, xfs.ConcatFilesOpt(dst, []string{a, b}, &xfs.ConcatOptions{Separator: []byte("\n")}))
+	data, _ = os.ReadFile(dst)
+	assert.Equal(t, "one\ntwo", string(data))
+
+	assert.NoError(t, xfs.ConcatFilesOpt(dst, []string{a}, &xfs.ConcatOptions{Append: true}))
+	data, _ = os.ReadFile(dst)
+	assert.Equal(t, "one\ntwoone", string(data))
+
+	assert.Error(t, xfs.ConcatFilesOpt(dst, []string{dst}, &xfs.ConcatOptions{Append: true}))
+
+	err := xfs.ConcatFiles(dst, a, filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	data, _ = os.ReadFile(dst)
+	assert.Equal(t, "one\ntwoone", string(data))
+}
@@ -0,0 +1,216 @@
+package xfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// FileKind is a coarse classification of a file's content.
+type FileKind string
+
+const (
+	KindUnknown    FileKind = "unknown"
+	KindEmpty      FileKind = "empty"
+	KindText       FileKind = "text"
+	KindImage      FileKind = "image"
+	KindAudio      FileKind = "audio"
+	KindVideo      FileKind = "video"
+	KindArchive    FileKind = "archive"
+	KindDocument   FileKind = "document"
+	KindExecutable FileKind = "executable"
+	KindFont       FileKind = "font"
+)
+
+// sniffLen is the number of bytes read to detect the content type.
+const sniffLen = 512
+
+// binarySniffLen is the number of bytes inspected by IsBinaryFile.
+const binarySniffLen = 8 * 1024
+
+type magic struct {
+	offset int
+	sig    []byte
+	mime   string
+}
+
+// magics complements http.DetectContentType with formats it does not know.
+var magics = []magic{
+	{0, []byte("\x7fELF"), "application/x-elf"},
+	{0, []byte("\xfe\xed\xfa\xce"), "application/x-mach-binary"},
+	{0, []byte("\xfe\xed\xfa\xcf"), "application/x-mach-binary"},
+	{0, []byte("\xce\xfa\xed\xfe"), "application/x-mach-binary"},
+	{0, []byte("\xcf\xfa\xed\xfe"), "application/x-mach-binary"},
+	{0, []byte("\xca\xfe\xba\xbe"), "application/x-mach-binary"},
+	{0, []byte("\x28\xb5\x2f\xfd"), "application/zstd"},
+	{0, []byte("BZh"), "application/x-bzip2"},
+	{0, []byte("\xfd7zXZ\x00"), "application/x-xz"},
+	{0, []byte("7z\xbc\xaf\x27\x1c"), "application/x-7z-compressed"},
+	{257, []byte("ustar"), "application/x-tar"},
+	{0, []byte("\x00asm"), "application/wasm"},
+	{0, []byte("SQLite format 3\x00"), "application/vnd.sqlite3"},
+}
+
+// DetectContentType reads at most the first 512 bytes of the named file and
+// returns its MIME type. It recognizes everything http.DetectContentType does
+// plus common binaries (ELF, Mach-O, PE) and archives (tar, bzip2, xz, zstd,
+// 7z). Unrecognized content is reported as "application/octet-stream".
+//
+// Parameters:
+//   - filename: the name of the file
+func DetectContentType(filename string) (string, error) {
+	head, err := readHead(filename, sniffLen)
+	if err != nil {
+		return "", err
+	}
+
+	return detectContentType(head), nil
+}
+
+// Kind reads at most the first 512 bytes of the named file and returns a
+// friendly classification of its content such as KindImage or KindArchive.
+//
+// Parameters:
+//   - filename: the name of the file
+func Kind(filename string) (FileKind, error) {
+	head, err := readHead(filename, sniffLen)
+	if err != nil {
+		return "", err
+	}
+
+	if len(head) == 0 {
+		return KindEmpty, nil
+	}
+
+	return kindOf(detectContentType(head)), nil
+}
+
+// IsBinaryFile reports whether the named file appears to contain binary data.
+// It inspects at most the first 8 KiB: content containing a NUL byte is binary,
+// valid UTF-8 is text, and anything else is binary when more than 30% of the
+// bytes are control characters. Empty files are not binary.
+//
+// Parameters:
+//   - filename: the name of the file
+func IsBinaryFile(filename string) (bool, error) {
+	head, err := readHead(filename, binarySniffLen)
+	if err != nil {
+		return false, err
+	}
+
+	return isBinary(head), nil
+}
+
+func isBinary(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+
+	// The sample may end in the middle of a multi-byte rune.
+	trimmed := data
+	for i := 0; i < utf8.UTFMax-1 && len(trimmed) > 0; i++ {
+		if utf8.Valid(trimmed) {
+			return false
+		}
+
+		trimmed = trimmed[:len(trimmed)-1]
+	}
+
+	control := 0
+	for _, b := range data {
+		if (b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != '\b') || b == 0x7f {
+			control++
+		}
+	}
+
+	return control*100/len(data) > 30
+}
+
+func readHead(filename string, n int) ([]byte, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, n)
+	read, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	return buf[:read], nil
+}
+
+func detectContentType(head []byte) string {
+	if isPE(head) {
+		return "application/vnd.microsoft.portable-executable"
+	}
+
+	for _, m := range magics {
+		if len(head) >= m.offset+len(m.sig) && bytes.Equal(head[m.offset:m.offset+len(m.sig)], m.sig) {
+			return m.mime
+		}
+	}
+
+	return http.DetectContentType(head)
+}
+
+// isPE reports whether head starts with a DOS header whose e_lfanew field
+// points at a "PE\0\0" signature, so text that happens to start with "MZ"
+// is not mistaken for an executable.
+func isPE(head []byte) bool {
+	if len(head) < 0x40 || !bytes.HasPrefix(head, []byte("MZ")) {
+		return false
+	}
+
+	offset := int64(binary.LittleEndian.Uint32(head[0x3c:]))
+	return offset+4 <= int64(len(head)) && bytes.Equal(head[offset:offset+4], []byte("PE\x00\x00"))
+}
+
+func kindOf(mime string) FileKind {
+	if i := strings.IndexByte(mime, ';'); i >= 0 {
+		mime = mime[:i]
+	}
+
+	switch {
+	case strings.HasPrefix(mime, "text/"):
+		return KindText
+	case strings.HasPrefix(mime, "image/"):
+		return KindImage
+	case strings.HasPrefix(mime, "audio/"):
+		return KindAudio
+	case strings.HasPrefix(mime, "video/"):
+		return KindVideo
+	case strings.HasPrefix(mime, "font/"):
+		return KindFont
+	}
+
+	switch mime {
+	case "application/zip", "application/x-gzip", "application/x-rar-compressed",
+		"application/x-tar", "application/x-bzip2", "application/x-xz",
+		"application/zstd", "application/x-7z-compressed":
+		return KindArchive
+	case "application/pdf", "application/postscript", "application/rtf":
+		return KindDocument
+	case "application/x-elf", "application/x-mach-binary",
+		"application/vnd.microsoft.portable-executable", "application/wasm":
+		return KindExecutable
+	case "application/json", "application/xml":
+		return KindText
+	case "application/vnd.ms-fontobject":
+		return KindFont
+	case "application/ogg":
+		return KindAudio
+	}
+
+	return KindUnknown
+}
@@ -0,0 +1,95 @@
+package xfs_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectContentType(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]struct {
+		data string
+		mime string
+	}{
+		"a.png": {"\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR", "image/png"},
+		"a.elf": {"\x7fELF\x02\x01\x01\x00", "application/x-elf"},
+		"a.pdf": {"%PDF-1.7\n", "application/pdf"},
+		"a.gz":  {"\x1f\x8b\x08\x00\x00\x00", "application/x-gzip"},
+		"a.txt": {"hello world\n", "text/plain; charset=utf-8"},
+	}
+
+	for name, c := range cases {
+		path := filepath.Join(dir, name)
+		xfs.WriteTextFile(path, c.data, 0644)
+
+		mime, err := xfs.DetectContentType(path)
+		assert.NoError(t, err)
+		assert.Equal(t, c.mime, mime, name)
+	}
+
+	_, err := xfs.DetectContentType(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestKind(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]struct {
+		data string
+		kind xfs.FileKind
+	}{
+		"a.png":   {"\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR", xfs.KindImage},
+		"a.exe":   {peHeader(), xfs.KindExecutable},
+		"mz.txt":  {"MZ is not a PE header\n", xfs.KindText},
+		"a.zip":   {"PK\x03\x04\x14\x00", xfs.KindArchive},
+		"a.pdf":   {"%PDF-1.7\n", xfs.KindDocument},
+		"a.txt":   {"hello world\n", xfs.KindText},
+		"a.empty": {"", xfs.KindEmpty},
+	}
+
+	for name, c := range cases {
+		path := filepath.Join(dir, name)
+		xfs.WriteTextFile(path, c.data, 0644)
+
+		kind, err := xfs.Kind(path)
+		assert.NoError(t, err)
+		assert.Equal(t, c.kind, kind, name)
+	}
+}
+
+func peHeader() string {
+	head := make([]byte, 0x84)
+	copy(head, "MZ\x90\x00\x03\x00")
+	head[0x3c] = 0x80
+	copy(head[0x80:], "PE\x00\x00")
+	return string(head)
+}
+
+func TestIsBinaryFile(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]struct {
+		data   string
+		binary bool
+	}{
+		"text.txt":  {"hello\nworld\n", false},
+		"utf8.txt":  {"héllo wörld ✓", false},
+		"latin1":    {"caf\xe9 cr\xe8me", false},
+		"nul.bin":   {"abc\x00def", true},
+		"ctrl.bin":  {"\x01\x02\x03\x04\xff\xfe\x05", true},
+		"empty.txt": {"", false},
+	}
+
+	for name, c := range cases {
+		path := filepath.Join(dir, name)
+		xfs.WriteTextFile(path, c.data, 0644)
+
+		binary, err := xfs.IsBinaryFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, c.binary, binary, name)
+	}
+
+	_, err := xfs.IsBinaryFile(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
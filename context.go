@@ -0,0 +1,148 @@
+package xfs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// contextChunkSize is the amount of data transferred between two checks of
+// the context in ReadFileContext and WriteFileContext.
+const contextChunkSize = 64 * 1024
+
+// ReadFileContext reads the named file like ReadFile, checking ctx between
+// chunks. If ctx is canceled or its deadline passes, ReadFileContext returns
+// at once with an error wrapping ctx.Err(), even if the underlying read is
+// stuck, for example on a hung network mount; the blocked read finishes in
+// the background and its result is discarded.
+//
+// Parameters:
+//   - ctx: the context that bounds the read
+//   - filename: the name of the file
+func ReadFileContext(ctx context.Context, filename string) ([]byte, error) {
+	type result struct {
+		data []byte
+		err  error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		data, err := readFileContext(ctx, filename)
+		done <- result{data, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, &os.PathError{Op: "read", Path: filename, Err: ctx.Err()}
+	}
+}
+
+func readFileContext(ctx context.Context, filename string) ([]byte, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		buf.Grow(int(info.Size()))
+	}
+
+	if err := copyContext(ctx, &buf, f, filename, "read"); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WriteFileContext writes data to the named file atomically, like
+// WriteFileAtomic, checking ctx between chunks. If ctx is canceled or its
+// deadline passes before the file is renamed into place, the temporary file
+// is removed, the destination is left untouched and the error wraps
+// ctx.Err(). As with ReadFileContext, the call returns at once even if a
+// write is stuck; the temporary file is then removed as soon as the write
+// returns. Once the rename has started it is not interrupted: the call waits
+// for it and returns its result, so an error wrapping ctx.Err() always means
+// the destination was not replaced.
+//
+// Parameters:
+//   - ctx: the context that bounds the write
+//   - filename: the name of the file
+//   - data: the data to write
+//   - perm: the file permissions
+func WriteFileContext(ctx context.Context, filename string, data []byte, perm FileMode) error {
+	var state atomic.Int32
+	done := make(chan error, 1)
+	go func() {
+		done <- writeFileContext(ctx, filename, data, perm, &state)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if state.CompareAndSwap(writeRunning, writeAbandoned) {
+			return &os.PathError{Op: "write", Path: filename, Err: ctx.Err()}
+		}
+
+		return <-done
+	}
+}
+
+// States of a WriteFileContext call, handed off between the caller and the
+// goroutine doing the write so that only one of them decides the outcome.
+const (
+	writeRunning int32 = iota
+	writeCommitting
+	writeAbandoned
+)
+
+func writeFileContext(ctx context.Context, filename string, data []byte, perm FileMode, state *atomic.Int32) error {
+	w, err := NewAtomicWriter(filename, perm, nil)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := copyContext(ctx, w, bytes.NewReader(data), filename, "write"); err != nil {
+		return err
+	}
+
+	// Losing the handoff means the caller has already reported ctx.Err().
+	if err := ctx.Err(); err != nil || !state.CompareAndSwap(writeRunning, writeCommitting) {
+		return &os.PathError{Op: "write", Path: filename, Err: ctx.Err()}
+	}
+
+	return w.Commit()
+}
+
+// copyContext copies src to dst in chunks, stopping with an error wrapping
+// ctx.Err() once ctx is done.
+func copyContext(ctx context.Context, dst io.Writer, src io.Reader, filename, op string) error {
+	buf := make([]byte, contextChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return &os.PathError{Op: op, Path: filename, Err: err}
+		}
+
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
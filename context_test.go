@@ -0,0 +1,48 @@
+package xfs_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFileContext(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data")
+	want := bytes.Repeat([]byte("0123456789"), 20000)
+	os.WriteFile(file, want, 0644)
+
+	data, err := xfs.ReadFileContext(context.Background(), file)
+	assert.NoError(t, err)
+	assert.Equal(t, want, data)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = xfs.ReadFileContext(ctx, file)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWriteFileContext(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "data")
+	os.WriteFile(file, []byte("old"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := xfs.WriteFileContext(ctx, file, []byte("new"), 0644)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	data, _ := os.ReadFile(file)
+	assert.Equal(t, "old", string(data))
+
+	assert.NoError(t, xfs.WriteFileContext(context.Background(), file, []byte("new"), 0644))
+	data, _ = os.ReadFile(file)
+	assert.Equal(t, "new", string(data))
+
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+}
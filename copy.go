@@ -0,0 +1,299 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// errSymlinkLoop is returned when following symbolic links while copying a
+// tree leads back into a directory being copied.
+var errSymlinkLoop = errors.New("symbolic link loop")
+
+// SymlinkPolicy decides how CopyDirOpt treats symbolic links in the source
+// tree.
+type SymlinkPolicy int
+
+const (
+	// SymlinkDeref copies the target of a link: the content of a linked file
+	// or the whole tree of a linked directory. Links that lead back into a
+	// directory being copied fail with an error. This is the default and
+	// what CopyDir does.
+	SymlinkDeref SymlinkPolicy = iota
+	// SymlinkCopyLink recreates the link with the same target.
+	SymlinkCopyLink
+	// SymlinkSkip leaves links out of the copy.
+	SymlinkSkip
+)
+
+// CopyOptions controls CopyFileOpt and CopyDirOpt.
+type CopyOptions struct {
+	// Overwrite replaces existing destination files. Without it, existing
+	// files are left untouched.
+	Overwrite bool
+	// UpdateOnly copies a file only if the destination does not exist, or
+	// if the source was modified after the destination or their sizes
+	// differ, like cp --update. Up to date files are skipped without being
+	// read, which makes repeated copies of large, mostly unchanged trees
+	// fast. Outdated files are replaced even if Overwrite is false.
+	UpdateOnly bool
+	// IntoDir makes CopyFileOpt copy into dst, under the base name of src,
+	// when dst is an existing directory, like cp. Without it, a directory
+	// dst is treated as the destination file itself.
+	IntoDir bool
+	// Symlinks decides how CopyDirOpt treats symbolic links below src.
+	Symlinks SymlinkPolicy
+	// Filter restricts the files that are copied. Files it does not match
+	// are skipped; directories are always created.
+	Filter *FileFilter
+	// Deterministic gives every copied file and directory the modification
+	// time returned by SourceDateEpoch, so that copies of the same tree made
+	// at different times are identical, e.g. as input to reproducible
+	// builds. Entries are already copied in lexical order and owned by the
+	// calling user. Symbolic links keep their own times.
+	Deterministic bool
+
+	// copied counts the bytes copied, for the instrumentation of
+	// CopyFileOpt and CopyDirOpt.
+	copied int64
+}
+
+// defaultSourceDateEpoch is used when SOURCE_DATE_EPOCH is not set. It is the
+// earliest time the zip format can represent.
+var defaultSourceDateEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SourceDateEpoch returns the timestamp for reproducible output: the time in
+// the SOURCE_DATE_EPOCH environment variable, in seconds since the Unix
+// epoch, or 1980-01-01 00:00:00 UTC if it is unset or invalid.
+func SourceDateEpoch() time.Time {
+	if v := os.Getenv("SOURCE_DATE_EPOCH"); v != "" {
+		if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(sec, 0).UTC()
+		}
+	}
+
+	return defaultSourceDateEpoch
+}
+
+// CopyFileOpt copies the file from src to dst according to opts. If the file
+// is a symbolic link, it copies the link's target.
+//
+// Parameters:
+//   - src: the source file
+//   - dst: the destination file
+//   - opts: the copy options, nil uses the defaults
+func CopyFileOpt(src, dst string, opts *CopyOptions) error {
+	o := CopyOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	done := instrument("copyfile", src)
+	err := copyFileTo(src, dst, &o)
+	done(o.copied, err)
+	return err
+}
+
+func copyFileTo(src, dst string, o *CopyOptions) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if o.IntoDir && IsDir(dst) {
+		dst = filepath.Join(dst, filepath.Base(src))
+	}
+
+	if err := copyFileOpt(src, dst, info, o); err != nil || !o.Deterministic || !o.Filter.Match(info) {
+		return err
+	}
+
+	epoch := SourceDateEpoch()
+	return os.Chtimes(dst, epoch, epoch)
+}
+
+// CopyDirOpt copies the directory tree rooted at src to dst according to
+// opts, creating directories as needed. Symbolic links below src are handled
+// according to the Symlinks option.
+//
+// Parameters:
+//   - src: the source directory
+//   - dst: the destination directory
+//   - opts: the copy options, nil uses the defaults
+func CopyDirOpt(src, dst string, opts *CopyOptions) error {
+	o := CopyOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	done := instrument("copydir", src)
+	err := copyTree(src, dst, &o)
+	done(o.copied, err)
+	return err
+}
+
+func copyTree(src, dst string, o *CopyOptions) error {
+	if err := copyDir(src, dst, o, nil); err != nil || !o.Deterministic {
+		return err
+	}
+
+	return normalizeTimes(src, dst, o)
+}
+
+// normalizeTimes sets the times of the entries copied from src to dst to
+// SourceDateEpoch. Directories are handled after their contents, as adding
+// entries would change their modification time.
+func normalizeTimes(src, dst string, o *CopyOptions) error {
+	epoch := SourceDateEpoch()
+	var dirs []string
+	err := filepath.Walk(src, func(path string, info FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.IsDir():
+			dirs = append(dirs, target)
+			return nil
+		case info.Mode()&os.ModeSymlink != 0 && o.Symlinks != SymlinkDeref:
+			return nil
+		case info.Mode()&os.ModeSymlink != 0 && IsDir(path):
+			resolved, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return err
+			}
+
+			return normalizeTimes(resolved, target, o)
+		case !o.Filter.Match(info):
+			return nil
+		}
+
+		if err := os.Chtimes(target, epoch, epoch); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chtimes(dirs[i], epoch, epoch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyDir copies the tree rooted at src. chain holds the identities of the
+// directories entered by following links, to detect loops.
+func copyDir(src, dst string, o *CopyOptions, chain []FileIdentity) error {
+	if info, err := os.Stat(src); err == nil {
+		if id, err := FileIDInfo(src, info); err == nil {
+			for _, seen := range chain {
+				if seen == id {
+					return &os.PathError{Op: "copy", Path: src, Err: errSymlinkLoop}
+				}
+			}
+
+			chain = append(chain, id)
+		}
+	}
+
+	return filepath.Walk(src, func(path string, info FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		dstPath := filepath.Join(dst, relPath)
+		if info.IsDir() {
+			return EnsureDir(dstPath, info.Mode())
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 && path != src {
+			return copyLink(path, dstPath, o, chain)
+		}
+
+		return copyFileOpt(path, dstPath, info, o)
+	})
+}
+
+func copyLink(path, dst string, o *CopyOptions, chain []FileIdentity) error {
+	switch o.Symlinks {
+	case SymlinkSkip:
+		return nil
+	case SymlinkCopyLink:
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Lstat(dst); err == nil {
+			if !o.Overwrite {
+				return nil
+			}
+
+			if err := os.Remove(dst); err != nil {
+				return err
+			}
+		}
+
+		return os.Symlink(target, dst)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return copyFileOpt(path, dst, info, o)
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+
+	return copyDir(resolved, dst, o, chain)
+}
+
+func copyFileOpt(src, dst string, info FileInfo, o *CopyOptions) error {
+	if !o.Filter.Match(info) {
+		return nil
+	}
+
+	overwrite := o.Overwrite
+	if o.UpdateOnly {
+		existing, err := os.Stat(dst)
+		if err == nil && !info.ModTime().After(existing.ModTime()) && info.Size() == existing.Size() {
+			return nil
+		}
+
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		overwrite = true
+	}
+
+	n, err := copyFileN(src, dst, info, overwrite)
+	o.copied += n
+	return err
+}
@@ -0,0 +1,122 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyDirUpdateOnly(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	os.WriteFile(filepath.Join(src, "same"), []byte("aaa"), 0644)
+	os.WriteFile(filepath.Join(src, "newer"), []byte("new"), 0644)
+	os.WriteFile(filepath.Join(src, "resized"), []byte("longer"), 0644)
+	os.WriteFile(filepath.Join(src, "added"), []byte("add"), 0644)
+
+	old := time.Now().Add(-time.Hour)
+	os.WriteFile(filepath.Join(dst, "same"), []byte("bbb"), 0644)
+	os.WriteFile(filepath.Join(dst, "newer"), []byte("old"), 0644)
+	os.WriteFile(filepath.Join(dst, "resized"), []byte("short"), 0644)
+	os.Chtimes(filepath.Join(dst, "newer"), old, old)
+	os.Chtimes(filepath.Join(src, "same"), old, old)
+	os.Chtimes(filepath.Join(src, "resized"), old, old)
+
+	assert.NoError(t, xfs.CopyDirOpt(src, dst, &xfs.CopyOptions{UpdateOnly: true}))
+
+	for name, want := range map[string]string{
+		"same":    "bbb",
+		"newer":   "new",
+		"resized": "longer",
+		"added":   "add",
+	} {
+		data, err := os.ReadFile(filepath.Join(dst, name))
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(data), name)
+	}
+}
+
+func TestCopyFileIntoDir(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app.conf")
+	dst := filepath.Join(dir, "etc")
+	os.WriteFile(src, []byte("conf"), 0644)
+	os.Mkdir(dst, 0755)
+
+	assert.NoError(t, xfs.CopyFileOpt(src, dst, &xfs.CopyOptions{IntoDir: true}))
+	data, err := os.ReadFile(filepath.Join(dst, "app.conf"))
+	assert.NoError(t, err)
+	assert.Equal(t, "conf", string(data))
+
+	renamed := filepath.Join(dst, "renamed.conf")
+	assert.NoError(t, xfs.CopyFileOpt(src, renamed, &xfs.CopyOptions{IntoDir: true}))
+	assert.FileExists(t, renamed)
+}
+
+func TestCopyDirSymlinks(t *testing.T) {
+	if !xfs.CanSymlink() {
+		t.Skip("symbolic links are not permitted")
+	}
+
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "real"), 0755)
+	os.WriteFile(filepath.Join(src, "real", "file"), []byte("data"), 0644)
+	os.Symlink("real", filepath.Join(src, "dirlink"))
+	os.Symlink(filepath.Join("real", "file"), filepath.Join(src, "filelink"))
+
+	dst := filepath.Join(t.TempDir(), "deref")
+	assert.NoError(t, xfs.CopyDirOpt(src, dst, nil))
+	data, err := os.ReadFile(filepath.Join(dst, "dirlink", "file"))
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+	assert.False(t, xfs.IsSymlink(filepath.Join(dst, "dirlink")))
+	assert.False(t, xfs.IsSymlink(filepath.Join(dst, "filelink")))
+
+	dst = filepath.Join(t.TempDir(), "links")
+	assert.NoError(t, xfs.CopyDirOpt(src, dst, &xfs.CopyOptions{Symlinks: xfs.SymlinkCopyLink}))
+	target, err := os.Readlink(filepath.Join(dst, "dirlink"))
+	assert.NoError(t, err)
+	assert.Equal(t, "real", target)
+
+	dst = filepath.Join(t.TempDir(), "skip")
+	assert.NoError(t, xfs.CopyDirOpt(src, dst, &xfs.CopyOptions{Symlinks: xfs.SymlinkSkip}))
+	assert.NoFileExists(t, filepath.Join(dst, "filelink"))
+	assert.NoDirExists(t, filepath.Join(dst, "dirlink"))
+	assert.FileExists(t, filepath.Join(dst, "real", "file"))
+
+	os.Symlink("..", filepath.Join(src, "real", "loop"))
+	err = xfs.CopyDirOpt(src, filepath.Join(t.TempDir(), "loop"), nil)
+	assert.Error(t, err)
+}
+
+func TestCopyDirDeterministic(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "sub"), 0755)
+	os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(src, "sub", "b.txt"), []byte("b"), 0644)
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	epoch := time.Unix(1700000000, 0)
+	assert.True(t, epoch.Equal(xfs.SourceDateEpoch()))
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	assert.NoError(t, xfs.CopyDirOpt(src, dst, &xfs.CopyOptions{Deterministic: true}))
+
+	for _, name := range []string{".", "sub", "a.txt", filepath.Join("sub", "b.txt")} {
+		info, err := os.Stat(filepath.Join(dst, name))
+		assert.NoError(t, err)
+		assert.True(t, epoch.Equal(info.ModTime()), name)
+	}
+
+	file := filepath.Join(t.TempDir(), "a.txt")
+	assert.NoError(t, xfs.CopyFileOpt(filepath.Join(src, "a.txt"), file, &xfs.CopyOptions{Deterministic: true}))
+	info, _ := os.Stat(file)
+	assert.True(t, epoch.Equal(info.ModTime()))
+
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	assert.Equal(t, 1980, xfs.SourceDateEpoch().Year())
+}
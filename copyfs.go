@@ -0,0 +1,72 @@
+package xfs
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// CopyFS copies the file system src into the directory dst with the same
+// semantics as CopyDir: directories are created as needed and existing files
+// are only overwritten if the overwrite parameter is true. This makes it
+// possible to materialize an embed.FS, a zip.Reader or an fstest.MapFS on
+// disk.
+//
+// File modes are taken from src, but the owner is always granted read and
+// write access (and search access on directories) because file systems such
+// as embed.FS report every entry as read-only. Symbolic links are followed
+// and entries that are neither directories nor regular files return an
+// error wrapping fs.ErrInvalid.
+//
+// Parameters:
+//   - dst: the destination directory
+//   - src: the file system to copy
+//   - overwrite: whether to overwrite destination files that already exist
+func CopyFS(dst string, src fs.FS, overwrite bool) error {
+	return fs.WalkDir(src, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		dstPath := filepath.Join(dst, filepath.FromSlash(path))
+		info, err := fs.Stat(src, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return EnsureDir(dstPath, info.Mode().Perm()|0700)
+		}
+
+		if !info.Mode().IsRegular() {
+			return &os.PathError{Op: "copyfs", Path: path, Err: fs.ErrInvalid}
+		}
+
+		return copyFSFile(src, path, dstPath, info.Mode().Perm()|0600, overwrite)
+	})
+}
+
+func copyFSFile(src fs.FS, path, dst string, perm FileMode, overwrite bool) error {
+	if Exists(dst) && !overwrite {
+		return nil
+	}
+
+	srcFile, err := src.Open(path)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	if _, err := io.Copy(dstFile, srcFile); err != nil {
+		return err
+	}
+
+	return os.Chmod(dst, perm)
+}
@@ -0,0 +1,37 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyFS(t *testing.T) {
+	src := fstest.MapFS{
+		"a.txt":        {Data: []byte("a"), Mode: 0444},
+		"dir/b.txt":    {Data: []byte("b"), Mode: 0644},
+		"dir/sub/c.sh": {Data: []byte("c"), Mode: 0755},
+	}
+	dst := t.TempDir()
+
+	assert.NoError(t, xfs.CopyFS(dst, src, false))
+
+	data, err := os.ReadFile(filepath.Join(dst, "dir", "sub", "c.sh"))
+	assert.NoError(t, err)
+	assert.Equal(t, "c", string(data))
+
+	os.WriteFile(filepath.Join(dst, "dir", "b.txt"), []byte("changed"), 0644)
+	assert.NoError(t, xfs.CopyFS(dst, src, false))
+	data, _ = os.ReadFile(filepath.Join(dst, "dir", "b.txt"))
+	assert.Equal(t, "changed", string(data))
+
+	assert.NoError(t, xfs.CopyFS(dst, src, true))
+	data, _ = os.ReadFile(filepath.Join(dst, "dir", "b.txt"))
+	assert.Equal(t, "b", string(data))
+	data, _ = os.ReadFile(filepath.Join(dst, "a.txt"))
+	assert.Equal(t, "a", string(data))
+}
@@ -0,0 +1,484 @@
+package xfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrJobCanceled is returned by CopyJob.Wait for a job that was canceled.
+var ErrJobCanceled = errors.New("xfs: copy job canceled")
+
+// errJobPaused stops a running job so that its worker is released.
+var errJobPaused = errors.New("copy job paused")
+
+// copyQueueChunk is the amount of data copied between checks for pause,
+// cancellation and the bandwidth budget.
+const copyQueueChunk = 256 << 10
+
+// JobState is the state of a CopyJob.
+type JobState int
+
+const (
+	// JobQueued waits for a worker.
+	JobQueued JobState = iota
+	// JobRunning is being copied.
+	JobRunning
+	// JobPaused waits for Resume without occupying a worker.
+	JobPaused
+	// JobDone was copied successfully.
+	JobDone
+	// JobFailed stopped with an error.
+	JobFailed
+	// JobCanceled was canceled.
+	JobCanceled
+)
+
+// String returns the name of the state.
+func (s JobState) String() string {
+	switch s {
+	case JobQueued:
+		return "queued"
+	case JobRunning:
+		return "running"
+	case JobPaused:
+		return "paused"
+	case JobDone:
+		return "done"
+	case JobFailed:
+		return "failed"
+	case JobCanceled:
+		return "canceled"
+	}
+
+	return "unknown"
+}
+
+// CopyQueueOptions controls a CopyQueue.
+type CopyQueueOptions struct {
+	// Workers is the number of jobs copied at the same time. Zero means 2.
+	Workers int
+	// BytesPerSecond limits the combined copy rate of all jobs. Zero means
+	// no limit.
+	BytesPerSecond int64
+}
+
+// CopyQueue copies files and directory trees in the background. Jobs are
+// started by priority, share a fixed number of workers and an optional
+// bandwidth budget, and can be paused, resumed and canceled while they wait
+// or run, which is what file managers and backup agents need beyond one-shot
+// Copy calls.
+type CopyQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []*CopyJob
+	seq     int
+	closed  bool
+	limiter *rateLimiter
+	wg      sync.WaitGroup
+}
+
+// CopyJob is a copy enqueued on a CopyQueue.
+type CopyJob struct {
+	// Src is the file or directory being copied.
+	Src string
+	// Dst is the destination.
+	Dst string
+	// Priority orders the queued jobs: higher values start first and jobs
+	// of equal priority start in the order they were enqueued.
+	Priority int
+
+	q         *CopyQueue
+	seq       int
+	state     JobState
+	err       error
+	pause     bool
+	cancel    bool
+	filesDone int
+	copied    int64
+	done      chan struct{}
+}
+
+// NewCopyQueue starts a copy queue and its workers. Close stops it.
+//
+// Parameters:
+//   - opts: the queue options, nil uses the defaults
+func NewCopyQueue(opts *CopyQueueOptions) *CopyQueue {
+	o := CopyQueueOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Workers <= 0 {
+		o.Workers = 2
+	}
+
+	q := &CopyQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	if o.BytesPerSecond > 0 {
+		q.limiter = &rateLimiter{rate: o.BytesPerSecond}
+	}
+
+	for i := 0; i < o.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	return q
+}
+
+// Enqueue adds a job copying src to dst with the given priority. Like
+// CopyFile and CopyDir with overwrite set, existing destination files are
+// replaced. Symbolic links below a directory src are recreated, not
+// followed. Jobs enqueued after Close are canceled at once.
+//
+// Parameters:
+//   - src: the file or directory to copy
+//   - dst: the destination
+//   - priority: the priority of the job
+func (q *CopyQueue) Enqueue(src, dst string, priority int) *CopyJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.seq++
+	job := &CopyJob{Src: src, Dst: dst, Priority: priority, q: q, seq: q.seq, done: make(chan struct{})}
+	if q.closed {
+		job.finish(JobCanceled, ErrJobCanceled)
+		return job
+	}
+
+	q.pending = append(q.pending, job)
+	q.cond.Broadcast()
+	return job
+}
+
+// Close cancels the jobs that have not finished and waits for the workers
+// to stop.
+func (q *CopyQueue) Close() error {
+	q.mu.Lock()
+	q.closed = true
+	for _, job := range q.pending {
+		job.finish(JobCanceled, ErrJobCanceled)
+	}
+
+	q.pending = nil
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	q.wg.Wait()
+	return nil
+}
+
+func (q *CopyQueue) work() {
+	defer q.wg.Done()
+	for {
+		q.mu.Lock()
+		job := q.next()
+		for job == nil && !q.closed {
+			q.cond.Wait()
+			job = q.next()
+		}
+
+		if job == nil {
+			q.mu.Unlock()
+			return
+		}
+
+		job.state = JobRunning
+		q.mu.Unlock()
+
+		err := job.run()
+
+		q.mu.Lock()
+		q.settle(job, err)
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	}
+}
+
+// settle records the outcome of a run of job. A job stopped by a pause is
+// parked, unless Resume or Cancel were called while it was stopping, which
+// only cleared or set a flag as the job was still running. The caller holds
+// q.mu.
+func (q *CopyQueue) settle(job *CopyJob, err error) {
+	switch {
+	case err == errJobPaused && !q.closed && !job.cancel:
+		job.state = JobPaused
+		if !job.pause {
+			job.state = JobQueued
+		}
+
+		job.pause = false
+		q.pending = append(q.pending, job)
+	case err == errJobPaused || err == ErrJobCanceled:
+		job.finish(JobCanceled, ErrJobCanceled)
+	case err != nil:
+		job.finish(JobFailed, err)
+	default:
+		job.finish(JobDone, nil)
+	}
+}
+
+// next removes and returns the queued job with the highest priority, or nil.
+// The caller holds q.mu.
+func (q *CopyQueue) next() *CopyJob {
+	best := -1
+	for i, job := range q.pending {
+		if job.state != JobQueued {
+			continue
+		}
+
+		if best < 0 || job.Priority > q.pending[best].Priority ||
+			(job.Priority == q.pending[best].Priority && job.seq < q.pending[best].seq) {
+			best = i
+		}
+	}
+
+	if best < 0 {
+		return nil
+	}
+
+	job := q.pending[best]
+	q.pending = append(q.pending[:best], q.pending[best+1:]...)
+	return job
+}
+
+// finish moves the job to a final state. The caller holds q.mu.
+func (job *CopyJob) finish(state JobState, err error) {
+	job.state, job.err = state, err
+	close(job.done)
+}
+
+// State returns the current state of the job.
+func (job *CopyJob) State() JobState {
+	job.q.mu.Lock()
+	defer job.q.mu.Unlock()
+	return job.state
+}
+
+// Copied returns the number of bytes copied so far. A file interrupted by a
+// pause is copied again from its start, so the count can exceed the size of
+// the source.
+func (job *CopyJob) Copied() int64 {
+	job.q.mu.Lock()
+	defer job.q.mu.Unlock()
+	return job.copied
+}
+
+// Pause stops the job until Resume. A running job stops at the next chunk
+// and releases its worker; the file it was copying is copied again from the
+// start when the job resumes. Pausing a finished job has no effect.
+func (job *CopyJob) Pause() {
+	job.q.mu.Lock()
+	defer job.q.mu.Unlock()
+
+	switch job.state {
+	case JobQueued:
+		job.state = JobPaused
+	case JobRunning:
+		job.pause = true
+	}
+}
+
+// Resume queues a paused job again, keeping its place among jobs of the
+// same priority.
+func (job *CopyJob) Resume() {
+	job.q.mu.Lock()
+	defer job.q.mu.Unlock()
+
+	job.pause = false
+	if job.state == JobPaused {
+		job.state = JobQueued
+		job.q.cond.Broadcast()
+	}
+}
+
+// Cancel stops the job. A running job stops at the next chunk and removes
+// the partially copied file; files copied before remain.
+func (job *CopyJob) Cancel() {
+	q := job.q
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	switch job.state {
+	case JobQueued, JobPaused:
+		for i, pending := range q.pending {
+			if pending == job {
+				q.pending = append(q.pending[:i], q.pending[i+1:]...)
+				break
+			}
+		}
+
+		job.finish(JobCanceled, ErrJobCanceled)
+	case JobRunning:
+		job.cancel = true
+	}
+}
+
+// Done returns a channel that is closed when the job finishes.
+func (job *CopyJob) Done() <-chan struct{} {
+	return job.done
+}
+
+// Wait waits for the job to finish and returns its error, ErrJobCanceled
+// for a canceled job.
+func (job *CopyJob) Wait() error {
+	<-job.done
+	job.q.mu.Lock()
+	defer job.q.mu.Unlock()
+	return job.err
+}
+
+// checkpoint records progress and reports whether the job must stop.
+func (job *CopyJob) checkpoint(n int64) error {
+	job.q.mu.Lock()
+	defer job.q.mu.Unlock()
+
+	job.copied += n
+	if job.cancel || job.q.closed {
+		return ErrJobCanceled
+	}
+
+	if job.pause {
+		return errJobPaused
+	}
+
+	return nil
+}
+
+// run copies the files of the job, skipping the ones copied before a pause.
+// Entries are visited in lexical order, so the skipped files are the same.
+func (job *CopyJob) run() error {
+	info, err := os.Stat(job.Src)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		if job.filesDone > 0 {
+			return nil
+		}
+
+		if err := job.copyFile(job.Src, job.Dst, info); err != nil {
+			return err
+		}
+
+		job.filesDone++
+		return nil
+	}
+
+	index := 0
+	return filepath.Walk(job.Src, func(path string, info FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(job.Src, path)
+		if err != nil {
+			return err
+		}
+
+		dst := filepath.Join(job.Dst, rel)
+		if info.IsDir() {
+			return EnsureDir(dst, info.Mode())
+		}
+
+		index++
+		if index <= job.filesDone {
+			return nil
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			err = copyLink(path, dst, &CopyOptions{Overwrite: true, Symlinks: SymlinkCopyLink}, nil)
+		} else {
+			err = job.copyFile(path, dst, info)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		job.filesDone++
+		return nil
+	})
+}
+
+// copyFile copies one file in chunks, stopping at a chunk boundary when the
+// job is paused or canceled.
+func (job *CopyJob) copyFile(src, dst string, info FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, copyQueueChunk)
+	for {
+		n, readErr := in.Read(buf)
+		if n > 0 {
+			job.q.limiter.wait(n)
+			if _, err := out.Write(buf[:n]); err != nil {
+				out.Close()
+				return err
+			}
+
+			if err := job.checkpoint(int64(n)); err != nil {
+				out.Close()
+				if err == ErrJobCanceled {
+					os.Remove(dst)
+				}
+
+				return err
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+
+		if readErr != nil {
+			out.Close()
+			return readErr
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Chmod(dst, info.Mode())
+}
+
+// rateLimiter spaces out transfers so that their combined rate stays below
+// rate bytes per second. A nil *rateLimiter does not limit.
+type rateLimiter struct {
+	mu   sync.Mutex
+	rate int64
+	next time.Time
+}
+
+func (l *rateLimiter) wait(n int) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.rate))
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+}
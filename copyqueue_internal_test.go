@@ -0,0 +1,46 @@
+package xfs
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The worker learns about a pause from checkpoint and takes q.mu again
+// only after the copy returned, so Resume and Cancel can run in between
+// while the job is still marked running.
+func TestCopyQueueSettleAfterPause(t *testing.T) {
+	q := &CopyQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	newJob := func() *CopyJob {
+		return &CopyJob{q: q, state: JobRunning, done: make(chan struct{})}
+	}
+	settle := func(job *CopyJob, err error) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.settle(job, err)
+	}
+
+	paused := newJob()
+	paused.Pause()
+	assert.Equal(t, errJobPaused, paused.checkpoint(0))
+	settle(paused, errJobPaused)
+	assert.Equal(t, JobPaused, paused.State())
+
+	resumed := newJob()
+	resumed.Pause()
+	assert.Equal(t, errJobPaused, resumed.checkpoint(0))
+	resumed.Resume()
+	settle(resumed, errJobPaused)
+	assert.Equal(t, JobQueued, resumed.State())
+	assert.Same(t, resumed, q.next())
+
+	canceled := newJob()
+	canceled.Pause()
+	assert.Equal(t, errJobPaused, canceled.checkpoint(0))
+	canceled.Cancel()
+	settle(canceled, errJobPaused)
+	assert.Equal(t, JobCanceled, canceled.State())
+	assert.ErrorIs(t, canceled.Wait(), ErrJobCanceled)
+}
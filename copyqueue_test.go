@@ -0,0 +1,93 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func waitState(t *testing.T, job *xfs.CopyJob, state xfs.JobState) {
+	deadline := time.Now().Add(5 * time.Second)
+	for job.State() != state {
+		if time.Now().After(deadline) {
+			t.Fatalf("job is %v, want %v", job.State(), state)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCopyQueue(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "tree", "sub"), 0755)
+	os.WriteFile(filepath.Join(src, "tree", "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(src, "tree", "sub", "b.txt"), []byte("b"), 0644)
+	os.WriteFile(filepath.Join(src, "file.txt"), []byte("file"), 0644)
+
+	q := xfs.NewCopyQueue(nil)
+	defer q.Close()
+
+	tree := q.Enqueue(filepath.Join(src, "tree"), filepath.Join(dst, "tree"), 0)
+	file := q.Enqueue(filepath.Join(src, "file.txt"), filepath.Join(dst, "file.txt"), 0)
+	missing := q.Enqueue(filepath.Join(src, "missing"), filepath.Join(dst, "missing"), 0)
+
+	assert.NoError(t, tree.Wait())
+	assert.NoError(t, file.Wait())
+	assert.ErrorIs(t, missing.Wait(), os.ErrNotExist)
+	assert.Equal(t, xfs.JobFailed, missing.State())
+
+	data, _ := os.ReadFile(filepath.Join(dst, "tree", "sub", "b.txt"))
+	assert.Equal(t, "b", string(data))
+	data, _ = os.ReadFile(filepath.Join(dst, "file.txt"))
+	assert.Equal(t, "file", string(data))
+	assert.Equal(t, int64(4), file.Copied())
+}
+
+func TestCopyQueueControl(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	big := filepath.Join(src, "big")
+	os.WriteFile(big, make([]byte, 1<<20), 0644)
+	os.WriteFile(filepath.Join(src, "small"), []byte("s"), 0644)
+
+	q := xfs.NewCopyQueue(&xfs.CopyQueueOptions{Workers: 1, BytesPerSecond: 4 << 20})
+	defer q.Close()
+
+	blocker := q.Enqueue(big, filepath.Join(dst, "blocker"), 0)
+	waitState(t, blocker, xfs.JobRunning)
+
+	low := q.Enqueue(filepath.Join(src, "small"), filepath.Join(dst, "low"), 1)
+	high := q.Enqueue(filepath.Join(src, "small"), filepath.Join(dst, "high"), 10)
+	paused := q.Enqueue(filepath.Join(src, "small"), filepath.Join(dst, "paused"), 20)
+	paused.Pause()
+
+	blocker.Pause()
+	waitState(t, blocker, xfs.JobPaused)
+	assert.NoError(t, low.Wait())
+	assert.Equal(t, xfs.JobDone, high.State())
+	assert.Equal(t, xfs.JobPaused, paused.State())
+
+	blocker.Resume()
+	assert.NoError(t, blocker.Wait())
+	info, _ := os.Stat(filepath.Join(dst, "blocker"))
+	assert.Equal(t, int64(1<<20), info.Size())
+
+	canceled := q.Enqueue(big, filepath.Join(dst, "canceled"), 0)
+	waitState(t, canceled, xfs.JobRunning)
+	canceled.Cancel()
+	assert.ErrorIs(t, canceled.Wait(), xfs.ErrJobCanceled)
+	assert.False(t, xfs.Exists(filepath.Join(dst, "canceled")))
+
+	paused.Cancel()
+	assert.ErrorIs(t, paused.Wait(), xfs.ErrJobCanceled)
+	assert.Equal(t, "canceled", paused.State().String())
+
+	q.Close()
+	late := q.Enqueue(big, filepath.Join(dst, "late"), 0)
+	assert.ErrorIs(t, late.Wait(), xfs.ErrJobCanceled)
+}
@@ -0,0 +1,69 @@
+package xfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrExists is returned, wrapped in a *PathError, when a file that must be
+// created already exists. It is the same error as fs.ErrExist, so os.IsExist
+// also reports true for it.
+var ErrExists = fs.ErrExist
+
+// maxUniqueAttempts bounds the number of candidate names tried by UniquePath.
+const maxUniqueAttempts = 10000
+
+// UniquePath reserves the first name in the sequence "report.txt",
+// "report (1).txt", "report (2).txt", ... that does not exist yet and returns
+// it. The name is claimed by creating an empty file with O_CREATE|O_EXCL, so
+// concurrent callers never receive the same path; the caller is expected to
+// overwrite or replace the file.
+//
+// Parameters:
+//   - path: the preferred file name
+func UniquePath(path string) (string, error) {
+	dir, base := filepath.Split(path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	if stem == "" {
+		stem, ext = base, ""
+	}
+
+	for i := 0; i < maxUniqueAttempts; i++ {
+		candidate := path
+		if i > 0 {
+			candidate = filepath.Join(dir, fmt.Sprintf("%s (%d)%s", stem, i, ext))
+		}
+
+		f, err := os.OpenFile(candidate, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			f.Close()
+			return candidate, nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return "", err
+		}
+	}
+
+	return "", &os.PathError{Op: "unique", Path: path, Err: os.ErrExist}
+}
+
+// CreateNew creates the named file with mode perm (before umask) and opens it
+// for reading and writing, failing if the file already exists. The check and
+// the creation are a single atomic operation (O_CREATE|O_EXCL), which makes
+// CreateNew suitable for lock files and one-shot markers.
+//
+// If the file already exists, the returned error satisfies
+// errors.Is(err, ErrExists).
+//
+// Parameters:
+//   - filename: the name of the file
+//   - perm: the file permissions
+func CreateNew(filename string, perm FileMode) (*File, error) {
+	return os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+}
@@ -0,0 +1,44 @@
+package xfs_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniquePath(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "report.txt")
+
+	path, err := xfs.UniquePath(target)
+	assert.NoError(t, err)
+	assert.Equal(t, target, path)
+	assert.True(t, xfs.IsFile(path))
+
+	path, err = xfs.UniquePath(target)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "report (1).txt"), path)
+
+	path, err = xfs.UniquePath(target)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "report (2).txt"), path)
+
+	xfs.EnsureFileDefault(filepath.Join(dir, ".env"))
+	path, err = xfs.UniquePath(filepath.Join(dir, ".env"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".env (1)"), path)
+}
+
+func TestCreateNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.lock")
+
+	f, err := xfs.CreateNew(path, 0644)
+	assert.NoError(t, err)
+	assert.NotNil(t, f)
+	f.Close()
+
+	_, err = xfs.CreateNew(path, 0644)
+	assert.ErrorIs(t, err, xfs.ErrExists)
+}
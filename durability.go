@@ -0,0 +1,89 @@
+package xfs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DurabilityPolicy selects how much WriteFile, WriteTextFile, WriteFileLines,
+// the copy functions, Rename and AtomicWriter flush to stable storage.
+type DurabilityPolicy int
+
+const (
+	// DurabilityNone leaves flushing to the operating system. A power loss
+	// can lose recently written data. This is the default.
+	DurabilityNone DurabilityPolicy = iota
+	// DurabilityDataOnly flushes the content of written files with fsync
+	// before the functions return.
+	DurabilityDataOnly
+	// DurabilityDataAndDir additionally flushes the directories whose entries
+	// changed, so that new files and renames also survive a power loss.
+	DurabilityDataAndDir
+)
+
+// DefaultDurability is the durability policy applied by the package, so that
+// an application can opt into crash-safe writes everywhere without changing
+// its call sites. Set it during initialization, before files are written.
+// The Sync option of AtomicOptions and WriteFileSync flush regardless of it.
+var DefaultDurability = DurabilityNone
+
+// String returns the name of the policy.
+func (p DurabilityPolicy) String() string {
+	switch p {
+	case DurabilityNone:
+		return "none"
+	case DurabilityDataOnly:
+		return "data"
+	case DurabilityDataAndDir:
+		return "data+dir"
+	}
+
+	return "unknown"
+}
+
+// writeFile writes data like os.WriteFile and flushes according to policy.
+func writeFile(filename string, data []byte, perm FileMode, policy DurabilityPolicy) error {
+	if policy == DurabilityNone {
+		return os.WriteFile(filename, data, perm)
+	}
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return syncParent(filename, policy)
+}
+
+// syncData flushes the content of f if the policy asks for it.
+func syncData(f *File, policy DurabilityPolicy) error {
+	if policy < DurabilityDataOnly {
+		return nil
+	}
+
+	return f.Sync()
+}
+
+// syncParent flushes the directory containing filename if the policy asks
+// for it.
+func syncParent(filename string, policy DurabilityPolicy) error {
+	if policy < DurabilityDataAndDir {
+		return nil
+	}
+
+	return SyncDir(filepath.Dir(filename))
+}
@@ -0,0 +1,39 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultDurability(t *testing.T) {
+	defer func(p xfs.DurabilityPolicy) { xfs.DefaultDurability = p }(xfs.DefaultDurability)
+
+	for _, policy := range []xfs.DurabilityPolicy{xfs.DurabilityDataOnly, xfs.DurabilityDataAndDir} {
+		xfs.DefaultDurability = policy
+		dir := t.TempDir()
+		a := filepath.Join(dir, "a")
+		b := filepath.Join(dir, "b")
+		c := filepath.Join(dir, "c")
+
+		assert.NoError(t, xfs.WriteFile(a, []byte("data"), 0644), policy.String())
+		assert.NoError(t, xfs.CopyFile(a, b, false), policy.String())
+		assert.NoError(t, xfs.Rename(b, c), policy.String())
+		assert.NoError(t, xfs.WriteFileAtomic(b, []byte("atomic"), 0644, nil), policy.String())
+
+		data, _ := os.ReadFile(c)
+		assert.Equal(t, "data", string(data))
+		data, _ = os.ReadFile(b)
+		assert.Equal(t, "atomic", string(data))
+	}
+}
+
+func TestDurabilityPolicyString(t *testing.T) {
+	assert.Equal(t, "none", xfs.DurabilityNone.String())
+	assert.Equal(t, "data", xfs.DurabilityDataOnly.String())
+	assert.Equal(t, "data+dir", xfs.DurabilityDataAndDir.String())
+	assert.Equal(t, "unknown", xfs.DurabilityPolicy(9).String())
+}
@@ -0,0 +1,201 @@
+package xfs
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrDecrypt is returned when an encrypted file cannot be decrypted, because
+// the key or passphrase is wrong or the file was modified.
+var ErrDecrypt = errors.New("xfs: decryption failed")
+
+// errNotEncrypted is returned for files without the encrypted file header.
+var errNotEncrypted = errors.New("not an encrypted file")
+
+// errBadEncryptionKey is returned for an EncryptionKey with both or neither
+// of Key and Passphrase set.
+var errBadEncryptionKey = errors.New("exactly one of key and passphrase must be set")
+
+// encMagic starts every file written by WriteFileEncrypted.
+var encMagic = []byte("xfsenc\x01")
+
+const (
+	kdfNone   = 0
+	kdfScrypt = 1
+
+	encSaltLen = 16
+
+	// scrypt parameters for new files, stored in the header so they can be
+	// raised later without breaking existing files.
+	scryptLogN = 15
+	scryptR    = 8
+	scryptP    = 1
+
+	// Limits on the scrypt parameters read from a file, so that a damaged
+	// or hostile header cannot make ReadFileEncrypted panic or allocate
+	// gigabytes.
+	scryptMaxLogN = 20
+	scryptMaxRP   = 32
+	scryptMaxMem  = 1 << 30
+)
+
+// EncryptionKey is the secret used by WriteFileEncrypted and
+// ReadFileEncrypted. Exactly one of Key and Passphrase must be set.
+type EncryptionKey struct {
+	// Key is a raw AES key of 16, 24 or 32 bytes.
+	Key []byte
+	// Passphrase is stretched into a 32 byte key with scrypt and a random
+	// salt stored in the file.
+	Passphrase string
+}
+
+// WriteFileEncrypted encrypts data with AES-GCM and writes it atomically to
+// the named file, see WriteFileAtomic. The file records how the key was
+// derived, so ReadFileEncrypted needs only the same key or passphrase. Since
+// the file holds secrets, perm should usually be 0600.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - data: the plaintext to encrypt
+//   - perm: the file permissions
+//   - key: the key or passphrase
+func WriteFileEncrypted(filename string, data []byte, perm FileMode, key EncryptionKey) error {
+	header := bytes.NewBuffer(append([]byte(nil), encMagic...))
+	var aesKey []byte
+	switch {
+	case key.Passphrase != "" && key.Key == nil:
+		salt := make([]byte, encSaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+
+		header.WriteByte(kdfScrypt)
+		header.Write([]byte{scryptLogN, scryptR, scryptP})
+		header.Write(salt)
+
+		k, err := scrypt.Key([]byte(key.Passphrase), salt, 1<<scryptLogN, scryptR, scryptP, 32)
+		if err != nil {
+			return &os.PathError{Op: "encrypt", Path: filename, Err: err}
+		}
+
+		aesKey = k
+	case key.Passphrase == "" && key.Key != nil:
+		header.WriteByte(kdfNone)
+		aesKey = key.Key
+	default:
+		return &os.PathError{Op: "encrypt", Path: filename, Err: errBadEncryptionKey}
+	}
+
+	aead, err := newGCM(aesKey)
+	if err != nil {
+		return &os.PathError{Op: "encrypt", Path: filename, Err: err}
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	header.Write(nonce)
+	out := aead.Seal(header.Bytes(), nonce, data, header.Bytes())
+	return WriteFileAtomic(filename, out, perm, nil)
+}
+
+// ReadFileEncrypted reads and decrypts a file written by WriteFileEncrypted.
+// A wrong key or passphrase, or a modified file, yields an error wrapping
+// ErrDecrypt.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - key: the key or passphrase the file was written with
+func ReadFileEncrypted(filename string, key EncryptionKey) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, encMagic) || len(data) <= len(encMagic) {
+		return nil, &os.PathError{Op: "decrypt", Path: filename, Err: errNotEncrypted}
+	}
+
+	pos := len(encMagic)
+	kdf := data[pos]
+	pos++
+
+	var aesKey []byte
+	switch kdf {
+	case kdfNone:
+		if key.Key == nil {
+			return nil, &os.PathError{Op: "decrypt", Path: filename, Err: fmt.Errorf("%w: file requires a key", ErrDecrypt)}
+		}
+
+		aesKey = key.Key
+	case kdfScrypt:
+		if len(data) < pos+3+encSaltLen {
+			return nil, &os.PathError{Op: "decrypt", Path: filename, Err: ErrDecrypt}
+		}
+
+		if key.Passphrase == "" {
+			return nil, &os.PathError{Op: "decrypt", Path: filename, Err: fmt.Errorf("%w: file requires a passphrase", ErrDecrypt)}
+		}
+
+		logN, r, p := data[pos], data[pos+1], data[pos+2]
+		salt := data[pos+3 : pos+3+encSaltLen]
+		pos += 3 + encSaltLen
+		if !validScryptParams(int(logN), int(r), int(p)) {
+			return nil, &os.PathError{Op: "decrypt", Path: filename, Err: ErrDecrypt}
+		}
+
+		aesKey, err = scrypt.Key([]byte(key.Passphrase), salt, 1<<logN, int(r), int(p), 32)
+		if err != nil {
+			return nil, &os.PathError{Op: "decrypt", Path: filename, Err: err}
+		}
+	default:
+		return nil, &os.PathError{Op: "decrypt", Path: filename, Err: errNotEncrypted}
+	}
+
+	aead, err := newGCM(aesKey)
+	if err != nil {
+		return nil, &os.PathError{Op: "decrypt", Path: filename, Err: err}
+	}
+
+	if len(data) < pos+aead.NonceSize() {
+		return nil, &os.PathError{Op: "decrypt", Path: filename, Err: ErrDecrypt}
+	}
+
+	nonce := data[pos : pos+aead.NonceSize()]
+	pos += aead.NonceSize()
+	plain, err := aead.Open(nil, nonce, data[pos:], data[:pos])
+	if err != nil {
+		return nil, &os.PathError{Op: "decrypt", Path: filename, Err: ErrDecrypt}
+	}
+
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// validScryptParams reports whether the scrypt parameters of a file header
+// are within the limits ReadFileEncrypted accepts.
+func validScryptParams(logN, r, p int) bool {
+	if logN < 1 || logN > scryptMaxLogN || r < 1 || p < 1 || r*p > scryptMaxRP {
+		return false
+	}
+
+	// scrypt needs 128 * r * N bytes of memory.
+	return 128*r<<logN <= scryptMaxMem
+}
@@ -0,0 +1,78 @@
+package xfs_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileEncryptedKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	key := xfs.EncryptionKey{Key: bytes.Repeat([]byte{7}, 32)}
+
+	assert.NoError(t, xfs.WriteFileEncrypted(path, []byte("secret"), 0600, key))
+	raw, _ := os.ReadFile(path)
+	assert.NotContains(t, string(raw), "secret")
+
+	data, err := xfs.ReadFileEncrypted(path, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", string(data))
+
+	_, err = xfs.ReadFileEncrypted(path, xfs.EncryptionKey{Key: bytes.Repeat([]byte{8}, 32)})
+	assert.ErrorIs(t, err, xfs.ErrDecrypt)
+
+	raw[len(raw)-1] ^= 1
+	os.WriteFile(path, raw, 0600)
+	_, err = xfs.ReadFileEncrypted(path, key)
+	assert.ErrorIs(t, err, xfs.ErrDecrypt)
+}
+
+func TestFileEncryptedPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	key := xfs.EncryptionKey{Passphrase: "correct horse"}
+
+	assert.NoError(t, xfs.WriteFileEncrypted(path, []byte("secret"), 0600, key))
+	data, err := xfs.ReadFileEncrypted(path, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", string(data))
+
+	_, err = xfs.ReadFileEncrypted(path, xfs.EncryptionKey{Passphrase: "wrong"})
+	assert.ErrorIs(t, err, xfs.ErrDecrypt)
+
+	err = xfs.WriteFileEncrypted(path, []byte("x"), 0600, xfs.EncryptionKey{})
+	assert.Error(t, err)
+}
+
+func TestFileEncryptedTamperedHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	key := xfs.EncryptionKey{Passphrase: "correct horse"}
+	assert.NoError(t, xfs.WriteFileEncrypted(path, []byte("secret"), 0600, key))
+	raw, _ := os.ReadFile(path)
+
+	// The scrypt logN, r and p bytes follow the magic and the KDF byte.
+	pos := len("xfsenc\x01") + 1
+	assert.Equal(t, []byte{15, 8, 1}, raw[pos:pos+3])
+	cases := map[string][3]byte{
+		"zero p":    {15, 8, 0},
+		"zero r":    {15, 0, 1},
+		"zero logN": {0, 8, 1},
+		"huge logN": {30, 8, 1},
+		"huge r*p":  {15, 255, 255},
+		"huge mem":  {20, 16, 1},
+	}
+
+	for name, params := range cases {
+		tampered := bytes.Clone(raw)
+		copy(tampered[pos:], params[:])
+		os.WriteFile(path, tampered, 0600)
+
+		assert.NotPanics(t, func() {
+			_, err := xfs.ReadFileEncrypted(path, key)
+			assert.ErrorIs(t, err, xfs.ErrDecrypt, name)
+		}, name)
+	}
+}
@@ -0,0 +1,109 @@
+package xfs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+// EnsureSymlink makes newname a symbolic link to oldname. The link is created
+// if it does not exist and left alone if it already points to oldname. If
+// newname exists with a different target, or is not a symbolic link, it is
+// replaced atomically when replace is set; otherwise an error matching
+// os.ErrExist is returned. Directories are never replaced.
+//
+// Parameters:
+//   - oldname: the target of the link
+//   - newname: the name of the link
+//   - replace: whether to replace a link that points elsewhere
+func EnsureSymlink(oldname, newname string, replace bool) error {
+	info, err := os.Lstat(newname)
+	if os.IsNotExist(err) {
+		return os.Symlink(oldname, newname)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		if target, err := os.Readlink(newname); err == nil && target == oldname {
+			return nil
+		}
+	}
+
+	if !replace || info.IsDir() {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: os.ErrExist}
+	}
+
+	return replaceLink(newname, func(tmp string) error {
+		return os.Symlink(oldname, tmp)
+	})
+}
+
+// EnsureLink makes newname a hard link to oldname. The link is created if it
+// does not exist and left alone if it already refers to the same file as
+// oldname. If newname refers to another file, it is replaced atomically when
+// replace is set; otherwise an error matching os.ErrExist is returned.
+// Directories are never replaced.
+//
+// Parameters:
+//   - oldname: the existing file
+//   - newname: the name of the link
+//   - replace: whether to replace a different file at newname
+func EnsureLink(oldname, newname string, replace bool) error {
+	info, err := os.Lstat(newname)
+	if os.IsNotExist(err) {
+		return os.Link(oldname, newname)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	target, err := os.Lstat(oldname)
+	if err != nil {
+		return err
+	}
+
+	if os.SameFile(info, target) {
+		return nil
+	}
+
+	if !replace || info.IsDir() {
+		return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: os.ErrExist}
+	}
+
+	return replaceLink(newname, func(tmp string) error {
+		return os.Link(oldname, tmp)
+	})
+}
+
+// replaceLink creates a link under a unique name next to newname with create
+// and renames it over newname, so that newname always exists.
+func replaceLink(newname string, create func(tmp string) error) error {
+	dir, base := filepath.Split(newname)
+	buf := make([]byte, 6)
+	for i := 0; i < maxUniqueAttempts; i++ {
+		rand.Read(buf)
+		tmp := filepath.Join(dir, "."+base+".tmp-"+hex.EncodeToString(buf))
+		err := create(tmp)
+		if os.IsExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := os.Rename(tmp, newname); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+
+		return nil
+	}
+
+	return &os.PathError{Op: "createtemp", Path: newname, Err: os.ErrExist}
+}
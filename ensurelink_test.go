@@ -0,0 +1,53 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnsureSymlink(t *testing.T) {
+	if !xfs.CanSymlink() {
+		t.Skip("symbolic links are not supported")
+	}
+
+	dir := t.TempDir()
+	link := filepath.Join(dir, "current")
+
+	assert.NoError(t, xfs.EnsureSymlink("v1", link, false))
+	assert.NoError(t, xfs.EnsureSymlink("v1", link, false))
+
+	err := xfs.EnsureSymlink("v2", link, false)
+	assert.ErrorIs(t, err, os.ErrExist)
+
+	assert.NoError(t, xfs.EnsureSymlink("v2", link, true))
+	target, _ := os.Readlink(link)
+	assert.Equal(t, "v2", target)
+
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+	err = xfs.EnsureSymlink("v2", filepath.Join(dir, "sub"), true)
+	assert.ErrorIs(t, err, os.ErrExist)
+}
+
+func TestEnsureLink(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	link := filepath.Join(dir, "link")
+	os.WriteFile(a, []byte("a"), 0644)
+	os.WriteFile(b, []byte("b"), 0644)
+
+	assert.NoError(t, xfs.EnsureLink(a, link, false))
+	assert.NoError(t, xfs.EnsureLink(a, link, false))
+	assert.ErrorIs(t, xfs.EnsureLink(b, link, false), os.ErrExist)
+
+	assert.NoError(t, xfs.EnsureLink(b, link, true))
+	data, _ := os.ReadFile(link)
+	assert.Equal(t, "b", string(data))
+}
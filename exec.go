@@ -0,0 +1,40 @@
+package xfs
+
+import (
+	"os"
+)
+
+// IsExecutable reports whether the named file is an executable regular file.
+//
+// On Unix this checks whether any of the execute permission bits are set. On
+// Windows, which has no execute bit, a file is executable when its extension
+// is listed in PATHEXT or its content starts with the PE "MZ" signature.
+//
+// Parameters:
+//   - filename: the name of the file
+func IsExecutable(filename string) bool {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return false
+	}
+
+	return isExecutable(filename, info)
+}
+
+// MakeExecutable adds the execute permission bits to the named file, like
+// chmod +x: the user, group and other execute bits are set unless the
+// process umask masks them. Other permission bits are left unchanged.
+//
+// On Windows, which has no execute bit, MakeExecutable only verifies that the
+// file exists.
+//
+// Parameters:
+//   - filename: the name of the file
+func MakeExecutable(filename string) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	return makeExecutable(filename, info)
+}
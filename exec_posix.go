@@ -0,0 +1,20 @@
+//go:build !windows
+
+package xfs
+
+import (
+	"os"
+)
+
+func isExecutable(filename string, info FileInfo) bool {
+	return info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+func makeExecutable(filename string, info FileInfo) error {
+	mode := info.Mode().Perm() | (0111 &^ getUmask())
+	return os.Chmod(filename, mode|info.Mode()&(os.ModeSetuid|os.ModeSetgid|os.ModeSticky))
+}
+
+func executableCandidates(path string) []string {
+	return []string{path}
+}
@@ -0,0 +1,45 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsExecutable(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "run.sh")
+	xfs.WriteTextFile(script, "#!/bin/sh\n", 0644)
+
+	if runtime.GOOS != "windows" {
+		assert.False(t, xfs.IsExecutable(script))
+		os.Chmod(script, 0755)
+		assert.True(t, xfs.IsExecutable(script))
+	}
+
+	assert.False(t, xfs.IsExecutable(dir))
+	assert.False(t, xfs.IsExecutable(filepath.Join(dir, "missing")))
+}
+
+func TestMakeExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no execute permission bits")
+	}
+
+	script := filepath.Join(t.TempDir(), "run.sh")
+	xfs.WriteTextFile(script, "#!/bin/sh\n", 0644)
+	os.Chmod(script, 0640)
+
+	assert.NoError(t, xfs.MakeExecutable(script))
+	assert.True(t, xfs.IsExecutable(script))
+
+	info, _ := os.Stat(script)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm()&0666)
+	assert.Equal(t, os.FileMode(0100), info.Mode().Perm()&0100)
+
+	assert.Error(t, xfs.MakeExecutable(filepath.Join(t.TempDir(), "missing")))
+}
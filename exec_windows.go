@@ -0,0 +1,72 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func isExecutable(filename string, info FileInfo) bool {
+	if !info.Mode().IsRegular() {
+		return false
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, e := range pathExts() {
+		if ext == e {
+			return true
+		}
+	}
+
+	head, err := readHead(filename, 2)
+	return err == nil && string(head) == "MZ"
+}
+
+func makeExecutable(filename string, info FileInfo) error {
+	return nil
+}
+
+// pathExts returns the lower-cased executable extensions listed in PATHEXT.
+func pathExts() []string {
+	value := os.Getenv("PATHEXT")
+	if value == "" {
+		return []string{".com", ".exe", ".bat", ".cmd"}
+	}
+
+	var exts []string
+	for _, e := range strings.Split(strings.ToLower(value), ";") {
+		if e == "" {
+			continue
+		}
+
+		if e[0] != '.' {
+			e = "." + e
+		}
+
+		exts = append(exts, e)
+	}
+
+	return exts
+}
+
+// executableCandidates returns path followed by path with each PATHEXT
+// extension appended, unless path already has one of them.
+func executableCandidates(path string) []string {
+	exts := pathExts()
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range exts {
+		if ext == e {
+			return []string{path}
+		}
+	}
+
+	candidates := make([]string, 0, len(exts))
+	for _, e := range exts {
+		candidates = append(candidates, path+e)
+	}
+
+	return candidates
+}
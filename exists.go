@@ -0,0 +1,63 @@
+package xfs
+
+import (
+	"os"
+)
+
+// ExistsE reports whether the named file or directory exists. Unlike Exists,
+// it distinguishes a missing file, which yields false and a nil error, from a
+// failed check, for example for lack of permission on a parent directory,
+// which yields the error.
+//
+// Parameters:
+//   - filename: the name of the file or directory
+func ExistsE(filename string) (bool, error) {
+	_, err := os.Stat(filename)
+	return statExists(err)
+}
+
+// FileExists reports whether the named file exists and is not a directory.
+// A failed check is returned as an error, see ExistsE.
+//
+// Parameters:
+//   - filename: the name of the file
+func FileExists(filename string) (bool, error) {
+	info, err := os.Stat(filename)
+	ok, err := statExists(err)
+	return ok && !info.IsDir(), err
+}
+
+// DirExists reports whether the named directory exists. A failed check is
+// returned as an error, see ExistsE.
+//
+// Parameters:
+//   - dir: the name of the directory
+func DirExists(dir string) (bool, error) {
+	info, err := os.Stat(dir)
+	ok, err := statExists(err)
+	return ok && info.IsDir(), err
+}
+
+// SymlinkExists reports whether the named file exists and is a symbolic
+// link, whether or not its target exists. A failed check is returned as an
+// error, see ExistsE.
+//
+// Parameters:
+//   - filename: the name of the link
+func SymlinkExists(filename string) (bool, error) {
+	info, err := os.Lstat(filename)
+	ok, err := statExists(err)
+	return ok && info.Mode()&os.ModeSymlink != 0, err
+}
+
+func statExists(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return false, err
+}
@@ -0,0 +1,55 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExistsE(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, []byte("x"), 0644)
+
+	ok, err := xfs.ExistsE(file)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = xfs.ExistsE(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, _ = xfs.FileExists(file)
+	assert.True(t, ok)
+	ok, _ = xfs.FileExists(dir)
+	assert.False(t, ok)
+	ok, _ = xfs.DirExists(dir)
+	assert.True(t, ok)
+	ok, _ = xfs.DirExists(file)
+	assert.False(t, ok)
+	ok, _ = xfs.SymlinkExists(file)
+	assert.False(t, ok)
+
+	if xfs.CanSymlink() {
+		link := filepath.Join(dir, "dangling")
+		os.Symlink("nowhere", link)
+		ok, err = xfs.SymlinkExists(link)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	if runtime.GOOS != "windows" && os.Getuid() != 0 {
+		locked := filepath.Join(dir, "locked")
+		os.Mkdir(locked, 0755)
+		os.WriteFile(filepath.Join(locked, "inner"), nil, 0644)
+		os.Chmod(locked, 0)
+		defer os.Chmod(locked, 0755)
+
+		_, err = xfs.ExistsE(filepath.Join(locked, "inner"))
+		assert.ErrorIs(t, err, os.ErrPermission)
+	}
+}
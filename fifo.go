@@ -0,0 +1,23 @@
+package xfs
+
+import (
+	"os"
+)
+
+// Mkfifo creates a named pipe (FIFO) with the specified name and permission
+// bits (before umask). If there is an error, it will be of type *PathError.
+//
+// Named pipes in the file system are a Unix feature; on Windows use
+// CreateNamedPipe and on other platforms the error wraps
+// errors.ErrUnsupported.
+//
+// Parameters:
+//   - filename: the name of the pipe
+//   - perm: the pipe permissions
+func Mkfifo(filename string, perm FileMode) error {
+	if err := mkfifo(filename, perm); err != nil {
+		return &os.PathError{Op: "mkfifo", Path: filename, Err: err}
+	}
+
+	return nil
+}
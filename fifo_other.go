@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package xfs
+
+import (
+	"errors"
+)
+
+func mkfifo(filename string, perm FileMode) error {
+	return errors.ErrUnsupported
+}
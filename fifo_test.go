@@ -0,0 +1,28 @@
+package xfs_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMkfifo(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Mkfifo is not supported on Windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "pipe")
+	assert.NoError(t, xfs.Mkfifo(path, 0600))
+	assert.True(t, xfs.IsFifo(path))
+	assert.Error(t, xfs.Mkfifo(path, 0600))
+}
+
+func TestIsFifo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	xfs.WriteTextFile(path, "", 0644)
+	assert.False(t, xfs.IsFifo(path))
+	assert.False(t, xfs.IsFifo(filepath.Join(t.TempDir(), "missing")))
+}
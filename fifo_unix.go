@@ -0,0 +1,11 @@
+//go:build unix
+
+package xfs
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+func mkfifo(filename string, perm FileMode) error {
+	return unix.Mkfifo(filename, uint32(perm.Perm()))
+}
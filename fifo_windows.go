@@ -0,0 +1,44 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+func mkfifo(filename string, perm FileMode) error {
+	return errors.ErrUnsupported
+}
+
+// CreateNamedPipe creates the server end of a Windows named pipe and returns
+// it as a *File. The name may be given with or without the `\\.\pipe\`
+// prefix. The pipe is a duplex byte stream accepting a single client; call
+// ConnectNamedPipe from golang.org/x/sys/windows on f.Fd() to wait for it.
+//
+// Parameters:
+//   - name: the name of the pipe e.g. "myapp"
+func CreateNamedPipe(name string) (*File, error) {
+	if !strings.HasPrefix(name, `\\.\pipe\`) {
+		name = `\\.\pipe\` + name
+	}
+
+	p, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, &os.PathError{Op: "createnamedpipe", Path: name, Err: err}
+	}
+
+	h, err := windows.CreateNamedPipe(p,
+		windows.PIPE_ACCESS_DUPLEX|windows.FILE_FLAG_FIRST_PIPE_INSTANCE,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT,
+		1, 64*1024, 64*1024, 0, nil)
+	if err != nil {
+		return nil, &os.PathError{Op: "createnamedpipe", Path: name, Err: err}
+	}
+
+	return os.NewFile(uintptr(h), name), nil
+}
@@ -0,0 +1,67 @@
+package xfs
+
+import (
+	"io"
+	"os"
+)
+
+// CopyFromFile copies the whole content of the open file src to the named
+// file dst, creating or truncating it, and gives dst the mode of src. The
+// content is read with ReadAt, so the offset of src is left unchanged. It
+// serves callers that hold src open, for example under a lock or as an
+// unnamed O_TMPFILE file, and cannot refer to it by name.
+//
+// Parameters:
+//   - dst: the destination file
+//   - src: the open source file
+func CopyFromFile(dst string, src *File) error {
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dstFile, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	if _, err := io.Copy(dstFile, io.NewSectionReader(src, 0, info.Size())); err != nil {
+		return err
+	}
+
+	if err := dstFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Chmod(dst, info.Mode())
+}
+
+// CopyToFile copies the content of the named file src into the open file dst
+// at its current offset and returns the number of bytes copied.
+//
+// Parameters:
+//   - dst: the open destination file
+//   - src: the source file
+func CopyToFile(dst *File, src string) (int64, error) {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer srcFile.Close()
+
+	return io.Copy(dst, srcFile)
+}
+
+// WriteAtOffset copies r into the open file f starting at offset and returns
+// the number of bytes written. The file offset used by Write is not changed,
+// so several ranges of a file can be filled independently. f must not be
+// opened with O_APPEND.
+//
+// Parameters:
+//   - f: the open file
+//   - offset: the offset to start writing at
+//   - r: the data to write
+func WriteAtOffset(f *File, offset int64, r io.Reader) (int64, error) {
+	return io.Copy(io.NewOffsetWriter(f, offset), r)
+}
@@ -0,0 +1,65 @@
+package xfs_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyFromFile(t *testing.T) {
+	dir := t.TempDir()
+	src, _ := os.OpenFile(filepath.Join(dir, "src"), os.O_RDWR|os.O_CREATE, 0600)
+	defer src.Close()
+	src.WriteString("locked content")
+
+	dst := filepath.Join(dir, "dst")
+	assert.NoError(t, xfs.CopyFromFile(dst, src))
+
+	data, _ := os.ReadFile(dst)
+	assert.Equal(t, "locked content", string(data))
+
+	offset, _ := src.Seek(0, io.SeekCurrent)
+	assert.Equal(t, int64(14), offset)
+
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(dst)
+		assert.Equal(t, xfs.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+func TestCopyToFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	os.WriteFile(src, []byte("body"), 0644)
+
+	dst, _ := os.Create(filepath.Join(dir, "dst"))
+	defer dst.Close()
+	dst.WriteString("head:")
+
+	n, err := xfs.CopyToFile(dst, src)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+
+	data, _ := os.ReadFile(dst.Name())
+	assert.Equal(t, "head:body", string(data))
+}
+
+func TestWriteAtOffset(t *testing.T) {
+	f, _ := os.Create(filepath.Join(t.TempDir(), "file"))
+	defer f.Close()
+	f.WriteString("0123456789")
+
+	n, err := xfs.WriteAtOffset(f, 2, strings.NewReader("ab"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	f.WriteString("!")
+	data, _ := os.ReadFile(f.Name())
+	assert.Equal(t, "01ab456789!", string(data))
+}
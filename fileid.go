@@ -0,0 +1,46 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+)
+
+// FileIdentity identifies a file independently of its name: two names with
+// the same identity are hard links to, or symbolic links resolving to, the
+// same file. On Unix it holds the device and inode numbers, on Windows the
+// volume serial number and file index.
+type FileIdentity struct {
+	Dev uint64
+	Ino uint64
+}
+
+// FileID returns the identity of the named file. If the file is a symbolic
+// link, the identity of the link's target is returned. On platforms without
+// file identities the error matches errors.ErrUnsupported.
+//
+// Parameters:
+//   - filename: the name of the file
+func FileID(filename string) (FileIdentity, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return FileIdentity{}, err
+	}
+
+	return FileIDInfo(filename, info)
+}
+
+// FileIDInfo returns the identity of the file described by info, which must
+// come from Stat or Lstat of filename, for example inside a WalkDir callback.
+// On Unix no further system call is made.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - info: the file info of filename
+func FileIDInfo(filename string, info FileInfo) (FileIdentity, error) {
+	dev, ino, ok := fileIdentity(filename, info)
+	if !ok {
+		return FileIdentity{}, &os.PathError{Op: "fileid", Path: filename, Err: &unsupportedError{errors.ErrUnsupported}}
+	}
+
+	return FileIdentity{Dev: dev, Ino: ino}, nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package xfs
+
+// fileIdentity reports that file identities are not available.
+func fileIdentity(filename string, info FileInfo) (dev, ino uint64, ok bool) {
+	return 0, 0, false
+}
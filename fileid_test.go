@@ -0,0 +1,30 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileID(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	c := filepath.Join(dir, "c")
+	os.WriteFile(a, []byte("x"), 0644)
+	os.WriteFile(c, []byte("x"), 0644)
+	os.Link(a, b)
+
+	idA, err := xfs.FileID(a)
+	assert.NoError(t, err)
+	idB, _ := xfs.FileID(b)
+	idC, _ := xfs.FileID(c)
+	assert.Equal(t, idA, idB)
+	assert.NotEqual(t, idA, idC)
+
+	_, err = xfs.FileID(filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package xfs
+
+import (
+	"syscall"
+)
+
+// fileIdentity returns the device and inode numbers of the file described by
+// info, which must come from Stat or Lstat of filename.
+func fileIdentity(filename string, info FileInfo) (dev, ino uint64, ok bool) {
+	if id, ok := info.(interface{ identity() (uint64, uint64) }); ok {
+		dev, ino = id.identity()
+		return dev, ino, true
+	}
+
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+
+	return uint64(st.Dev), uint64(st.Ino), true
+}
@@ -0,0 +1,39 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"io/fs"
+
+	"golang.org/x/sys/windows"
+)
+
+// fileIdentity returns the volume serial number and file index of the named
+// file, which identify it like the device and inode numbers on Unix.
+func fileIdentity(filename string, info FileInfo) (dev, ino uint64, ok bool) {
+	name, err := windows.UTF16PtrFromString(fixLongPath(filename))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	flags := uint32(windows.FILE_FLAG_BACKUP_SEMANTICS)
+	if info.Mode()&fs.ModeSymlink != 0 {
+		flags |= windows.FILE_FLAG_OPEN_REPARSE_POINT
+	}
+
+	h, err := windows.CreateFile(name, 0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil, windows.OPEN_EXISTING, flags, 0)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer windows.CloseHandle(h)
+
+	var data windows.ByHandleFileInformation
+	if err := windows.GetFileInformationByHandle(h, &data); err != nil {
+		return 0, 0, false
+	}
+
+	return uint64(data.VolumeSerialNumber), uint64(data.FileIndexHigh)<<32 | uint64(data.FileIndexLow), true
+}
@@ -0,0 +1,32 @@
+package xfs
+
+import (
+	"time"
+)
+
+// FileTimes holds the timestamps of a file. A time the platform or file
+// system does not record is the zero time.
+type FileTimes struct {
+	// ModTime is the time the content was last modified.
+	ModTime time.Time
+	// AccessTime is the time the file was last read. Many systems update it
+	// lazily or not at all, see the relatime and noatime mount options.
+	AccessTime time.Time
+	// ChangeTime is the time the content or metadata, such as the
+	// permissions, last changed. It is not available on Windows.
+	ChangeTime time.Time
+	// BirthTime is the time the file was created. It is available on Windows,
+	// macOS, FreeBSD, NetBSD and on Linux for file systems that record it.
+	BirthTime time.Time
+}
+
+// StatTimes returns all timestamps of the named file that the platform
+// records, where FileInfo only exposes the modification time. If the file is
+// a symbolic link, the times of the link's target are returned.
+// If there is an error, it will be of type [*PathError].
+//
+// Parameters:
+//   - filename: the name of the file
+func StatTimes(filename string) (*FileTimes, error) {
+	return statTimes(filename)
+}
@@ -0,0 +1,24 @@
+//go:build darwin || freebsd || netbsd
+
+package xfs
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func statTimes(filename string) (*FileTimes, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(filename, &st); err != nil {
+		return nil, &os.PathError{Op: "stat", Path: filename, Err: err}
+	}
+
+	return &FileTimes{
+		ModTime:    time.Unix(st.Mtim.Unix()),
+		AccessTime: time.Unix(st.Atim.Unix()),
+		ChangeTime: time.Unix(st.Ctim.Unix()),
+		BirthTime:  time.Unix(st.Btim.Unix()),
+	}, nil
+}
@@ -0,0 +1,47 @@
+//go:build linux
+
+package xfs
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func statTimes(filename string) (*FileTimes, error) {
+	var stx unix.Statx_t
+	err := unix.Statx(unix.AT_FDCWD, filename, 0, unix.STATX_BASIC_STATS|unix.STATX_BTIME, &stx)
+	if err == unix.ENOSYS {
+		var st unix.Stat_t
+		if err := unix.Stat(filename, &st); err != nil {
+			return nil, &os.PathError{Op: "stat", Path: filename, Err: err}
+		}
+
+		return &FileTimes{
+			ModTime:    time.Unix(st.Mtim.Unix()),
+			AccessTime: time.Unix(st.Atim.Unix()),
+			ChangeTime: time.Unix(st.Ctim.Unix()),
+		}, nil
+	}
+
+	if err != nil {
+		return nil, &os.PathError{Op: "stat", Path: filename, Err: err}
+	}
+
+	t := &FileTimes{
+		ModTime:    statxTime(stx.Mtime),
+		AccessTime: statxTime(stx.Atime),
+		ChangeTime: statxTime(stx.Ctime),
+	}
+
+	if stx.Mask&unix.STATX_BTIME != 0 {
+		t.BirthTime = statxTime(stx.Btime)
+	}
+
+	return t, nil
+}
+
+func statxTime(ts unix.StatxTimestamp) time.Time {
+	return time.Unix(ts.Sec, int64(ts.Nsec))
+}
@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package xfs
+
+import (
+	"os"
+)
+
+func statTimes(filename string) (*FileTimes, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileTimes{ModTime: info.ModTime()}, nil
+}
@@ -0,0 +1,31 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatTimes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(path, []byte("x"), 0644)
+
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	atime := mtime.Add(-time.Hour)
+	os.Chtimes(path, atime, mtime)
+
+	times, err := xfs.StatTimes(path)
+	assert.NoError(t, err)
+	assert.True(t, times.ModTime.Equal(mtime))
+	assert.True(t, times.AccessTime.Equal(atime))
+	if !times.BirthTime.IsZero() {
+		assert.False(t, times.BirthTime.After(time.Now()))
+	}
+
+	_, err = xfs.StatTimes(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,25 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+func statTimes(filename string) (*FileTimes, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &FileTimes{ModTime: info.ModTime()}
+	if d, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		t.AccessTime = time.Unix(0, d.LastAccessTime.Nanoseconds())
+		t.BirthTime = time.Unix(0, d.CreationTime.Nanoseconds())
+	}
+
+	return t, nil
+}
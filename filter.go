@@ -0,0 +1,45 @@
+package xfs
+
+import "time"
+
+// FileFilter selects files by size and modification time. It is used by the
+// Filter field of CopyOptions, SyncOptions and RemoveOptions, so that
+// policies such as "files changed in the last 7 days under 100MB" can be
+// stated instead of written as walk code. Zero fields do not restrict the
+// selection, and a nil *FileFilter matches every file. Directories are never
+// filtered.
+type FileFilter struct {
+	// MinSize is the minimum size in bytes.
+	MinSize int64
+	// MaxSize is the maximum size in bytes. Zero means no limit.
+	MaxSize int64
+	// ModifiedAfter selects files modified after the time.
+	ModifiedAfter time.Time
+	// ModifiedBefore selects files modified before the time.
+	ModifiedBefore time.Time
+}
+
+// Match reports whether the file described by info is selected by the
+// filter.
+//
+// Parameters:
+//   - info: the file info
+func (f *FileFilter) Match(info FileInfo) bool {
+	if f == nil {
+		return true
+	}
+
+	if info.Size() < f.MinSize || (f.MaxSize > 0 && info.Size() > f.MaxSize) {
+		return false
+	}
+
+	if !f.ModifiedAfter.IsZero() && !info.ModTime().After(f.ModifiedAfter) {
+		return false
+	}
+
+	if !f.ModifiedBefore.IsZero() && !info.ModTime().Before(f.ModifiedBefore) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,99 @@
+package xfs_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+// filterTree creates a small file and a large file, both modified a week ago,
+// and a recent small file.
+func filterTree(t *testing.T) string {
+	dir := t.TempDir()
+	old := time.Now().Add(-7 * 24 * time.Hour)
+	os.WriteFile(filepath.Join(dir, "old-small.txt"), []byte("s"), 0644)
+	os.WriteFile(filepath.Join(dir, "old-large.txt"), make([]byte, 100), 0644)
+	os.Chtimes(filepath.Join(dir, "old-small.txt"), old, old)
+	os.Chtimes(filepath.Join(dir, "old-large.txt"), old, old)
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "sub", "new.txt"), []byte("n"), 0644)
+	return dir
+}
+
+func TestFileFilterMatch(t *testing.T) {
+	dir := filterTree(t)
+	small, _ := os.Stat(filepath.Join(dir, "old-small.txt"))
+	large, _ := os.Stat(filepath.Join(dir, "old-large.txt"))
+	recent, _ := os.Stat(filepath.Join(dir, "sub", "new.txt"))
+
+	var none *xfs.FileFilter
+	assert.True(t, none.Match(large))
+
+	f := &xfs.FileFilter{MaxSize: 10}
+	assert.True(t, f.Match(small))
+	assert.False(t, f.Match(large))
+
+	f = &xfs.FileFilter{MinSize: 10}
+	assert.False(t, f.Match(small))
+	assert.True(t, f.Match(large))
+
+	dayAgo := time.Now().Add(-24 * time.Hour)
+	f = &xfs.FileFilter{ModifiedAfter: dayAgo}
+	assert.False(t, f.Match(small))
+	assert.True(t, f.Match(recent))
+
+	f = &xfs.FileFilter{ModifiedBefore: dayAgo}
+	assert.True(t, f.Match(small))
+	assert.False(t, f.Match(recent))
+}
+
+func TestCopyDirFilter(t *testing.T) {
+	src := filterTree(t)
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	filter := &xfs.FileFilter{MaxSize: 10, ModifiedBefore: time.Now().Add(-time.Hour)}
+	assert.NoError(t, xfs.CopyDirOpt(src, dst, &xfs.CopyOptions{Filter: filter}))
+
+	assert.True(t, xfs.IsFile(filepath.Join(dst, "old-small.txt")))
+	assert.False(t, xfs.Exists(filepath.Join(dst, "old-large.txt")))
+	assert.True(t, xfs.IsDir(filepath.Join(dst, "sub")))
+	assert.False(t, xfs.Exists(filepath.Join(dst, "sub", "new.txt")))
+}
+
+func TestRemoveAllOptFilter(t *testing.T) {
+	dir := filterTree(t)
+
+	filter := &xfs.FileFilter{ModifiedBefore: time.Now().Add(-24 * time.Hour)}
+	assert.NoError(t, xfs.RemoveAllOpt(dir, &xfs.RemoveOptions{Filter: filter}))
+
+	names, _ := xfs.ReadDirNames(dir)
+	assert.Equal(t, []string{"sub"}, names)
+	assert.True(t, xfs.IsFile(filepath.Join(dir, "sub", "new.txt")))
+}
+
+func TestSyncFilter(t *testing.T) {
+	src := filterTree(t)
+	b, _ := xfs.NewLocalBackend(t.TempDir())
+	w, _ := b.Writer("old-large.txt")
+	w.Close()
+
+	opts := &xfs.SyncOptions{Delete: true, Filter: &xfs.FileFilter{MaxSize: 10}}
+	assert.NoError(t, xfs.Sync(src, b, opts))
+
+	_, err := b.Stat("old-small.txt")
+	assert.NoError(t, err)
+	_, err = b.Stat("sub/new.txt")
+	assert.NoError(t, err)
+
+	large, err := b.Stat("old-large.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), large.Size)
+
+	_, err = b.Stat("missing.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
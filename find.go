@@ -0,0 +1,385 @@
+package xfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileType identifies the kind of a file system entry for queries such as Find.
+type FileType int
+
+const (
+	// TypeAny matches any kind of entry.
+	TypeAny FileType = iota
+	// TypeFile matches regular files.
+	TypeFile
+	// TypeDir matches directories.
+	TypeDir
+	// TypeSymlink matches symbolic links.
+	TypeSymlink
+)
+
+// String returns the name of the file type.
+func (t FileType) String() string {
+	switch t {
+	case TypeFile:
+		return "file"
+	case TypeDir:
+		return "dir"
+	case TypeSymlink:
+		return "symlink"
+	default:
+		return "any"
+	}
+}
+
+func (t FileType) matches(mode FileMode) bool {
+	switch t {
+	case TypeFile:
+		return mode.IsRegular()
+	case TypeDir:
+		return mode.IsDir()
+	case TypeSymlink:
+		return mode&fs.ModeSymlink != 0
+	default:
+		return true
+	}
+}
+
+// FindQuery is a fluent, find(1)-like query over a directory tree. Create one
+// with Find, chain the filter methods and call Results or All to run it.
+//
+// All filters must match for an entry to be included. The tree is walked in
+// lexical order and symbolic links are not followed.
+type FindQuery struct {
+	root     string
+	names    []string
+	sizes    []sizeFilter
+	before   time.Time
+	after    time.Time
+	typ      FileType
+	minDepth int
+	maxDepth int
+	err      error
+}
+
+type sizeFilter struct {
+	op   string
+	size int64
+}
+
+func (f sizeFilter) matches(size int64) bool {
+	switch f.op {
+	case ">":
+		return size > f.size
+	case ">=":
+		return size >= f.size
+	case "<":
+		return size < f.size
+	case "<=":
+		return size <= f.size
+	default:
+		return size == f.size
+	}
+}
+
+// Find starts a new query rooted at root.
+//
+// Example:
+//
+//	paths, err := xfs.Find("/var/log").Name("*.log").Size(">10MB").Type(xfs.TypeFile).Results()
+//
+// Parameters:
+//   - root: the directory to search
+func Find(root string) *FindQuery {
+	return &FindQuery{root: root, maxDepth: -1}
+}
+
+// Name restricts the results to entries whose base name matches at least one
+// of the given patterns. The pattern syntax is the same as filepath.Match.
+//
+// Parameters:
+//   - patterns: the base name patterns e.g. "*.log"
+func (q *FindQuery) Name(patterns ...string) *FindQuery {
+	for _, p := range patterns {
+		if _, err := filepath.Match(p, ""); err != nil && q.err == nil {
+			q.err = fmt.Errorf("xfs: invalid name pattern %q: %w", p, err)
+		}
+	}
+
+	q.names = append(q.names, patterns...)
+	return q
+}
+
+// Size restricts the results to entries whose size satisfies the expression.
+// The expression is an optional comparison operator (>, >=, <, <=, =) followed
+// by a size such as "10MB", "512KiB" or "100". Without an operator the size
+// must match exactly. Calling Size more than once combines the conditions.
+//
+// Parameters:
+//   - expr: the size expression e.g. ">10MB"
+func (q *FindQuery) Size(expr string) *FindQuery {
+	expr = strings.TrimSpace(expr)
+	op := "="
+	for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(expr, candidate) {
+			op = candidate
+			expr = strings.TrimSpace(expr[len(candidate):])
+			break
+		}
+	}
+
+	size, err := ParseSize(expr)
+	if err != nil {
+		if q.err == nil {
+			q.err = err
+		}
+		return q
+	}
+
+	q.sizes = append(q.sizes, sizeFilter{op: op, size: size})
+	return q
+}
+
+// ModifiedBefore restricts the results to entries last modified before t.
+//
+// Parameters:
+//   - t: the exclusive upper bound for the modification time
+func (q *FindQuery) ModifiedBefore(t time.Time) *FindQuery {
+	q.before = t
+	return q
+}
+
+// ModifiedAfter restricts the results to entries last modified after t.
+//
+// Parameters:
+//   - t: the exclusive lower bound for the modification time
+func (q *FindQuery) ModifiedAfter(t time.Time) *FindQuery {
+	q.after = t
+	return q
+}
+
+// Type restricts the results to entries of the given type.
+//
+// Parameters:
+//   - t: the file type e.g. TypeFile
+func (q *FindQuery) Type(t FileType) *FindQuery {
+	q.typ = t
+	return q
+}
+
+// MinDepth excludes entries less than depth levels below the root. The root
+// itself is at depth 0.
+//
+// Parameters:
+//   - depth: the minimum depth
+func (q *FindQuery) MinDepth(depth int) *FindQuery {
+	q.minDepth = depth
+	return q
+}
+
+// MaxDepth stops descending more than depth levels below the root. The root
+// itself is at depth 0. A negative depth means no limit.
+//
+// Parameters:
+//   - depth: the maximum depth
+func (q *FindQuery) MaxDepth(depth int) *FindQuery {
+	q.maxDepth = depth
+	return q
+}
+
+// All runs the query and returns an iterator over the matching paths. The
+// paths are prefixed with the root, like the paths passed to WalkDir. If an
+// error occurs, it is yielded with an empty path and iteration stops.
+func (q *FindQuery) All() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		if q.err != nil {
+			yield("", q.err)
+			return
+		}
+
+		stopped := false
+		err := filepath.WalkDir(q.root, func(path string, d DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			depth := pathDepth(q.root, path)
+			ok, err := q.matches(path, d, depth)
+			if err != nil {
+				return err
+			}
+
+			if ok && !yield(path, nil) {
+				stopped = true
+				return fs.SkipAll
+			}
+
+			if q.maxDepth >= 0 && d.IsDir() && depth >= q.maxDepth {
+				return fs.SkipDir
+			}
+
+			return nil
+		})
+
+		if err != nil && !stopped {
+			yield("", err)
+		}
+	}
+}
+
+// Results runs the query and returns all matching paths.
+func (q *FindQuery) Results() ([]string, error) {
+	var paths []string
+	for path, err := range q.All() {
+		if err != nil {
+			return paths, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+func (q *FindQuery) matches(path string, d DirEntry, depth int) (bool, error) {
+	if depth < q.minDepth {
+		return false, nil
+	}
+
+	if q.maxDepth >= 0 && depth > q.maxDepth {
+		return false, nil
+	}
+
+	if !q.typ.matches(d.Type()) {
+		return false, nil
+	}
+
+	if len(q.names) > 0 {
+		matched := false
+		for _, pattern := range q.names {
+			if ok, _ := filepath.Match(pattern, d.Name()); ok {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false, nil
+		}
+	}
+
+	if len(q.sizes) == 0 && q.before.IsZero() && q.after.IsZero() {
+		return true, nil
+	}
+
+	info, err := d.Info()
+	if err != nil {
+		return false, err
+	}
+
+	for _, f := range q.sizes {
+		if !f.matches(info.Size()) {
+			return false, nil
+		}
+	}
+
+	if !q.before.IsZero() && !info.ModTime().Before(q.before) {
+		return false, nil
+	}
+
+	if !q.after.IsZero() && !info.ModTime().After(q.after) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func pathDepth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// NewestFile returns the path and FileInfo of the most recently modified
+// regular file directly inside dir whose name matches pattern. An empty
+// pattern matches every file. If no file matches, the error wraps
+// fs.ErrNotExist.
+//
+// Parameters:
+//   - dir: the directory to search
+//   - pattern: the base name pattern e.g. "backup-*.tar.gz"
+func NewestFile(dir, pattern string) (string, FileInfo, error) {
+	return pickFile(dir, pattern, func(a, b FileInfo) bool {
+		return a.ModTime().After(b.ModTime())
+	})
+}
+
+// OldestFile returns the path and FileInfo of the least recently modified
+// regular file directly inside dir whose name matches pattern. An empty
+// pattern matches every file. If no file matches, the error wraps
+// fs.ErrNotExist.
+//
+// Parameters:
+//   - dir: the directory to search
+//   - pattern: the base name pattern e.g. "backup-*.tar.gz"
+func OldestFile(dir, pattern string) (string, FileInfo, error) {
+	return pickFile(dir, pattern, func(a, b FileInfo) bool {
+		return a.ModTime().Before(b.ModTime())
+	})
+}
+
+func pickFile(dir, pattern string, better func(a, b FileInfo) bool) (string, FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var bestName string
+	var best FileInfo
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		if pattern != "" {
+			ok, err := filepath.Match(pattern, entry.Name())
+			if err != nil {
+				return "", nil, err
+			}
+
+			if !ok {
+				continue
+			}
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return "", nil, err
+		}
+
+		if best == nil || better(info, best) {
+			bestName = entry.Name()
+			best = info
+		}
+	}
+
+	if best == nil {
+		return "", nil, &fs.PathError{Op: "find", Path: filepath.Join(dir, pattern), Err: fs.ErrNotExist}
+	}
+
+	return filepath.Join(dir, bestName), best, nil
+}
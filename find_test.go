@@ -0,0 +1,94 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	xfs.MkdirAllDefault(filepath.Join(root, "logs", "old"))
+	xfs.WriteFile(filepath.Join(root, "logs", "app.log"), make([]byte, 2048), 0644)
+	xfs.WriteFile(filepath.Join(root, "logs", "small.log"), []byte("x"), 0644)
+	xfs.WriteFile(filepath.Join(root, "logs", "old", "app.log"), make([]byte, 4096), 0644)
+	xfs.WriteFile(filepath.Join(root, "readme.txt"), []byte("readme"), 0644)
+
+	old := time.Now().Add(-48 * time.Hour)
+	os.Chtimes(filepath.Join(root, "logs", "old", "app.log"), old, old)
+
+	paths, err := xfs.Find(root).Name("*.log").Results()
+	assert.NoError(t, err)
+	assert.Len(t, paths, 3)
+
+	paths, err = xfs.Find(root).Name("*.log").Size(">1KB").Type(xfs.TypeFile).Results()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "logs", "app.log"),
+		filepath.Join(root, "logs", "old", "app.log"),
+	}, paths)
+
+	paths, err = xfs.Find(root).Name("*.log").ModifiedBefore(time.Now().Add(-time.Hour)).Results()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "logs", "old", "app.log")}, paths)
+
+	paths, err = xfs.Find(root).Type(xfs.TypeDir).MinDepth(1).Results()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "logs"), filepath.Join(root, "logs", "old")}, paths)
+
+	paths, err = xfs.Find(root).Name("*.log").MaxDepth(2).Results()
+	assert.NoError(t, err)
+	assert.Len(t, paths, 2)
+
+	_, err = xfs.Find(root).Size(">lots").Results()
+	assert.Error(t, err)
+
+	count := 0
+	for _, err := range xfs.Find(root).Type(xfs.TypeFile).All() {
+		assert.NoError(t, err)
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestNewestFile(t *testing.T) {
+	dir := t.TempDir()
+	for i, name := range []string{"backup-1.tar", "backup-2.tar", "backup-3.tar", "other.txt"} {
+		path := filepath.Join(dir, name)
+		xfs.WriteTextFile(path, name, 0644)
+		mtime := time.Now().Add(time.Duration(i-10) * time.Hour)
+		os.Chtimes(path, mtime, mtime)
+	}
+
+	path, info, err := xfs.NewestFile(dir, "backup-*.tar")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "backup-3.tar"), path)
+	assert.Equal(t, "backup-3.tar", info.Name())
+
+	path, _, err = xfs.NewestFile(dir, "")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "other.txt"), path)
+
+	_, _, err = xfs.NewestFile(dir, "*.zip")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestOldestFile(t *testing.T) {
+	dir := t.TempDir()
+	for i, name := range []string{"backup-1.tar", "backup-2.tar", "backup-3.tar"} {
+		path := filepath.Join(dir, name)
+		xfs.WriteTextFile(path, name, 0644)
+		mtime := time.Now().Add(time.Duration(i-10) * time.Hour)
+		os.Chtimes(path, mtime, mtime)
+	}
+
+	path, info, err := xfs.OldestFile(dir, "backup-*.tar")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "backup-1.tar"), path)
+	assert.Equal(t, "backup-1.tar", info.Name())
+}
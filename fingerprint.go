@@ -0,0 +1,82 @@
+package xfs
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"os"
+	"path/filepath"
+)
+
+// Fingerprint returns an opaque token that changes when the named file
+// probably changed, in the spirit of an HTTP ETag. It combines the size,
+// modification time, mode and file identity (device and inode on Unix,
+// volume serial number and file index on Windows), so replacing a file by
+// renaming another over it changes the token even if size and time match.
+// Unlike HashFile it does not read the content, which makes it cheap enough
+// for polling; a file rewritten with the same size within the timestamp
+// resolution is not detected.
+//
+// The token is stable across calls and processes on the same machine but
+// should not be compared across machines. If the file is a symbolic link,
+// it describes the link's target.
+//
+// Parameters:
+//   - filename: the name of the file
+func Fingerprint(filename string) (string, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	writeFingerprint(h, filename, info)
+	return hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
+// FingerprintDir returns an opaque token for the directory tree rooted at
+// root that changes when an entry is added, removed or renamed, or when the
+// Fingerprint of any entry changes. Symbolic links are not followed.
+//
+// Parameters:
+//   - root: the directory
+func FingerprintDir(root string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(root, func(path string, d DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		h.Write([]byte(filepath.ToSlash(rel)))
+		h.Write([]byte{0})
+		writeFingerprint(h, path, info)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
+func writeFingerprint(h hash.Hash, path string, info FileInfo) {
+	dev, ino, _ := fileIdentity(path, info)
+	var buf [40]byte
+	binary.LittleEndian.PutUint64(buf[0:], uint64(info.Size()))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(info.ModTime().UnixNano()))
+	binary.LittleEndian.PutUint64(buf[16:], uint64(info.Mode()))
+	binary.LittleEndian.PutUint64(buf[24:], dev)
+	binary.LittleEndian.PutUint64(buf[32:], ino)
+	h.Write(buf[:])
+}
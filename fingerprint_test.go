@@ -0,0 +1,49 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config")
+	os.WriteFile(file, []byte("v1"), 0644)
+
+	first, err := xfs.Fingerprint(file)
+	assert.NoError(t, err)
+
+	again, _ := xfs.Fingerprint(file)
+	assert.Equal(t, first, again)
+
+	// Same size and modification time, but a different file.
+	info, _ := os.Stat(file)
+	os.WriteFile(file+".new", []byte("v2"), 0644)
+	os.Chtimes(file+".new", info.ModTime(), info.ModTime())
+	os.Rename(file+".new", file)
+
+	replaced, _ := xfs.Fingerprint(file)
+	assert.NotEqual(t, first, replaced)
+
+	_, err = xfs.Fingerprint(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestFingerprintDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a"), []byte("a"), 0644)
+
+	first, err := xfs.FingerprintDir(dir)
+	assert.NoError(t, err)
+
+	again, _ := xfs.FingerprintDir(dir)
+	assert.Equal(t, first, again)
+
+	os.WriteFile(filepath.Join(dir, "b"), []byte("b"), 0644)
+	changed, _ := xfs.FingerprintDir(dir)
+	assert.NotEqual(t, first, changed)
+}
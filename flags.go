@@ -0,0 +1,79 @@
+package xfs
+
+import (
+	"strings"
+)
+
+// FileFlags is a set of Linux inode attributes as shown by lsattr(1) and
+// changed by chattr(1).
+type FileFlags uint32
+
+// Inode attribute flags. The values match the FS_*_FL constants of the Linux
+// kernel.
+const (
+	// FlagSync makes changes to the file written synchronously (chattr +S).
+	FlagSync FileFlags = 0x00000008
+	// FlagImmutable prevents the file from being modified, renamed, linked or
+	// deleted, even by root (chattr +i).
+	FlagImmutable FileFlags = 0x00000010
+	// FlagAppendOnly only allows the file to be opened for appending
+	// (chattr +a).
+	FlagAppendOnly FileFlags = 0x00000020
+	// FlagNoDump excludes the file from dump(8) backups (chattr +d).
+	FlagNoDump FileFlags = 0x00000040
+	// FlagNoAtime stops access time updates for the file (chattr +A).
+	FlagNoAtime FileFlags = 0x00000080
+	// FlagNoCOW disables copy-on-write on file systems such as btrfs
+	// (chattr +C).
+	FlagNoCOW FileFlags = 0x00800000
+)
+
+var fileFlagNames = []struct {
+	flag FileFlags
+	name string
+}{
+	{FlagSync, "sync"},
+	{FlagImmutable, "immutable"},
+	{FlagAppendOnly, "append"},
+	{FlagNoDump, "nodump"},
+	{FlagNoAtime, "noatime"},
+	{FlagNoCOW, "nocow"},
+}
+
+// String returns the names of the known flags that are set, separated by
+// commas.
+func (f FileFlags) String() string {
+	var names []string
+	for _, n := range fileFlagNames {
+		if f&n.flag != 0 {
+			names = append(names, n.name)
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+// GetFileFlags returns the inode attribute flags of the named file using the
+// FS_IOC_GETFLAGS ioctl. If the file system does not support inode flags, or
+// the platform is not Linux, the error wraps errors.ErrUnsupported.
+//
+// Parameters:
+//   - filename: the name of the file or directory
+func GetFileFlags(filename string) (FileFlags, error) {
+	return getFileFlags(filename)
+}
+
+// SetFileFlags replaces the inode attribute flags of the named file using the
+// FS_IOC_SETFLAGS ioctl. Flags that are not known to this package are
+// preserved only if they are included in flags, so read the current flags
+// with GetFileFlags and modify them. Setting FlagImmutable or FlagAppendOnly
+// requires the CAP_LINUX_IMMUTABLE capability. If the file system does not
+// support inode flags, or the platform is not Linux, the error wraps
+// errors.ErrUnsupported.
+//
+// Parameters:
+//   - filename: the name of the file or directory
+//   - flags: the new flags
+func SetFileFlags(filename string, flags FileFlags) error {
+	return setFileFlags(filename, flags)
+}
@@ -0,0 +1,38 @@
+//go:build linux
+
+package xfs
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func getFileFlags(filename string) (FileFlags, error) {
+	fd, err := unix.Open(filename, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return 0, &os.PathError{Op: "getflags", Path: filename, Err: err}
+	}
+	defer unix.Close(fd)
+
+	flags, err := unix.IoctlGetUint32(fd, unix.FS_IOC_GETFLAGS)
+	if err != nil {
+		return 0, &os.PathError{Op: "getflags", Path: filename, Err: unsupportedErrno(err)}
+	}
+
+	return FileFlags(flags), nil
+}
+
+func setFileFlags(filename string, flags FileFlags) error {
+	fd, err := unix.Open(filename, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "setflags", Path: filename, Err: err}
+	}
+	defer unix.Close(fd)
+
+	if err := unix.IoctlSetPointerInt(fd, unix.FS_IOC_SETFLAGS, int(flags)); err != nil {
+		return &os.PathError{Op: "setflags", Path: filename, Err: unsupportedErrno(err)}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package xfs
+
+import (
+	"errors"
+	"os"
+)
+
+func getFileFlags(filename string) (FileFlags, error) {
+	if _, err := os.Stat(filename); err != nil {
+		return 0, err
+	}
+
+	return 0, &os.PathError{Op: "getflags", Path: filename, Err: errors.ErrUnsupported}
+}
+
+func setFileFlags(filename string, flags FileFlags) error {
+	if _, err := os.Stat(filename); err != nil {
+		return err
+	}
+
+	return &os.PathError{Op: "setflags", Path: filename, Err: errors.ErrUnsupported}
+}
@@ -0,0 +1,51 @@
+package xfs_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileFlagsString(t *testing.T) {
+	assert.Equal(t, "", xfs.FileFlags(0).String())
+	assert.Equal(t, "immutable,append", (xfs.FlagImmutable | xfs.FlagAppendOnly).String())
+}
+
+func TestGetFileFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.conf")
+	xfs.WriteTextFile(path, "data", 0644)
+
+	flags, err := xfs.GetFileFlags(path)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("inode flags are not supported here")
+	}
+	assert.NoError(t, err)
+	assert.Zero(t, flags&xfs.FlagImmutable)
+
+	_, err = xfs.GetFileFlags(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestSetFileFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.conf")
+	xfs.WriteTextFile(path, "data", 0644)
+
+	flags, err := xfs.GetFileFlags(path)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("inode flags are not supported here")
+	}
+	assert.NoError(t, err)
+
+	err = xfs.SetFileFlags(path, flags|xfs.FlagNoDump)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("inode flags are not supported here")
+	}
+	assert.NoError(t, err)
+
+	updated, err := xfs.GetFileFlags(path)
+	assert.NoError(t, err)
+	assert.NotZero(t, updated&xfs.FlagNoDump)
+}
@@ -0,0 +1,288 @@
+package xfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// WritableFile is a file opened for writing from a WritableFS.
+type WritableFile interface {
+	fs.File
+	io.Writer
+}
+
+// WritableFS is an fs.FS that also supports creating, modifying and removing
+// files. Names follow the fs.FS conventions: they are slash-separated,
+// unrooted paths accepted by fs.ValidPath.
+type WritableFS interface {
+	fs.StatFS
+	fs.ReadDirFS
+
+	// OpenFile opens the named file with the given flags (os.O_RDONLY etc.)
+	// and, when creating it, permission bits.
+	OpenFile(name string, flag int, perm FileMode) (WritableFile, error)
+
+	// Mkdir creates the named directory.
+	Mkdir(name string, perm FileMode) error
+
+	// MkdirAll creates the named directory along with any missing parents.
+	MkdirAll(name string, perm FileMode) error
+
+	// Remove removes the named file or empty directory.
+	Remove(name string) error
+
+	// RemoveAll removes the named path and any children it contains.
+	RemoveAll(name string) error
+
+	// Rename renames oldname to newname.
+	Rename(oldname, newname string) error
+}
+
+// DirFS returns a WritableFS for the tree of files rooted at the directory
+// dir. Like os.DirFS it only prevents names from escaping dir lexically and
+// does not guard against symbolic links inside dir that point elsewhere.
+//
+// Parameters:
+//   - dir: the root directory
+func DirFS(dir string) WritableFS {
+	return dirFS(dir)
+}
+
+type dirFS string
+
+func (dir dirFS) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &os.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	return filepath.Join(string(dir), filepath.FromSlash(name)), nil
+}
+
+// fixErr replaces the full path in a *PathError with the name used inside
+// the file system so that errors look the same for every WritableFS.
+func (dir dirFS) fixErr(name string, err error) error {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return &os.PathError{Op: pathErr.Op, Path: name, Err: pathErr.Err}
+	}
+
+	return err
+}
+
+func (dir dirFS) Open(name string) (fs.File, error) {
+	return dir.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (dir dirFS) OpenFile(name string, flag int, perm FileMode) (WritableFile, error) {
+	path, err := dir.join("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, flag, perm)
+	if err != nil {
+		return nil, dir.fixErr(name, err)
+	}
+
+	return f, nil
+}
+
+func (dir dirFS) Stat(name string) (FileInfo, error) {
+	path, err := dir.join("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, dir.fixErr(name, err)
+	}
+
+	return info, nil
+}
+
+func (dir dirFS) ReadDir(name string) ([]DirEntry, error) {
+	path, err := dir.join("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, dir.fixErr(name, err)
+	}
+
+	return entries, nil
+}
+
+func (dir dirFS) Mkdir(name string, perm FileMode) error {
+	path, err := dir.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+
+	return dir.fixErr(name, os.Mkdir(path, perm))
+}
+
+func (dir dirFS) MkdirAll(name string, perm FileMode) error {
+	path, err := dir.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+
+	return dir.fixErr(name, os.MkdirAll(path, perm))
+}
+
+func (dir dirFS) Remove(name string) error {
+	path, err := dir.join("remove", name)
+	if err != nil {
+		return err
+	}
+
+	return dir.fixErr(name, os.Remove(path))
+}
+
+func (dir dirFS) RemoveAll(name string) error {
+	path, err := dir.join("removeall", name)
+	if err != nil {
+		return err
+	}
+
+	if name == "." {
+		return &os.PathError{Op: "removeall", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return dir.fixErr(name, os.RemoveAll(path))
+}
+
+func (dir dirFS) Rename(oldname, newname string) error {
+	oldpath, err := dir.join("rename", oldname)
+	if err != nil {
+		return err
+	}
+
+	newpath, err := dir.join("rename", newname)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(oldpath, newpath)
+	var linkErr *os.LinkError
+	if errors.As(err, &linkErr) {
+		return &os.LinkError{Op: linkErr.Op, Old: oldname, New: newname, Err: linkErr.Err}
+	}
+
+	return err
+}
+
+// EnsureDirFS creates the named directory in fsys, along with any missing
+// parents, if it does not exist.
+//
+// Parameters:
+//   - fsys: the file system
+//   - dir: the name of the directory
+//   - perm: the directory permissions
+func EnsureDirFS(fsys WritableFS, dir string, perm FileMode) error {
+	if _, err := fsys.Stat(dir); err == nil {
+		return nil
+	}
+
+	return fsys.MkdirAll(dir, perm)
+}
+
+// WriteFileFS writes data to the named file in fsys, creating it if
+// necessary. If the file does not exist, WriteFileFS creates it with
+// permissions perm; otherwise it is truncated before writing.
+//
+// Parameters:
+//   - fsys: the file system
+//   - filename: the name of the file
+//   - data: the data to write
+//   - perm: the file permissions
+func WriteFileFS(fsys WritableFS, filename string, data []byte, perm FileMode) error {
+	f, err := fsys.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// WriteTextFileFS writes the string data to the named file in fsys like
+// WriteFileFS.
+//
+// Parameters:
+//   - fsys: the file system
+//   - filename: the name of the file
+//   - data: the text to write
+//   - perm: the file permissions
+func WriteTextFileFS(fsys WritableFS, filename string, data string, perm FileMode) error {
+	return WriteFileFS(fsys, filename, []byte(data), perm)
+}
+
+// CopyDirFS copies the file system src into the directory dstDir of dst
+// with the same semantics as CopyFS, which makes it possible to copy a local
+// tree (os.DirFS) to any WritableFS such as a remote host. Use fs.Sub to
+// copy only part of src.
+//
+// Parameters:
+//   - dst: the destination file system
+//   - dstDir: the destination directory in dst, "." for its root
+//   - src: the file system to copy
+//   - overwrite: whether to overwrite destination files that already exist
+func CopyDirFS(dst WritableFS, dstDir string, src fs.FS, overwrite bool) error {
+	return fs.WalkDir(src, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		dstName := path.Join(dstDir, name)
+		info, err := fs.Stat(src, name)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return EnsureDirFS(dst, dstName, info.Mode().Perm()|0700)
+		}
+
+		if !info.Mode().IsRegular() {
+			return &os.PathError{Op: "copyfs", Path: name, Err: fs.ErrInvalid}
+		}
+
+		if _, err := dst.Stat(dstName); err == nil && !overwrite {
+			return nil
+		}
+
+		return copyToFS(dst, dstName, src, name, info.Mode().Perm()|0600)
+	})
+}
+
+func copyToFS(dst WritableFS, dstName string, src fs.FS, name string, perm FileMode) error {
+	srcFile, err := src.Open(name)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := dst.OpenFile(dstName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dstFile, srcFile)
+	if closeErr := dstFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,61 @@
+package xfs_test
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirFS(t *testing.T) {
+	root := t.TempDir()
+	fsys := xfs.DirFS(root)
+
+	assert.NoError(t, fsys.MkdirAll("a/b", 0755))
+	f, err := fsys.OpenFile("a/b/c.txt", os.O_WRONLY|os.O_CREATE, 0644)
+	assert.NoError(t, err)
+	io.WriteString(f, "hello")
+	f.Close()
+
+	data, err := os.ReadFile(filepath.Join(root, "a", "b", "c.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.NoError(t, fstest.TestFS(fsys, "a/b/c.txt"))
+
+	assert.NoError(t, fsys.Rename("a/b/c.txt", "a/c.txt"))
+	_, err = fsys.Stat("a/b/c.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	var pathErr *os.PathError
+	assert.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, "a/b/c.txt", pathErr.Path)
+
+	_, err = fsys.Open("../escape")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+
+	assert.NoError(t, fsys.RemoveAll("a"))
+	assert.False(t, xfs.Exists(filepath.Join(root, "a")))
+}
+
+func TestCopyDirFS(t *testing.T) {
+	src := fstest.MapFS{
+		"a.txt":     {Data: []byte("a"), Mode: 0644},
+		"dir/b.txt": {Data: []byte("b"), Mode: 0644},
+	}
+	root := t.TempDir()
+	dst := xfs.DirFS(root)
+
+	assert.NoError(t, xfs.CopyDirFS(dst, "out", src, false))
+	assert.NoError(t, xfs.WriteTextFileFS(dst, "out/a.txt", "changed", 0644))
+	assert.NoError(t, xfs.CopyDirFS(dst, "out", src, false))
+
+	data, _ := os.ReadFile(filepath.Join(root, "out", "a.txt"))
+	assert.Equal(t, "changed", string(data))
+	data, _ = os.ReadFile(filepath.Join(root, "out", "dir", "b.txt"))
+	assert.Equal(t, "b", string(data))
+}
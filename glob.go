@@ -0,0 +1,274 @@
+package xfs
+
+import (
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// ExpandBraces expands shell-style brace alternatives in pattern and returns
+// the resulting patterns in order. Alternatives may be nested, so
+// "src/{cmd,internal/{a,b}}/*.go" yields three patterns. Everything else,
+// including glob metacharacters, is left untouched so the results can be
+// passed to Glob or filepath.Match. A pattern without braces is returned
+// as the only element.
+//
+// Braces without a comma, such as "{a}", are kept literally as in the shell.
+// On platforms other than Windows a backslash escapes the following brace or
+// comma. Unbalanced braces return filepath.ErrBadPattern.
+//
+// Parameters:
+//   - pattern: the pattern to expand
+func ExpandBraces(pattern string) ([]string, error) {
+	open, close, commas, err := findBraces(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if open < 0 {
+		return []string{pattern}, nil
+	}
+
+	prefix := pattern[:open]
+	suffix := pattern[close+1:]
+
+	var results []string
+	start := open + 1
+	for _, comma := range append(commas, close) {
+		alt := prefix + pattern[start:comma] + suffix
+		expanded, err := ExpandBraces(alt)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, expanded...)
+		start = comma + 1
+	}
+
+	return results, nil
+}
+
+// findBraces locates the first brace group in pattern that contains a
+// top-level comma and returns the index of its opening and closing braces and
+// of its top-level commas. open is -1 when there is no such group.
+func findBraces(pattern string) (open, close int, commas []int, err error) {
+	escapes := runtime.GOOS != "windows"
+	if !balancedBraces(pattern, escapes) {
+		return -1, -1, nil, filepath.ErrBadPattern
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case '{':
+			depth := 0
+			commas = commas[:0]
+			for j := i; j < len(pattern); j++ {
+				switch pattern[j] {
+				case '\\':
+					if escapes {
+						j++
+					}
+				case '{':
+					depth++
+				case ',':
+					if depth == 1 {
+						commas = append(commas, j)
+					}
+				case '}':
+					depth--
+				}
+
+				if depth == 0 {
+					if len(commas) > 0 {
+						return i, j, commas, nil
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return -1, -1, nil, nil
+}
+
+// balancedBraces reports whether every brace in pattern is closed.
+func balancedBraces(pattern string, escapes bool) bool {
+	depth := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+
+	return depth == 0
+}
+
+// Glob returns the names of all files matching pattern, or nil if there is
+// no matching file. It extends filepath.Glob with brace expansion (see
+// ExpandBraces) and with "**" as a whole path element, which matches zero or
+// more directories, so "src/{cmd,internal}/**/*.go" finds every Go file below
+// src/cmd and src/internal. The results are sorted and free of duplicates.
+//
+// Like filepath.Glob, I/O errors such as unreadable directories are ignored
+// and the only possible error is filepath.ErrBadPattern. Symbolic links to
+// directories are not followed when expanding "**".
+//
+// Parameters:
+//   - pattern: the pattern to match
+func Glob(pattern string) ([]string, error) {
+	patterns, err := ExpandBraces(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, p := range patterns {
+		var found []string
+		if hasGlobStar(p) {
+			found, err = globStar(p)
+		} else {
+			found, err = filepath.Glob(p)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		matches = append(matches, found...)
+	}
+
+	slices.Sort(matches)
+	return slices.Compact(matches), nil
+}
+
+// splitPattern splits a pattern into its path elements.
+func splitPattern(pattern string) []string {
+	if runtime.GOOS == "windows" {
+		pattern = strings.ReplaceAll(pattern, "/", `\`)
+	}
+
+	return strings.Split(pattern, string(filepath.Separator))
+}
+
+func hasGlobStar(pattern string) bool {
+	return slices.Contains(splitPattern(pattern), "**")
+}
+
+func hasMeta(segment string) bool {
+	chars := `*?[`
+	if runtime.GOOS != "windows" {
+		chars = `*?[\`
+	}
+
+	return strings.ContainsAny(segment, chars)
+}
+
+// globStar matches a pattern containing "**" by walking the tree below the
+// longest literal prefix of the pattern.
+func globStar(pattern string) ([]string, error) {
+	segments := splitPattern(pattern)
+
+	literal := 0
+	for literal < len(segments) && !hasMeta(segments[literal]) {
+		literal++
+	}
+
+	for _, segment := range segments[literal:] {
+		if segment == "**" {
+			continue
+		}
+
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	root := strings.Join(segments[:literal], string(filepath.Separator))
+	if literal > 0 && (root == "" || root == filepath.VolumeName(root)) {
+		root += string(filepath.Separator)
+	}
+
+	walkRoot := root
+	if walkRoot == "" {
+		walkRoot = "."
+	}
+
+	rest := segments[literal:]
+	var matches []string
+	filepath.WalkDir(walkRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		rel, err := filepath.Rel(walkRoot, path)
+		if err != nil {
+			return nil
+		}
+
+		var parts []string
+		if rel != "." {
+			parts = strings.Split(rel, string(filepath.Separator))
+		}
+
+		if matchSegments(rest, parts) {
+			matches = append(matches, path)
+		}
+
+		return nil
+	})
+
+	return matches, nil
+}
+
+// matchSegments reports whether the path elements in name match the pattern
+// elements, where a "**" element matches any number of path elements.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+
+			if len(pattern) == 0 {
+				return true
+			}
+
+			for i := range name {
+				if matchSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		ok, err := filepath.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
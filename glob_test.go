@@ -0,0 +1,62 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandBraces(t *testing.T) {
+	patterns, err := xfs.ExpandBraces("src/{cmd,internal}/**/*.go")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"src/cmd/**/*.go", "src/internal/**/*.go"}, patterns)
+
+	patterns, err = xfs.ExpandBraces("{a,b{1,2}}.{txt,md}")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "a.md", "b1.txt", "b1.md", "b2.txt", "b2.md"}, patterns)
+
+	patterns, err = xfs.ExpandBraces("{x}/{y,z}")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{x}/y", "{x}/z"}, patterns)
+
+	patterns, err = xfs.ExpandBraces("plain/*.go")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"plain/*.go"}, patterns)
+
+	_, err = xfs.ExpandBraces("src/{cmd,internal")
+	assert.ErrorIs(t, err, filepath.ErrBadPattern)
+}
+
+func TestGlob(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{
+		"src/cmd/main.go",
+		"src/cmd/tool/tool.go",
+		"src/internal/a/b/lib.go",
+		"src/internal/readme.md",
+		"src/pkg/skip.go",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		xfs.MkdirAllDefault(filepath.Dir(path))
+		os.WriteFile(path, nil, 0644)
+	}
+
+	matches, err := xfs.Glob(filepath.Join(root, "src", "{cmd,internal}", "**", "*.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "src", "cmd", "main.go"),
+		filepath.Join(root, "src", "cmd", "tool", "tool.go"),
+		filepath.Join(root, "src", "internal", "a", "b", "lib.go"),
+	}, matches)
+
+	matches, err = xfs.Glob(filepath.Join(root, "src", "*", "*.{go,md}"))
+	assert.NoError(t, err)
+	assert.Len(t, matches, 3)
+
+	matches, err = xfs.Glob(filepath.Join(root, "missing", "**", "*.go"))
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+}
@@ -0,0 +1,182 @@
+package xfs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// HashCache memoizes file digests computed by HashFile. An entry is reused as
+// long as the file's size and modification time are unchanged, so repeated
+// snapshots and verifications of large trees only hash files that changed.
+//
+// A HashCache is safe for concurrent use.
+type HashCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]hashCacheEntry
+	dirty   bool
+}
+
+type hashCacheEntry struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime"`
+	Hash    string    `json:"hash"`
+}
+
+// NewHashCache creates a hash cache persisted to the named file, loading
+// existing entries if the file exists. An empty filename creates a cache
+// that only lives in memory.
+//
+// Parameters:
+//   - filename: the name of the cache file
+func NewHashCache(filename string) (*HashCache, error) {
+	c := &HashCache{path: filename, entries: map[string]hashCacheEntry{}}
+	if filename == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Hash returns the hex encoded SHA-256 digest of the named file, using the
+// cached digest when the file's size and modification time are unchanged.
+//
+// Parameters:
+//   - filename: the name of the file
+func (c *HashCache) Hash(filename string) (string, error) {
+	key, err := filepath.Abs(filename)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+		return entry.Hash, nil
+	}
+
+	sum, err := HashFile(filename)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = hashCacheEntry{Size: info.Size(), ModTime: info.ModTime(), Hash: sum}
+	c.dirty = true
+	c.mu.Unlock()
+
+	return sum, nil
+}
+
+// Invalidate removes the cached digest of the named file.
+//
+// Parameters:
+//   - filename: the name of the file
+func (c *HashCache) Invalidate(filename string) {
+	key, err := filepath.Abs(filename)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		delete(c.entries, key)
+		c.dirty = true
+	}
+}
+
+// Len returns the number of cached digests.
+func (c *HashCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Save persists the cache to its file if it changed since it was loaded or
+// last saved. The file is replaced by renaming a temporary file, so a crash
+// never leaves a truncated cache behind. Save is a no-op for in-memory caches.
+func (c *HashCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.path == "" || !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// SnapshotCached is like Snapshot but takes file digests from cache.
+//
+// Parameters:
+//   - root: the directory to snapshot
+//   - cache: the hash cache to consult and update
+func SnapshotCached(root string, cache *HashCache) (*Manifest, error) {
+	return snapshot(root, cache.Hash)
+}
+
+// VerifySnapshotCached is like VerifySnapshot but takes file digests from cache.
+//
+// Parameters:
+//   - root: the directory to verify
+//   - manifest: the manifest to verify against
+//   - cache: the hash cache to consult and update
+func VerifySnapshotCached(root string, manifest *Manifest, cache *HashCache) (*SnapshotDiff, error) {
+	current, err := SnapshotCached(root, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest.Diff(current), nil
+}
@@ -0,0 +1,65 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashCache(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "data.bin")
+	cachePath := filepath.Join(dir, "hashes.json")
+	xfs.WriteTextFile(file, "hello", 0644)
+
+	cache, err := xfs.NewHashCache(cachePath)
+	assert.NoError(t, err)
+
+	sum, err := cache.Hash(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sum)
+	assert.NoError(t, cache.Save())
+
+	// Change the content but keep size and mtime: the cached digest is reused.
+	info, _ := os.Stat(file)
+	xfs.WriteTextFile(file, "HELLO", 0644)
+	os.Chtimes(file, info.ModTime(), info.ModTime())
+
+	loaded, err := xfs.NewHashCache(cachePath)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, loaded.Len())
+	cached, err := loaded.Hash(file)
+	assert.NoError(t, err)
+	assert.Equal(t, sum, cached)
+
+	// A new mtime invalidates the entry.
+	later := info.ModTime().Add(time.Second)
+	os.Chtimes(file, later, later)
+	fresh, err := loaded.Hash(file)
+	assert.NoError(t, err)
+	assert.NotEqual(t, sum, fresh)
+
+	loaded.Invalidate(file)
+	assert.Equal(t, 0, loaded.Len())
+}
+
+func TestSnapshotCached(t *testing.T) {
+	root := t.TempDir()
+	xfs.WriteTextFile(filepath.Join(root, "a.txt"), "a", 0644)
+
+	cache, err := xfs.NewHashCache("")
+	assert.NoError(t, err)
+
+	m, err := xfs.SnapshotCached(root, cache)
+	assert.NoError(t, err)
+	assert.Len(t, m.Entries, 1)
+	assert.Equal(t, 1, cache.Len())
+
+	diff, err := xfs.VerifySnapshotCached(root, m, cache)
+	assert.NoError(t, err)
+	assert.False(t, diff.Changed())
+}
@@ -0,0 +1,38 @@
+package xfs
+
+// IsFileLocked reports whether another process holds a lock on the named
+// file that conflicts with writing it. On Linux it looks the file up in
+// /proc/locks, which lists POSIX, open file description and flock(2) locks.
+// On other Unix systems it tests for POSIX record locks with fcntl F_GETLK,
+// so flock(2) locks are not seen on all of them. On Windows it reports
+// whether the file cannot be opened exclusively because of a sharing or
+// lock violation.
+//
+// Caution: on Unix systems other than Linux, IsFileLocked opens and closes
+// the file, and closing any descriptor of a file releases all POSIX record
+// locks the calling process holds on it. Do not call it on a file your own
+// program has locked with fcntl, such as an open SQLite database or a
+// pidfile, or that lock is silently lost.
+//
+// The answer may be outdated as soon as it is returned; use it to wait or
+// warn, not to guarantee exclusive access.
+//
+// Parameters:
+//   - filename: the name of the file
+func IsFileLocked(filename string) (bool, error) {
+	return isFileLocked(filename)
+}
+
+// InUse reports whether another process has the named file open, for
+// example before replacing a binary or a database. On Linux it scans the
+// open files, executables and memory mappings of all processes in /proc that
+// the caller may inspect. On Windows it reports whether the file cannot be
+// opened exclusively. Elsewhere it falls back to IsFileLocked.
+//
+// Like IsFileLocked, InUse is a heuristic snapshot.
+//
+// Parameters:
+//   - filename: the name of the file
+func InUse(filename string) (bool, error) {
+	return inUse(filename)
+}
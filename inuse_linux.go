@@ -0,0 +1,146 @@
+//go:build linux
+
+package xfs
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+func inUse(filename string) (bool, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(filename, &st); err != nil {
+		return false, &os.PathError{Op: "stat", Path: filename, Err: err}
+	}
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return isFileLocked(filename)
+	}
+
+	self := strconv.Itoa(os.Getpid())
+	for _, p := range procs {
+		if _, err := strconv.Atoi(p.Name()); err != nil || p.Name() == self {
+			continue
+		}
+
+		dir := filepath.Join("/proc", p.Name())
+		if procHasFile(dir, &st) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// procHasFile reports whether the process described by the /proc directory
+// dir has the file st open, runs it or maps it. Processes that cannot be
+// inspected are skipped.
+func procHasFile(dir string, st *unix.Stat_t) bool {
+	if sameStat(filepath.Join(dir, "exe"), st) {
+		return true
+	}
+
+	fds, _ := os.ReadDir(filepath.Join(dir, "fd"))
+	for _, fd := range fds {
+		if sameStat(filepath.Join(dir, "fd", fd.Name()), st) {
+			return true
+		}
+	}
+
+	maps, err := os.Open(filepath.Join(dir, "maps"))
+	if err != nil {
+		return false
+	}
+	defer maps.Close()
+
+	// Each line reads: address perms offset major:minor inode pathname.
+	scanner := bufio.NewScanner(maps)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+
+		ino, err := strconv.ParseUint(fields[4], 10, 64)
+		if err != nil || ino != st.Ino {
+			continue
+		}
+
+		if dev, ok := parseDevice(fields[3]); ok && dev == uint64(st.Dev) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isFileLocked looks the file up in /proc/locks rather than asking with
+// F_GETLK, because opening and closing the file for F_GETLK would release
+// the POSIX locks the calling process holds on it.
+func isFileLocked(filename string) (bool, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(filename, &st); err != nil {
+		return false, &os.PathError{Op: "stat", Path: filename, Err: err}
+	}
+
+	locks, err := os.Open("/proc/locks")
+	if err != nil {
+		return false, &os.PathError{Op: "getlk", Path: filename, Err: unsupportedErrno(err)}
+	}
+	defer locks.Close()
+
+	// Each line reads: id: class mode access pid major:minor:inode start end,
+	// with "->" after the id for requests waiting on the lock before them.
+	self := strconv.Itoa(os.Getpid())
+	scanner := bufio.NewScanner(locks)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[1] == "->" || fields[4] == self {
+			continue
+		}
+
+		i := strings.LastIndexByte(fields[5], ':')
+		if i < 0 {
+			continue
+		}
+
+		ino, err := strconv.ParseUint(fields[5][i+1:], 10, 64)
+		if err != nil || ino != st.Ino {
+			continue
+		}
+
+		if dev, ok := parseDevice(fields[5][:i]); ok && dev == uint64(st.Dev) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// parseDevice parses a device number in the hexadecimal major:minor form
+// used by files in /proc.
+func parseDevice(s string) (uint64, bool) {
+	major, minor, ok := strings.Cut(s, ":")
+	majorNum, err1 := strconv.ParseUint(major, 16, 32)
+	minorNum, err2 := strconv.ParseUint(minor, 16, 32)
+	if !ok || err1 != nil || err2 != nil {
+		return 0, false
+	}
+
+	return unix.Mkdev(uint32(majorNum), uint32(minorNum)), true
+}
+
+func sameStat(path string, st *unix.Stat_t) bool {
+	var other unix.Stat_t
+	if err := unix.Stat(path, &other); err != nil {
+		return false
+	}
+
+	return other.Dev == st.Dev && other.Ino == st.Ino
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !windows
+
+package xfs
+
+func inUse(filename string) (bool, error) {
+	return isFileLocked(filename)
+}
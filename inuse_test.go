@@ -0,0 +1,88 @@
+package xfs_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsFileLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(path, []byte("x"), 0644)
+
+	locked, err := xfs.IsFileLocked(path)
+	assert.NoError(t, err)
+	assert.False(t, locked)
+
+	_, err = xfs.IsFileLocked(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestIsFileLockedByOther(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses flock(1) and /proc/locks")
+	}
+
+	flock, err := exec.LookPath("flock")
+	if err != nil {
+		t.Skip("flock not found")
+	}
+
+	path := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(path, []byte("x"), 0644)
+
+	cmd := exec.Command(flock, "--exclusive", path, "sleep", "10")
+	assert.NoError(t, cmd.Start())
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	locked := false
+	for !locked && time.Now().Before(deadline) {
+		locked, err = xfs.IsFileLocked(path)
+		assert.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.True(t, locked)
+}
+
+func TestInUse(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("open handles of other processes are only visible on Linux")
+	}
+
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep not found")
+	}
+
+	path := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(path, []byte("x"), 0644)
+
+	used, err := xfs.InUse(path)
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	f, _ := os.Open(path)
+	cmd := exec.Command(sleep, "10")
+	cmd.Stdin = f
+	assert.NoError(t, cmd.Start())
+	f.Close()
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	used, err = xfs.InUse(path)
+	assert.NoError(t, err)
+	assert.True(t, used)
+}
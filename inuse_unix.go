@@ -0,0 +1,24 @@
+//go:build unix && !linux
+
+package xfs
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func isFileLocked(filename string) (bool, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	lk := unix.Flock_t{Type: unix.F_WRLCK, Whence: 0, Start: 0, Len: 0}
+	if err := unix.FcntlFlock(f.Fd(), unix.F_GETLK, &lk); err != nil {
+		return false, &os.PathError{Op: "getlk", Path: filename, Err: unsupportedErrno(err)}
+	}
+
+	return lk.Type != unix.F_UNLCK, nil
+}
@@ -0,0 +1,41 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// openExclusive reports whether the named file can only be opened with
+// sharing because another process has it open or locked.
+func openExclusive(filename string) (bool, error) {
+	name, err := windows.UTF16PtrFromString(fixLongPath(filename))
+	if err != nil {
+		return false, &os.PathError{Op: "open", Path: filename, Err: err}
+	}
+
+	h, err := windows.CreateFile(name, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil,
+		windows.OPEN_EXISTING, windows.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err == nil {
+		windows.CloseHandle(h)
+		return false, nil
+	}
+
+	if errors.Is(err, windows.ERROR_SHARING_VIOLATION) || errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return true, nil
+	}
+
+	return false, &os.PathError{Op: "open", Path: filename, Err: err}
+}
+
+func isFileLocked(filename string) (bool, error) {
+	return openExclusive(filename)
+}
+
+func inUse(filename string) (bool, error) {
+	return openExclusive(filename)
+}
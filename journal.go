@@ -0,0 +1,269 @@
+package xfs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUndoExpired is returned by UndoJournal.Undo when the retention window of
+// the removal has passed.
+var ErrUndoExpired = errors.New("xfs: undo retention expired")
+
+// journalMeta is the name of the file describing a removal in its journal
+// entry directory; the removed tree is stored next to it as journalData.
+const (
+	journalMeta = "entry.json"
+	journalData = "data"
+)
+
+// UndoJournal removes files and directories by moving them into a journal
+// directory, from which they can be restored with Undo until their retention
+// window has passed and Purge deletes them for good. It lets interactive
+// tools offer "undo delete".
+//
+// The journal directory should be on the same file system as the paths
+// removed through it: the trees are then moved with a rename, which is
+// instant. Otherwise they are copied and deleted.
+type UndoJournal struct {
+	dir       string
+	retention time.Duration
+}
+
+// JournalEntry describes a removal recorded in an UndoJournal.
+type JournalEntry struct {
+	// ID identifies the removal for Undo.
+	ID string `json:"id"`
+	// Path is the absolute path the tree was removed from.
+	Path string `json:"path"`
+	// RemovedAt is the time of the removal.
+	RemovedAt time.Time `json:"removed_at"`
+}
+
+// OpenUndoJournal opens the journal stored in dir, creating the directory
+// with mode 0700 if it does not exist.
+//
+// Parameters:
+//   - dir: the journal directory
+//   - retention: how long removals can be undone, zero for no limit
+func OpenUndoJournal(dir string, retention time.Duration) (*UndoJournal, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &UndoJournal{dir: dir, retention: retention}, nil
+}
+
+// RemoveAll removes path and any children it contains, like RemoveAll, by
+// moving it into the journal, and returns the ID that restores it.
+//
+// When the journal is on another file system, the tree is copied into the
+// journal and then deleted. If the copy succeeds but the deletion fails, the
+// entry is kept and RemoveAll returns its ID along with the error, so the
+// copy is never lost while parts of the original remain.
+//
+// Parameters:
+//   - path: the name of the file or directory
+func (j *UndoJournal) RemoveAll(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Lstat(abs); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 6)
+	rand.Read(buf)
+	entry := JournalEntry{
+		ID:        time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(buf),
+		Path:      abs,
+		RemovedAt: time.Now(),
+	}
+
+	entryDir := filepath.Join(j.dir, entry.ID)
+	if err := os.Mkdir(entryDir, 0700); err != nil {
+		return "", err
+	}
+
+	meta, err := json.Marshal(entry)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(entryDir, journalMeta), meta, 0600)
+	}
+
+	moved := false
+	if err == nil {
+		moved, err = moveTree(abs, filepath.Join(entryDir, journalData))
+	}
+
+	if err != nil && !moved {
+		os.RemoveAll(entryDir)
+		return "", err
+	}
+
+	return entry.ID, err
+}
+
+// Undo restores the tree removed under id to its original path. It fails
+// with an error matching os.ErrExist if the path was recreated in the
+// meantime, and with ErrUndoExpired once the retention window has passed.
+//
+// Parameters:
+//   - id: the ID returned by RemoveAll
+func (j *UndoJournal) Undo(id string) error {
+	entry, err := j.entry(id)
+	if err != nil {
+		return err
+	}
+
+	if j.expired(entry) {
+		return &os.PathError{Op: "undo", Path: entry.Path, Err: ErrUndoExpired}
+	}
+
+	if _, err := os.Lstat(entry.Path); err == nil {
+		return &os.PathError{Op: "undo", Path: entry.Path, Err: os.ErrExist}
+	}
+
+	if err := EnsureParentDir(entry.Path, 0755); err != nil {
+		return err
+	}
+
+	entryDir := filepath.Join(j.dir, id)
+	if moved, err := moveTree(filepath.Join(entryDir, journalData), entry.Path); !moved {
+		return err
+	}
+
+	return os.RemoveAll(entryDir)
+}
+
+// Entries returns the removals recorded in the journal, oldest first.
+// Entries that cannot be read are skipped.
+func (j *UndoJournal) Entries() ([]JournalEntry, error) {
+	dirs, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []JournalEntry
+	for _, d := range dirs {
+		if entry, err := j.entry(d.Name()); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(a, b int) bool {
+		return entries[a].RemovedAt.Before(entries[b].RemovedAt)
+	})
+
+	return entries, nil
+}
+
+// Purge deletes the removals whose retention window has passed, finalizing
+// their deletion.
+func (j *UndoJournal) Purge() error {
+	entries, err := j.Entries()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if j.expired(entry) {
+			if err := os.RemoveAll(filepath.Join(j.dir, entry.ID)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// StartPurge runs Purge in the background every interval until the returned
+// function is called.
+//
+// Parameters:
+//   - interval: the time between purges
+func (j *UndoJournal) StartPurge(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				j.Purge()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+func (j *UndoJournal) entry(id string) (JournalEntry, error) {
+	var entry JournalEntry
+	if filepath.Base(id) != id || id == "." || id == ".." {
+		return entry, &os.PathError{Op: "undo", Path: id, Err: os.ErrInvalid}
+	}
+
+	data, err := os.ReadFile(filepath.Join(j.dir, id, journalMeta))
+	if err != nil {
+		return entry, err
+	}
+
+	err = json.Unmarshal(data, &entry)
+	return entry, err
+}
+
+func (j *UndoJournal) expired(entry JournalEntry) bool {
+	return j.retention > 0 && time.Since(entry.RemovedAt) > j.retention
+}
+
+// moveTree renames src to dst, or copies and removes it when they are on
+// different file systems. It reports whether dst holds the complete tree,
+// which is also the case when only removing src after the copy failed.
+func moveTree(src, dst string) (bool, error) {
+	err := os.Rename(src, dst)
+	if err == nil {
+		return true, nil
+	}
+
+	if !crossDevice(err) {
+		return false, err
+	}
+
+	info, err := os.Lstat(src)
+	if err != nil {
+		return false, err
+	}
+
+	opts := &CopyOptions{Symlinks: SymlinkCopyLink}
+	switch {
+	case info.IsDir():
+		err = CopyDirOpt(src, dst, opts)
+	case info.Mode()&os.ModeSymlink != 0:
+		err = copyLink(src, dst, opts, nil)
+	default:
+		err = copyFile(src, dst, info, false)
+	}
+
+	if err != nil {
+		os.RemoveAll(dst)
+		return false, err
+	}
+
+	return true, os.RemoveAll(src)
+}
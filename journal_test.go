@@ -0,0 +1,101 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUndoJournal(t *testing.T) {
+	dir := t.TempDir()
+	j, err := xfs.OpenUndoJournal(filepath.Join(dir, ".journal"), time.Hour)
+	assert.NoError(t, err)
+
+	tree := filepath.Join(dir, "project")
+	os.MkdirAll(filepath.Join(tree, "src"), 0755)
+	os.WriteFile(filepath.Join(tree, "src", "main.go"), []byte("package main"), 0644)
+
+	id, err := j.RemoveAll(tree)
+	assert.NoError(t, err)
+	assert.False(t, xfs.Exists(tree))
+
+	entries, err := j.Entries()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, id, entries[0].ID)
+	assert.Equal(t, tree, entries[0].Path)
+
+	assert.NoError(t, j.Undo(id))
+	data, _ := os.ReadFile(filepath.Join(tree, "src", "main.go"))
+	assert.Equal(t, "package main", string(data))
+
+	entries, _ = j.Entries()
+	assert.Empty(t, entries)
+
+	id, _ = j.RemoveAll(tree)
+	os.Mkdir(tree, 0755)
+	assert.ErrorIs(t, j.Undo(id), os.ErrExist)
+
+	_, err = j.RemoveAll(filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	assert.ErrorIs(t, j.Undo("../escape"), os.ErrInvalid)
+}
+
+func TestUndoJournalCrossDevice(t *testing.T) {
+	dir := t.TempDir()
+	shm, err := os.MkdirTemp("/dev/shm", "journal")
+	if err != nil {
+		t.Skip("no /dev/shm")
+	}
+	defer os.RemoveAll(shm)
+
+	a, errA := xfs.FileID(dir)
+	b, errB := xfs.FileID(shm)
+	if errA != nil || errB != nil || a.Dev == b.Dev {
+		t.Skip("/dev/shm is not a separate file system")
+	}
+
+	j, err := xfs.OpenUndoJournal(shm, 0)
+	assert.NoError(t, err)
+
+	tree := filepath.Join(dir, "project")
+	os.MkdirAll(filepath.Join(tree, "src"), 0755)
+	os.WriteFile(filepath.Join(tree, "src", "main.go"), []byte("package main"), 0644)
+
+	id, err := j.RemoveAll(tree)
+	assert.NoError(t, err)
+	assert.False(t, xfs.Exists(tree))
+
+	assert.NoError(t, j.Undo(id))
+	data, _ := os.ReadFile(filepath.Join(tree, "src", "main.go"))
+	assert.Equal(t, "package main", string(data))
+
+	entries, _ := j.Entries()
+	assert.Empty(t, entries)
+}
+
+func TestUndoJournalPurge(t *testing.T) {
+	dir := t.TempDir()
+	j, _ := xfs.OpenUndoJournal(filepath.Join(dir, ".journal"), time.Millisecond)
+
+	file := filepath.Join(dir, "file.txt")
+	os.WriteFile(file, []byte("x"), 0644)
+	id, err := j.RemoveAll(file)
+	assert.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	assert.ErrorIs(t, j.Undo(id), xfs.ErrUndoExpired)
+
+	assert.NoError(t, j.Purge())
+	entries, _ := j.Entries()
+	assert.Empty(t, entries)
+
+	stop := j.StartPurge(time.Millisecond)
+	stop()
+	stop()
+}
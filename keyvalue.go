@@ -0,0 +1,181 @@
+package xfs
+
+import (
+	"strings"
+)
+
+// KeyValueOptions controls how GetKeyValue and SetKeyValue read ini and env
+// style files.
+type KeyValueOptions struct {
+	// Separator separates keys from values. Surrounding spaces are ignored
+	// when matching and kept when a new entry is written, so " = " matches
+	// "a=1" and writes "b = 2". Empty means "=".
+	Separator string
+	// Section is the name of the ini section holding the key, without
+	// brackets. Empty means the entries before the first section header.
+	Section string
+}
+
+func (o *KeyValueOptions) withDefaults() KeyValueOptions {
+	opts := KeyValueOptions{}
+	if o != nil {
+		opts = *o
+	}
+
+	if strings.TrimSpace(opts.Separator) == "" {
+		opts.Separator = "="
+	}
+
+	return opts
+}
+
+// GetKeyValue returns the value of the first entry for key in the named ini
+// or env style file and whether it was found. Surrounding spaces and a pair
+// of matching single or double quotes are removed from the value. Lines
+// starting with "#" or ";" are comments.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - key: the key to look up
+//   - opts: the file format options, nil uses the defaults
+func GetKeyValue(filename, key string, opts *KeyValueOptions) (string, bool, error) {
+	o := opts.withDefaults()
+	lines, err := ReadFileLinesKeepEOL(filename)
+	if err != nil {
+		return "", false, err
+	}
+
+	match, _ := findKeyValue(lines, key, o)
+	if match < 0 {
+		return "", false, nil
+	}
+
+	_, at, _ := parseKeyValue(lines[match].Text, o.Separator)
+	value := strings.TrimSpace(lines[match].Text[at:])
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+
+	return value, true, nil
+}
+
+// SetKeyValue sets key to value in the named ini or env style file and
+// reports whether the file changed. The first existing entry for key is
+// updated in place, keeping its spacing; otherwise a new entry is added at
+// the end of the section, creating the section if needed. Comments, blank
+// lines and lines SetKeyValue does not understand are preserved. The value
+// is written as is, so callers quote it where the format requires.
+//
+// A missing file is created with permissions 0644 (before umask). The file is
+// rewritten atomically with its current permissions.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - key: the key to set
+//   - value: the new value
+//   - opts: the file format options, nil uses the defaults
+func SetKeyValue(filename, key, value string, opts *KeyValueOptions) (bool, error) {
+	o := opts.withDefaults()
+	lines, perm, err := readLinesForEdit(filename)
+	if err != nil {
+		return false, err
+	}
+
+	eol := DefaultEOL
+	if len(lines) > 0 && lines[0].EOL != "" {
+		eol = lines[0].EOL
+	}
+
+	match, insert := findKeyValue(lines, key, o)
+	if match >= 0 {
+		_, at, _ := parseKeyValue(lines[match].Text, o.Separator)
+		text := lines[match].Text[:at] + value
+		if text == lines[match].Text {
+			return false, nil
+		}
+
+		lines[match].Text = text
+		return true, writeLinesAtomic(filename, lines, perm)
+	}
+
+	var added []Line
+	if insert < 0 {
+		if n := len(lines); n > 0 && strings.TrimSpace(lines[n-1].Text) != "" {
+			added = append(added, Line{EOL: eol})
+		}
+
+		added = append(added, Line{Text: "[" + o.Section + "]", EOL: eol})
+		insert = len(lines)
+	}
+
+	added = append(added, Line{Text: key + o.Separator + value, EOL: eol})
+	if insert > 0 && lines[insert-1].EOL == "" {
+		lines[insert-1].EOL = eol
+	}
+
+	updated := make([]Line, 0, len(lines)+len(added))
+	updated = append(updated, lines[:insert]...)
+	updated = append(updated, added...)
+	updated = append(updated, lines[insert:]...)
+	return true, writeLinesAtomic(filename, updated, perm)
+}
+
+// findKeyValue returns the index of the first entry for key in the section
+// selected by o, or -1, and the index at which a new entry for the section
+// belongs, or -1 if the section does not exist.
+func findKeyValue(lines []Line, key string, o KeyValueOptions) (int, int) {
+	section := ""
+	insert := -1
+	if o.Section == "" {
+		insert = 0
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line.Text)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if section == o.Section && insert < 0 {
+				insert = i + 1
+			}
+
+			continue
+		}
+
+		if section != o.Section || trimmed == "" {
+			continue
+		}
+
+		insert = i + 1
+		if k, _, ok := parseKeyValue(line.Text, o.Separator); ok && k == key {
+			return i, insert
+		}
+	}
+
+	return -1, insert
+}
+
+// parseKeyValue splits an entry line into its key and the offset of its
+// value, skipping comments and lines without a separator.
+func parseKeyValue(text, sep string) (string, int, bool) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || trimmed[0] == '#' || trimmed[0] == ';' {
+		return "", 0, false
+	}
+
+	i := strings.Index(text, strings.TrimSpace(sep))
+	if i < 0 {
+		return "", 0, false
+	}
+
+	key := strings.TrimSpace(text[:i])
+	if key == "" {
+		return "", 0, false
+	}
+
+	at := i + len(strings.TrimSpace(sep))
+	for at < len(text) && (text[at] == ' ' || text[at] == '\t') {
+		at++
+	}
+
+	return key, at, true
+}
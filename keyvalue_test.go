@@ -0,0 +1,58 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyValueEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(path, []byte("# settings\nNAME = \"demo\"\nPORT=80\n"), 0644)
+
+	value, ok, err := xfs.GetKeyValue(path, "NAME", nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "demo", value)
+
+	changed, err := xfs.SetKeyValue(path, "PORT", "8080", nil)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = xfs.SetKeyValue(path, "PORT", "8080", nil)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	changed, err = xfs.SetKeyValue(path, "DEBUG", "1", nil)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "# settings\nNAME = \"demo\"\nPORT=8080\nDEBUG=1\n", string(data))
+
+	_, ok, err = xfs.GetKeyValue(path, "MISSING", nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestKeyValueIni(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.ini")
+	os.WriteFile(path, []byte("top = 1\n\n[server]\n; port\nport = 80\n\n[client]\nport = 81\n"), 0644)
+
+	opts := &xfs.KeyValueOptions{Separator: " = ", Section: "client"}
+	value, ok, _ := xfs.GetKeyValue(path, "port", opts)
+	assert.True(t, ok)
+	assert.Equal(t, "81", value)
+
+	xfs.SetKeyValue(path, "host", "example.com", &xfs.KeyValueOptions{Separator: " = ", Section: "server"})
+	xfs.SetKeyValue(path, "port", "82", opts)
+	xfs.SetKeyValue(path, "level", "debug", &xfs.KeyValueOptions{Separator: " = ", Section: "log"})
+	xfs.SetKeyValue(path, "name", "x", &xfs.KeyValueOptions{Separator: " = "})
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "top = 1\nname = x\n\n[server]\n; port\nport = 80\nhost = example.com\n\n"+
+		"[client]\nport = 82\n\n[log]\nlevel = debug\n", string(data))
+}
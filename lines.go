@@ -0,0 +1,306 @@
+package xfs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+)
+
+// ErrLineTooLong is returned by ReadFileLinesOpt when a line exceeds the
+// configured maximum size.
+var ErrLineTooLong = errors.New("xfs: line too long")
+
+// DefaultEOL is the line terminator used by WriteFileLines, and by the line
+// editing functions for files without terminators. It defaults to EOL, the
+// terminator of the platform, and can be set to "\n" or "\r\n" when the
+// files are meant for another operating system, e.g. shell scripts generated
+// on Windows. Set it during initialization, before files are written. Use
+// WriteFileLinesSep to choose the terminator for a single call.
+var DefaultEOL = EOL
+
+// LinesOptions controls how ReadFileLinesOpt splits a file into lines.
+type LinesOptions struct {
+	// MaxLineSize is the maximum size of a line in bytes, not counting its
+	// terminator. Zero means no limit.
+	MaxLineSize int
+}
+
+// ReadFileLinesOpt reads the named file and returns its lines without their
+// terminators. Lines end with "\n" or "\r\n", and a final line without a
+// terminator is included. If a line is longer than MaxLineSize, reading stops
+// and the error wraps ErrLineTooLong.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - opts: the line options, nil means no line size limit
+func ReadFileLinesOpt(filename string, opts *LinesOptions) ([]string, error) {
+	o := LinesOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	done := instrument("readfile", filename)
+	lines, n, err := readFileLines(filename, &o)
+	done(n, err)
+	return lines, err
+}
+
+// readFileLines implements ReadFileLinesOpt and also returns the number of
+// bytes read.
+func readFileLines(filename string, o *LinesOptions) ([]string, int64, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var lines []string
+	var n int64
+	r := bufio.NewReader(f)
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		n += int64(len(chunk))
+		line = append(line, chunk...)
+		if o.MaxLineSize > 0 && len(bytes.TrimRight(line, "\r\n")) > o.MaxLineSize {
+			return nil, n, &os.PathError{Op: "read", Path: filename, Err: ErrLineTooLong}
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		if err != nil && err != io.EOF {
+			return nil, n, err
+		}
+
+		if len(line) > 0 {
+			line = bytes.TrimSuffix(line, []byte("\n"))
+			line = bytes.TrimSuffix(line, []byte("\r"))
+			lines = append(lines, string(line))
+		}
+
+		if err == io.EOF {
+			return lines, n, nil
+		}
+
+		line = line[:0]
+	}
+}
+
+// Line is a line of text together with the terminator that ended it.
+type Line struct {
+	// Text is the content of the line without its terminator.
+	Text string
+	// EOL is the terminator, "\n" or "\r\n", or empty for a final line
+	// without one.
+	EOL string
+}
+
+// ReadFileLinesKeepEOL reads the named file and returns its lines together
+// with their original terminators, so that WriteFileLinesRaw can write them
+// back byte for byte. A file ending in a terminator has no empty final line.
+//
+// Parameters:
+//   - filename: the name of the file
+func ReadFileLinesKeepEOL(filename string) ([]Line, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []Line
+	for len(data) > 0 {
+		line := Line{}
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			line.Text = string(data)
+			data = nil
+		} else {
+			line.Text = string(data[:i])
+			line.EOL = "\n"
+			if i > 0 && data[i-1] == '\r' {
+				line.Text = line.Text[:i-1]
+				line.EOL = "\r\n"
+			}
+
+			data = data[i+1:]
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// WriteFileLinesRaw writes the lines to the named file, each followed by its
+// own terminator, creating the file if necessary. Lines added without a
+// terminator are written as is, so a caller inserting lines should set EOL,
+// for example to the terminator of a neighbouring line.
+//
+// If the file does not exist, WriteFileLinesRaw creates it with permissions
+// perm (before umask); otherwise it truncates it before writing, without
+// changing permissions.
+//
+// The data is flushed to stable storage according to DefaultDurability.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - lines: the lines to write
+//   - perm: the file permissions
+func WriteFileLinesRaw(filename string, lines []Line, perm FileMode) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.WriteString(line.Text)
+		buf.WriteString(line.EOL)
+	}
+
+	done := instrument("writefile", filename)
+	err := writeFile(filename, buf.Bytes(), perm, DefaultDurability)
+	done(int64(buf.Len()), err)
+	return err
+}
+
+// EnsureLineInFile appends line to the named file unless a line with exactly
+// that text is already present, and reports whether the file changed. The new
+// line uses the terminator of the file's first line, or DefaultEOL for a file
+// without terminators. A missing file is created with permissions 0644
+// (before umask).
+//
+// The file is rewritten atomically with its current permissions, so readers
+// never observe a partial update.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - line: the line to ensure, without a terminator
+func EnsureLineInFile(filename string, line string) (bool, error) {
+	lines, perm, err := readLinesForEdit(filename)
+	if err != nil {
+		return false, err
+	}
+
+	eol := DefaultEOL
+	for _, l := range lines {
+		if l.Text == line {
+			return false, nil
+		}
+	}
+
+	if len(lines) > 0 && lines[0].EOL != "" {
+		eol = lines[0].EOL
+	}
+
+	if n := len(lines); n > 0 && lines[n-1].EOL == "" {
+		lines[n-1].EOL = eol
+	}
+
+	lines = append(lines, Line{Text: line, EOL: eol})
+	return true, writeLinesAtomic(filename, lines, perm)
+}
+
+// RemoveLineFromFile removes every line with exactly the given text from the
+// named file and reports whether the file changed. A missing file is left
+// missing. The file is rewritten atomically with its current permissions.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - line: the line to remove, without a terminator
+func RemoveLineFromFile(filename string, line string) (bool, error) {
+	lines, perm, err := readLinesForEdit(filename)
+	if err != nil || len(lines) == 0 {
+		return false, err
+	}
+
+	kept := lines[:0]
+	for _, l := range lines {
+		if l.Text != line {
+			kept = append(kept, l)
+		}
+	}
+
+	if len(kept) == len(lines) {
+		return false, nil
+	}
+
+	return true, writeLinesAtomic(filename, kept, perm)
+}
+
+// readLinesForEdit reads the lines and permissions of filename. A missing
+// file has no lines and zero permissions.
+func readLinesForEdit(filename string) ([]Line, FileMode, error) {
+	info, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lines, err := ReadFileLinesKeepEOL(filename)
+	return lines, info.Mode().Perm(), err
+}
+
+// writeLinesAtomic replaces filename with lines, keeping the permissions perm
+// of the existing file. Zero permissions create a new file with 0644 (before
+// umask).
+func writeLinesAtomic(filename string, lines []Line, perm FileMode) error {
+	w, err := NewAtomicWriter(filename, 0644, nil)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	for _, line := range lines {
+		if _, err := w.WriteString(line.Text + line.EOL); err != nil {
+			return err
+		}
+	}
+
+	if perm != 0 {
+		if err := w.f.Chmod(perm); err != nil {
+			return err
+		}
+	}
+
+	return w.Commit()
+}
+
+// DetectEOL returns the terminator of the first line of the named file, "\n"
+// or "\r\n", so that edits and generated files can match the existing line
+// endings. It returns an empty string for a file without line terminators.
+//
+// Parameters:
+//   - filename: the name of the file
+func DetectEOL(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	prev := byte(0)
+	for {
+		c, err := r.ReadByte()
+		if err == io.EOF {
+			return "", nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if c == '\n' {
+			if prev == '\r' {
+				return "\r\n", nil
+			}
+
+			return "\n", nil
+		}
+
+		prev = c
+	}
+}
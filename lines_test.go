@@ -0,0 +1,132 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFileLinesLong(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "long")
+	long := strings.Repeat("x", 100*1024)
+	os.WriteFile(path, []byte("a\r\n"+long+"\n\nb"), 0644)
+
+	lines, err := xfs.ReadFileLines(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", long, "", "b"}, lines)
+}
+
+func TestReadFileLinesOpt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines")
+	os.WriteFile(path, []byte("short\nmuch too long\n"), 0644)
+
+	_, err := xfs.ReadFileLinesOpt(path, &xfs.LinesOptions{MaxLineSize: 8})
+	assert.ErrorIs(t, err, xfs.ErrLineTooLong)
+
+	lines, err := xfs.ReadFileLinesOpt(path, &xfs.LinesOptions{MaxLineSize: 13})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"short", "much too long"}, lines)
+}
+
+func TestReadFileLinesKeepEOL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mixed")
+	content := "one\r\ntwo\n\r\nlast"
+	os.WriteFile(path, []byte(content), 0644)
+
+	lines, err := xfs.ReadFileLinesKeepEOL(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []xfs.Line{
+		{Text: "one", EOL: "\r\n"},
+		{Text: "two", EOL: "\n"},
+		{Text: "", EOL: "\r\n"},
+		{Text: "last"},
+	}, lines)
+
+	lines[1].Text = "TWO"
+	assert.NoError(t, xfs.WriteFileLinesRaw(path, lines, 0644))
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "one\r\nTWO\n\r\nlast", string(data))
+}
+
+func TestEnsureLineInFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	os.WriteFile(path, []byte("127.0.0.1 localhost\r\n::1 localhost"), 0600)
+
+	changed, err := xfs.EnsureLineInFile(path, "10.0.0.1 db")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = xfs.EnsureLineInFile(path, "10.0.0.1 db")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "127.0.0.1 localhost\r\n::1 localhost\r\n10.0.0.1 db\r\n", string(data))
+
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	changed, err = xfs.RemoveLineFromFile(path, "::1 localhost")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = xfs.RemoveLineFromFile(path, "::1 localhost")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	data, _ = os.ReadFile(path)
+	assert.Equal(t, "127.0.0.1 localhost\r\n10.0.0.1 db\r\n", string(data))
+
+	created := filepath.Join(t.TempDir(), "new")
+	changed, err = xfs.EnsureLineInFile(created, "line")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	data, _ = os.ReadFile(created)
+	assert.Equal(t, "line"+xfs.EOL, string(data))
+}
+
+func TestDetectEOL(t *testing.T) {
+	dir := t.TempDir()
+	unix := filepath.Join(dir, "unix")
+	dos := filepath.Join(dir, "dos")
+	none := filepath.Join(dir, "none")
+	os.WriteFile(unix, []byte("a\nb\r\n"), 0644)
+	os.WriteFile(dos, []byte("a\r\nb\n"), 0644)
+	os.WriteFile(none, []byte("a"), 0644)
+
+	eol, err := xfs.DetectEOL(unix)
+	assert.NoError(t, err)
+	assert.Equal(t, "\n", eol)
+
+	eol, _ = xfs.DetectEOL(dos)
+	assert.Equal(t, "\r\n", eol)
+
+	eol, _ = xfs.DetectEOL(none)
+	assert.Equal(t, "", eol)
+
+	_, err = xfs.DetectEOL(filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestDefaultEOL(t *testing.T) {
+	defer func(eol string) { xfs.DefaultEOL = eol }(xfs.DefaultEOL)
+	xfs.DefaultEOL = "\r\n"
+
+	path := filepath.Join(t.TempDir(), "script.bat")
+	assert.NoError(t, xfs.WriteFileLines(path, []string{"a", "b"}, 0644))
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "a\r\nb\r\n", string(data))
+
+	env := filepath.Join(t.TempDir(), "app.env")
+	_, err := xfs.SetKeyValue(env, "A", "1", nil)
+	assert.NoError(t, err)
+
+	data, _ = os.ReadFile(env)
+	assert.Equal(t, "A=1\r\n", string(data))
+}
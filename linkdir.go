@@ -0,0 +1,61 @@
+package xfs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LinkDir recreates the tree rooted at src at dst using hard links instead of
+// copying file data, like cp --link or rsync --link-dest, so that a snapshot
+// of a large tree takes no time and no extra space. Directories are created
+// with the permissions of their source and symbolic links are recreated with
+// the same target. Where a hard link is not possible, for example because
+// dst is on another device, the file is copied instead.
+//
+// Since linked files share their content, modifying a file in place under
+// either tree changes it in both; replace files instead, for example with
+// WriteFileAtomic. LinkDir fails if a destination file already exists.
+//
+// Parameters:
+//   - src: the source directory
+//   - dst: the destination directory
+func LinkDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return EnsureDir(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			return os.Symlink(link, target)
+		}
+
+		err = os.Link(path, target)
+		if err != nil && cannotLink(err) {
+			if _, statErr := os.Lstat(target); os.IsNotExist(statErr) {
+				return copyFile(path, target, info, false)
+			}
+		}
+
+		return err
+	})
+}
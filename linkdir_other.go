@@ -0,0 +1,17 @@
+//go:build !unix && !windows
+
+package xfs
+
+// cannotLink reports whether a hard link failed because the file system or
+// the location of the link does not allow it. Without hard link support,
+// every failure is treated that way.
+func cannotLink(err error) bool {
+	return true
+}
+
+// crossDevice reports whether a rename failed because the paths are on
+// different file systems. Without an error code to tell, every failure is
+// treated that way.
+func crossDevice(err error) bool {
+	return true
+}
@@ -0,0 +1,36 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkDir(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	dst := filepath.Join(t.TempDir(), "dst")
+	os.MkdirAll(filepath.Join(src, "sub"), 0755)
+	os.WriteFile(filepath.Join(src, "sub", "file"), []byte("data"), 0644)
+	if runtime.GOOS != "windows" {
+		os.Symlink("sub/file", filepath.Join(src, "link"))
+	}
+
+	assert.NoError(t, xfs.LinkDir(src, dst))
+
+	a, _ := os.Stat(filepath.Join(src, "sub", "file"))
+	b, err := os.Stat(filepath.Join(dst, "sub", "file"))
+	assert.NoError(t, err)
+	assert.True(t, os.SameFile(a, b))
+
+	if runtime.GOOS != "windows" {
+		target, err := os.Readlink(filepath.Join(dst, "link"))
+		assert.NoError(t, err)
+		assert.Equal(t, "sub/file", target)
+	}
+
+	assert.Error(t, xfs.LinkDir(src, dst))
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package xfs
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// cannotLink reports whether a hard link failed because the file system or
+// the location of the link does not allow it, rather than because of the
+// paths involved.
+func cannotLink(err error) bool {
+	return errors.Is(err, unix.EXDEV) || errors.Is(err, unix.EPERM) || errors.Is(err, unix.EMLINK) ||
+		errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EOPNOTSUPP)
+}
+
+// crossDevice reports whether a rename or hard link failed because the paths
+// are on different file systems.
+func crossDevice(err error) bool {
+	return errors.Is(err, unix.EXDEV)
+}
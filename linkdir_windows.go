@@ -0,0 +1,24 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// cannotLink reports whether a hard link failed because the file system or
+// the location of the link does not allow it, rather than because of the
+// paths involved.
+func cannotLink(err error) bool {
+	return errors.Is(err, windows.ERROR_NOT_SAME_DEVICE) || errors.Is(err, windows.ERROR_INVALID_FUNCTION) ||
+		errors.Is(err, windows.ERROR_NOT_SUPPORTED) || errors.Is(err, windows.ERROR_TOO_MANY_LINKS)
+}
+
+// crossDevice reports whether a rename or hard link failed because the paths
+// are on different volumes.
+func crossDevice(err error) bool {
+	return errors.Is(err, windows.ERROR_NOT_SAME_DEVICE)
+}
@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package xfs
+
+import (
+	"errors"
+	"os"
+)
+
+func isFileLocked(filename string) (bool, error) {
+	if _, err := os.Stat(filename); err != nil {
+		return false, err
+	}
+
+	return false, &os.PathError{Op: "getlk", Path: filename, Err: &unsupportedError{errors.ErrUnsupported}}
+}
@@ -0,0 +1,61 @@
+package xfs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing/fstest"
+)
+
+// LoadMapFS reads the directory tree rooted at root into an fstest.MapFS,
+// keeping the size, mode and modification time of every entry. The result is
+// an in-memory, hermetic copy of the tree for tests of code that consumes an
+// fs.FS. The root itself is not included and the keys use forward slashes.
+//
+// Symbolic links to files are stored as regular files with the contents of
+// their target; links to directories and special files are skipped.
+//
+// Parameters:
+//   - root: the directory to load
+func LoadMapFS(root string) (fstest.MapFS, error) {
+	fsys := fstest.MapFS{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(rel)
+		switch {
+		case d.IsDir():
+			fsys[name] = &fstest.MapFile{Mode: info.Mode(), ModTime: info.ModTime()}
+		case info.Mode().IsRegular():
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			fsys[name] = &fstest.MapFile{Data: data, Mode: info.Mode(), ModTime: info.ModTime()}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fsys, nil
+}
@@ -0,0 +1,32 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMapFS(t *testing.T) {
+	root := t.TempDir()
+	xfs.MkdirAllDefault(filepath.Join(root, "dir", "sub"))
+	os.WriteFile(filepath.Join(root, "a.txt"), []byte("hello"), 0600)
+	os.WriteFile(filepath.Join(root, "dir", "sub", "b.txt"), []byte("world"), 0644)
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	os.Chtimes(filepath.Join(root, "a.txt"), mtime, mtime)
+
+	fsys, err := xfs.LoadMapFS(root)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(fsys["a.txt"].Data))
+	assert.Equal(t, "world", string(fsys["dir/sub/b.txt"].Data))
+	assert.True(t, fsys["a.txt"].ModTime.Equal(mtime))
+	assert.True(t, fsys["dir"].Mode.IsDir())
+	assert.NoError(t, fstest.TestFS(fsys, "a.txt", "dir/sub/b.txt"))
+
+	_, err = xfs.LoadMapFS(filepath.Join(root, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,233 @@
+package xfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ErrMergeConflict is returned by MergeDir with the MergeError strategy when
+// the source and destination trees conflict.
+var ErrMergeConflict = errors.New("xfs: merge conflict")
+
+// MergeStrategy decides how MergeDir resolves a conflict, that is a source
+// entry whose destination already exists and is not a directory merged with
+// a source directory.
+type MergeStrategy int
+
+const (
+	// MergeSkip keeps the destination entry.
+	MergeSkip MergeStrategy = iota
+	// MergeOverwrite replaces the destination entry with the source entry.
+	MergeOverwrite
+	// MergeOverwriteIfNewer replaces the destination entry if the source
+	// entry was modified more recently, and keeps it otherwise.
+	MergeOverwriteIfNewer
+	// MergeRename keeps both by copying the source entry next to the
+	// destination under the first free name of the form "name (1).ext".
+	MergeRename
+	// MergeError changes nothing and fails with ErrMergeConflict if there is
+	// any conflict.
+	MergeError
+)
+
+// String returns the name of the strategy.
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeSkip:
+		return "skip"
+	case MergeOverwrite:
+		return "overwrite"
+	case MergeOverwriteIfNewer:
+		return "overwrite-if-newer"
+	case MergeRename:
+		return "rename"
+	case MergeError:
+		return "error"
+	}
+
+	return "unknown"
+}
+
+// MergeConflict describes a conflict found by MergeDir and how it was
+// resolved.
+type MergeConflict struct {
+	// Path is the slash separated path of the entry relative to the source
+	// and destination directories.
+	Path string
+	// Action is the resolution applied: MergeSkip, MergeOverwrite or
+	// MergeRename, or MergeError if nothing was changed.
+	Action MergeStrategy
+	// RenamedTo is the name the source entry was copied to with MergeRename.
+	RenamedTo string
+}
+
+// MergeDir copies the tree rooted at src into dst, merging directories that
+// exist in both and resolving every other collision with strategy. It
+// returns the conflicts it encountered, including those it resolved. With
+// MergeError, the trees are checked first and nothing is copied if any
+// conflict is found. Like CopyDir, symbolic links are copied as the content
+// of their targets, linked directories are merged like directories, and
+// links that lead back into a directory being merged fail with an error.
+//
+// Parameters:
+//   - src: the source directory
+//   - dst: the destination directory
+//   - strategy: how to resolve conflicts
+func MergeDir(src, dst string, strategy MergeStrategy) ([]MergeConflict, error) {
+	if strategy == MergeError {
+		conflicts, err := mergeDir(src, dst, strategy, false, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(conflicts) > 0 {
+			return conflicts, &os.PathError{Op: "merge", Path: filepath.Join(dst, filepath.FromSlash(conflicts[0].Path)), Err: ErrMergeConflict}
+		}
+	}
+
+	return mergeDir(src, dst, strategy, true, nil)
+}
+
+// mergeDir merges the tree rooted at src into dst. chain holds the
+// identities of the directories entered by following links, to detect
+// loops, like for copyDir.
+func mergeDir(src, dst string, strategy MergeStrategy, apply bool, chain []FileIdentity) ([]MergeConflict, error) {
+	if info, err := os.Stat(src); err == nil {
+		if id, err := FileIDInfo(src, info); err == nil {
+			for _, seen := range chain {
+				if seen == id {
+					return nil, &os.PathError{Op: "merge", Path: src, Err: errSymlinkLoop}
+				}
+			}
+
+			chain = append(chain, id)
+		}
+	}
+
+	var conflicts []MergeConflict
+	err := filepath.Walk(src, func(path string, info FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+		if info.Mode()&os.ModeSymlink != 0 && path != src {
+			// Links are merged as their targets; Walk reports the link
+			// itself and does not enter linked directories.
+			if info, err = os.Stat(path); err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				resolved, err := filepath.EvalSymlinks(path)
+				if err != nil {
+					return err
+				}
+
+				sub, err := mergeDir(resolved, target, strategy, apply, chain)
+				for _, c := range sub {
+					c.Path = filepath.ToSlash(filepath.Join(rel, filepath.FromSlash(c.Path)))
+					conflicts = append(conflicts, c)
+				}
+
+				return err
+			}
+		}
+
+		existing, err := os.Stat(target)
+		if os.IsNotExist(err) {
+			if !apply {
+				return skipDir(info)
+			}
+
+			if info.IsDir() {
+				return EnsureDir(target, info.Mode())
+			}
+
+			return copyFile(path, target, info, true)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && existing.IsDir() {
+			return nil
+		}
+
+		c := MergeConflict{Path: filepath.ToSlash(rel), Action: strategy}
+		if strategy == MergeOverwriteIfNewer {
+			c.Action = MergeSkip
+			if info.ModTime().After(existing.ModTime()) {
+				c.Action = MergeOverwrite
+			}
+		}
+
+		if apply {
+			renamed, err := resolveConflict(path, target, info, existing, c.Action)
+			if err != nil {
+				return err
+			}
+
+			c.RenamedTo = renamed
+		}
+
+		conflicts = append(conflicts, c)
+		return skipDir(info)
+	})
+
+	return conflicts, err
+}
+
+// resolveConflict applies action to the conflicting source entry and
+// returns the new name it was copied to for MergeRename.
+func resolveConflict(path, target string, info, existing FileInfo, action MergeStrategy) (string, error) {
+	switch action {
+	case MergeOverwrite:
+		if info.IsDir() || existing.IsDir() {
+			if err := os.RemoveAll(target); err != nil {
+				return "", err
+			}
+		}
+
+		return "", copyEntry(path, target, info)
+	case MergeRename:
+		renamed, err := UniquePath(target)
+		if err != nil {
+			return "", err
+		}
+
+		if info.IsDir() {
+			if err := os.Remove(renamed); err != nil {
+				return "", err
+			}
+		}
+
+		return renamed, copyEntry(path, renamed, info)
+	}
+
+	return "", nil
+}
+
+func copyEntry(path, target string, info FileInfo) error {
+	if info.IsDir() {
+		return CopyDir(path, target, true)
+	}
+
+	return copyFile(path, target, info, true)
+}
+
+func skipDir(info FileInfo) error {
+	if info.IsDir() {
+		return fs.SkipDir
+	}
+
+	return nil
+}
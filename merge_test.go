@@ -0,0 +1,105 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func mergeFixture(t *testing.T) (string, string) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "sub"), 0755)
+	os.MkdirAll(filepath.Join(dst, "sub"), 0755)
+	os.WriteFile(filepath.Join(src, "new.txt"), []byte("new"), 0644)
+	os.WriteFile(filepath.Join(src, "sub", "same.txt"), []byte("src"), 0644)
+	os.WriteFile(filepath.Join(dst, "sub", "same.txt"), []byte("dst"), 0644)
+	os.WriteFile(filepath.Join(dst, "sub", "keep.txt"), []byte("keep"), 0644)
+
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(dst, "sub", "same.txt"), old, old)
+	return src, dst
+}
+
+func readString(path string) string {
+	data, _ := os.ReadFile(path)
+	return string(data)
+}
+
+func TestMergeDir(t *testing.T) {
+	src, dst := mergeFixture(t)
+	conflicts, err := xfs.MergeDir(src, dst, xfs.MergeSkip)
+	assert.NoError(t, err)
+	assert.Equal(t, []xfs.MergeConflict{{Path: "sub/same.txt", Action: xfs.MergeSkip}}, conflicts)
+	assert.Equal(t, "new", readString(filepath.Join(dst, "new.txt")))
+	assert.Equal(t, "dst", readString(filepath.Join(dst, "sub", "same.txt")))
+	assert.Equal(t, "keep", readString(filepath.Join(dst, "sub", "keep.txt")))
+
+	src, dst = mergeFixture(t)
+	conflicts, err = xfs.MergeDir(src, dst, xfs.MergeOverwriteIfNewer)
+	assert.NoError(t, err)
+	assert.Equal(t, xfs.MergeOverwrite, conflicts[0].Action)
+	assert.Equal(t, "src", readString(filepath.Join(dst, "sub", "same.txt")))
+
+	src, dst = mergeFixture(t)
+	conflicts, err = xfs.MergeDir(src, dst, xfs.MergeRename)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dst, "sub", "same (1).txt"), conflicts[0].RenamedTo)
+	assert.Equal(t, "src", readString(conflicts[0].RenamedTo))
+	assert.Equal(t, "dst", readString(filepath.Join(dst, "sub", "same.txt")))
+}
+
+func TestMergeDirError(t *testing.T) {
+	src, dst := mergeFixture(t)
+	conflicts, err := xfs.MergeDir(src, dst, xfs.MergeError)
+	assert.ErrorIs(t, err, xfs.ErrMergeConflict)
+	assert.Len(t, conflicts, 1)
+	assert.NoFileExists(t, filepath.Join(dst, "new.txt"))
+}
+
+func TestMergeDirSymlinks(t *testing.T) {
+	if !xfs.CanSymlink() {
+		t.Skip("symbolic links are not supported")
+	}
+
+	outside := t.TempDir()
+	os.MkdirAll(filepath.Join(outside, "lib"), 0755)
+	os.WriteFile(filepath.Join(outside, "lib", "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0600)
+
+	src, dst := mergeFixture(t)
+	os.Symlink(filepath.Join(outside, "lib"), filepath.Join(src, "dirlink"))
+	os.Symlink(filepath.Join(outside, "secret"), filepath.Join(src, "filelink"))
+	os.Symlink(filepath.Join(outside, "lib"), filepath.Join(src, "sub", "same"))
+	os.MkdirAll(filepath.Join(dst, "sub", "same"), 0755)
+	os.WriteFile(filepath.Join(dst, "sub", "same", "a.txt"), []byte("dst"), 0644)
+
+	conflicts, err := xfs.MergeDir(src, dst, xfs.MergeSkip)
+	assert.NoError(t, err)
+	assert.Equal(t, []xfs.MergeConflict{
+		{Path: "sub/same/a.txt", Action: xfs.MergeSkip},
+		{Path: "sub/same.txt", Action: xfs.MergeSkip},
+	}, conflicts)
+
+	info, err := os.Lstat(filepath.Join(dst, "dirlink"))
+	assert.NoError(t, err)
+	assert.True(t, info.IsDir())
+	assert.Equal(t, "a", readString(filepath.Join(dst, "dirlink", "a.txt")))
+
+	info, err = os.Lstat(filepath.Join(dst, "filelink"))
+	assert.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular())
+	assert.Equal(t, "secret", readString(filepath.Join(dst, "filelink")))
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	os.Symlink(src, filepath.Join(src, "sub", "loop"))
+	_, err = xfs.MergeDir(src, t.TempDir(), xfs.MergeSkip)
+	assert.Error(t, err)
+}
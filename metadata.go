@@ -0,0 +1,260 @@
+package xfs
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// modeBits are the permission and special bits restored by ApplyMetadata.
+const modeBits = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+
+// MetadataEntry records the metadata of a single entry captured by
+// SaveMetadata.
+type MetadataEntry struct {
+	// Path is the slash separated path relative to the root.
+	Path string `json:"path"`
+	// Mode is the file mode, including the type bits.
+	Mode FileMode `json:"mode"`
+	// UID is the numeric user ID of the owner, or -1 if unknown.
+	UID int `json:"uid"`
+	// GID is the numeric group ID of the group, or -1 if unknown.
+	GID int `json:"gid"`
+	// User is the name of the owner, if it could be resolved.
+	User string `json:"user,omitempty"`
+	// Group is the name of the group, if it could be resolved.
+	Group string `json:"group,omitempty"`
+	// Target is the target of a symbolic link.
+	Target string `json:"target,omitempty"`
+}
+
+// MetadataManifest is a serializable record of the permissions, owners and
+// symbolic links of a directory tree.
+type MetadataManifest struct {
+	Entries []MetadataEntry `json:"entries"`
+}
+
+// SaveMetadata records the mode, owner and group of every entry below root
+// and the target of every symbolic link, in lexical order. It complements
+// storage such as zip archives or git, which lose this information, and is
+// restored with ApplyMetadata. On platforms without file ownership the owner
+// fields are left unknown.
+//
+// Parameters:
+//   - root: the directory to record
+func SaveMetadata(root string) (*MetadataManifest, error) {
+	m := &MetadataManifest{}
+	err := filepath.WalkDir(root, func(path string, d DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		entry := MetadataEntry{Path: filepath.ToSlash(rel), Mode: info.Mode(), UID: -1, GID: -1}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			entry.Target, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		} else {
+			o, err := Owner(path)
+			if err != nil && !errors.Is(err, errors.ErrUnsupported) {
+				return err
+			}
+
+			if o != nil {
+				entry.UID, entry.GID, entry.User, entry.Group = o.UID, o.GID, o.User, o.Group
+			}
+		}
+
+		m.Entries = append(m.Entries, entry)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// ApplyMetadata restores the metadata recorded by SaveMetadata to the tree
+// rooted at root. Symbolic links are recreated with their recorded target,
+// replacing a file or link at their path but never a directory. Entries that
+// no longer exist are skipped, and entries whose path is not a valid relative
+// path inside root are rejected with an error. Owners are restored by name
+// when the name was recorded and by ID otherwise, and only where they differ
+// from the current owner, so applying a manifest to one's own files does not
+// require privileges.
+//
+// Parameters:
+//   - root: the directory to update
+//   - manifest: the manifest to apply
+func ApplyMetadata(root string, manifest *MetadataManifest) error {
+	for _, e := range manifest.Entries {
+		if e.Mode&fs.ModeSymlink == 0 {
+			continue
+		}
+
+		path, err := metadataPath(root, e)
+		if err != nil {
+			return err
+		}
+
+		if err := applySymlink(path, e.Target); err != nil {
+			return err
+		}
+	}
+
+	// Modes are applied deepest first so that restoring a read-only
+	// directory cannot get in the way of its children.
+	for i := len(manifest.Entries) - 1; i >= 0; i-- {
+		e := manifest.Entries[i]
+		if e.Mode&fs.ModeSymlink != 0 {
+			continue
+		}
+
+		path, err := metadataPath(root, e)
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Lstat(path); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return err
+		}
+
+		if err := applyOwner(path, e); err != nil {
+			return err
+		}
+
+		if err := os.Chmod(path, e.Mode&modeBits); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// metadataPath returns the path of e below root. It rejects paths that are
+// not valid relative paths and paths that leave root, including through
+// symbolic links restored earlier; for a symbolic link only its parent is
+// checked since the link itself may point anywhere.
+func metadataPath(root string, e MetadataEntry) (string, error) {
+	if !fs.ValidPath(e.Path) {
+		return "", &os.PathError{Op: "applymetadata", Path: e.Path, Err: fs.ErrInvalid}
+	}
+
+	path := filepath.Join(root, filepath.FromSlash(e.Path))
+	check := path
+	if e.Mode&fs.ModeSymlink != 0 {
+		check = filepath.Dir(path)
+	}
+
+	if !IsSubPath(root, check) {
+		return "", &os.PathError{Op: "applymetadata", Path: path, Err: ErrNotSubPath}
+	}
+
+	return path, nil
+}
+
+func applySymlink(path, target string) error {
+	info, err := os.Lstat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	case info.Mode()&fs.ModeSymlink != 0:
+		if current, err := os.Readlink(path); err == nil && current == target {
+			return nil
+		}
+
+		fallthrough
+	case info.Mode().IsRegular():
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	default:
+		return &os.PathError{Op: "symlink", Path: path, Err: os.ErrExist}
+	}
+
+	return os.Symlink(target, path)
+}
+
+func applyOwner(path string, e MetadataEntry) error {
+	if e.User == "" && e.Group == "" && e.UID < 0 && e.GID < 0 {
+		return nil
+	}
+
+	current, err := Owner(path)
+	if err != nil {
+		if errors.Is(err, errors.ErrUnsupported) {
+			return nil
+		}
+
+		return err
+	}
+
+	if e.User != "" || e.Group != "" {
+		if e.User == current.User && e.Group == current.Group {
+			return nil
+		}
+
+		return ChownName(path, e.User, e.Group)
+	}
+
+	if e.UID == current.UID && e.GID == current.GID {
+		return nil
+	}
+
+	return Chown(path, e.UID, e.GID)
+}
+
+// Save writes the manifest to the named file as JSON.
+//
+// Parameters:
+//   - filename: the name of the file
+func (m *MetadataManifest) Save(filename string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0644)
+}
+
+// LoadMetadata reads a manifest previously written by MetadataManifest.Save.
+//
+// Parameters:
+//   - filename: the name of the file
+func LoadMetadata(filename string) (*MetadataManifest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MetadataManifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
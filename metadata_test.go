@@ -0,0 +1,93 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveMetadata(t *testing.T) {
+	root := t.TempDir()
+	xfs.MkdirAllDefault(filepath.Join(root, "bin"))
+	os.WriteFile(filepath.Join(root, "bin", "tool"), []byte("x"), 0755)
+	os.Symlink(filepath.Join("bin", "tool"), filepath.Join(root, "tool"))
+
+	m, err := xfs.SaveMetadata(root)
+	assert.NoError(t, err)
+	assert.Len(t, m.Entries, 3)
+	assert.Equal(t, "bin/tool", m.Entries[1].Path)
+	assert.Equal(t, filepath.Join("bin", "tool"), m.Entries[2].Target)
+
+	filename := filepath.Join(t.TempDir(), "meta.json")
+	assert.NoError(t, m.Save(filename))
+	loaded, err := xfs.LoadMetadata(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, m, loaded)
+}
+
+func TestApplyMetadata(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not preserved on Windows")
+	}
+
+	root := t.TempDir()
+	xfs.MkdirAllDefault(filepath.Join(root, "bin"))
+	tool := filepath.Join(root, "bin", "tool")
+	os.WriteFile(tool, []byte("x"), 0755)
+	os.Symlink(filepath.Join("bin", "tool"), filepath.Join(root, "tool"))
+
+	m, err := xfs.SaveMetadata(root)
+	assert.NoError(t, err)
+
+	os.Chmod(tool, 0644)
+	os.Remove(filepath.Join(root, "tool"))
+
+	assert.NoError(t, xfs.ApplyMetadata(root, m))
+
+	info, _ := os.Stat(tool)
+	assert.Equal(t, xfs.FileMode(0755), info.Mode().Perm())
+
+	target, err := os.Readlink(filepath.Join(root, "tool"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("bin", "tool"), target)
+}
+
+func TestApplyMetadataOutsideRoot(t *testing.T) {
+	if !xfs.CanSymlink() {
+		t.Skip("symbolic links are not supported")
+	}
+
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	victim := filepath.Join(parent, "victim")
+	xfs.MkdirAllDefault(filepath.Join(root, "dir"))
+	xfs.MkdirAllDefault(victim)
+	os.WriteFile(filepath.Join(victim, "keep"), []byte("x"), 0644)
+
+	link := xfs.MetadataEntry{Mode: os.ModeSymlink | 0777, Target: "elsewhere", UID: -1, GID: -1}
+	for _, path := range []string{"../victim", "/victim", "dir/../../victim"} {
+		link.Path = path
+		err := xfs.ApplyMetadata(root, &xfs.MetadataManifest{Entries: []xfs.MetadataEntry{link}})
+		assert.Error(t, err, path)
+	}
+
+	link.Path = "dir"
+	err := xfs.ApplyMetadata(root, &xfs.MetadataManifest{Entries: []xfs.MetadataEntry{link}})
+	assert.ErrorIs(t, err, os.ErrExist)
+	assert.DirExists(t, filepath.Join(root, "dir"))
+
+	// A link restored by the manifest must not redirect later entries.
+	escape := xfs.MetadataEntry{Path: "escape", Mode: os.ModeSymlink | 0777, Target: victim, UID: -1, GID: -1}
+	file := xfs.MetadataEntry{Path: "escape/keep", Mode: 0600, UID: -1, GID: -1}
+	err = xfs.ApplyMetadata(root, &xfs.MetadataManifest{Entries: []xfs.MetadataEntry{escape, file}})
+	assert.ErrorIs(t, err, xfs.ErrNotSubPath)
+	assert.FileExists(t, filepath.Join(victim, "keep"))
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(filepath.Join(victim, "keep"))
+		assert.Equal(t, xfs.FileMode(0644), info.Mode().Perm())
+	}
+}
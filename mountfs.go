@@ -0,0 +1,466 @@
+package xfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCrossMount is returned by MountFS.Rename when the old and new names
+// belong to different mounts.
+var ErrCrossMount = errors.New("xfs: rename across mounts")
+
+// MountFS is a virtual file system that maps path prefixes to other file
+// systems, for example "config" to an in-memory fstest.MapFS, "data" to a
+// DirFS and "assets" to an embed.FS. Every operation is routed to the mount
+// with the longest prefix matching the name, with the name rewritten to be
+// relative to that mount. Directories leading up to a mount point exist
+// implicitly and list the mount points below them.
+//
+// Mounted file systems that do not implement WritableFS are read-only and
+// write operations on them return an error wrapping fs.ErrPermission.
+// A MountFS is safe for concurrent use.
+type MountFS struct {
+	mu     sync.RWMutex
+	mounts map[string]fs.FS
+}
+
+// NewMountFS returns an empty MountFS.
+func NewMountFS() *MountFS {
+	return &MountFS{mounts: map[string]fs.FS{}}
+}
+
+// Mount attaches fsys at prefix, replacing any file system already mounted
+// there. A leading or trailing slash in prefix is ignored, and "" or "/"
+// mounts fsys at the root.
+//
+// Parameters:
+//   - prefix: the mount point
+//   - fsys: the file system to mount
+func (m *MountFS) Mount(prefix string, fsys fs.FS) error {
+	name := cleanMountPoint(prefix)
+	if !fs.ValidPath(name) {
+		return &os.PathError{Op: "mount", Path: prefix, Err: fs.ErrInvalid}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mounts[name] = fsys
+	return nil
+}
+
+// Unmount detaches the file system mounted at prefix and reports whether
+// there was one.
+//
+// Parameters:
+//   - prefix: the mount point
+func (m *MountFS) Unmount(prefix string) bool {
+	name := cleanMountPoint(prefix)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.mounts[name]
+	delete(m.mounts, name)
+	return ok
+}
+
+func cleanMountPoint(prefix string) string {
+	name := strings.Trim(prefix, "/")
+	if name == "" {
+		return "."
+	}
+
+	return name
+}
+
+// resolve returns the file system responsible for name, the prefix it is
+// mounted at and the name relative to it. fsys is nil if no mount covers
+// name.
+func (m *MountFS) resolve(op, name string) (fsys fs.FS, mount, rel string, err error) {
+	if !fs.ValidPath(name) {
+		return nil, "", "", &os.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for prefix := name; ; prefix = path.Dir(prefix) {
+		if fsys, ok := m.mounts[prefix]; ok {
+			switch {
+			case prefix == ".":
+				return fsys, prefix, name, nil
+			case prefix == name:
+				return fsys, prefix, ".", nil
+			default:
+				return fsys, prefix, name[len(prefix)+1:], nil
+			}
+		}
+
+		if prefix == "." {
+			return nil, "", "", nil
+		}
+	}
+}
+
+// writable returns the writable file system responsible for name and the
+// prefix it is mounted at.
+func (m *MountFS) writable(op, name string) (WritableFS, string, string, error) {
+	fsys, mount, rel, err := m.resolve(op, name)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if fsys == nil {
+		if m.isVirtualDir(name) {
+			return nil, "", "", &os.PathError{Op: op, Path: name, Err: fs.ErrPermission}
+		}
+
+		return nil, "", "", &os.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	wfs, ok := fsys.(WritableFS)
+	if !ok {
+		return nil, "", "", &os.PathError{Op: op, Path: name, Err: fs.ErrPermission}
+	}
+
+	return wfs, mount, rel, nil
+}
+
+// childMounts returns the names of the entries that mount points add to the
+// directory name.
+func (m *MountFS) childMounts(name string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var children []string
+	for prefix := range m.mounts {
+		if prefix == "." || prefix == name {
+			continue
+		}
+
+		rest := prefix
+		if name != "." {
+			if !strings.HasPrefix(prefix, name+"/") {
+				continue
+			}
+
+			rest = prefix[len(name)+1:]
+		}
+
+		child, _, _ := strings.Cut(rest, "/")
+		if !slices.Contains(children, child) {
+			children = append(children, child)
+		}
+	}
+
+	slices.Sort(children)
+	return children
+}
+
+// isVirtualDir reports whether name is an implicit directory leading up to a
+// mount point.
+func (m *MountFS) isVirtualDir(name string) bool {
+	return name == "." || len(m.childMounts(name)) > 0
+}
+
+// Open opens the named file for reading.
+//
+// Parameters:
+//   - name: the name of the file
+func (m *MountFS) Open(name string) (fs.File, error) {
+	fsys, _, rel, err := m.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if fsys != nil {
+		f, err := fsys.Open(rel)
+		if err == nil && rel == "." && name != "." {
+			return &mountRoot{File: f, name: path.Base(name)}, nil
+		}
+
+		if err == nil || !errors.Is(err, fs.ErrNotExist) || !m.isVirtualDir(name) {
+			return f, mountErr(name, err)
+		}
+	}
+
+	if !m.isVirtualDir(name) {
+		return nil, &os.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries, err := m.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &virtualDir{name: name, entries: entries}, nil
+}
+
+// OpenFile opens the named file with the given flags and permission bits on
+// the mount that covers it.
+//
+// Parameters:
+//   - name: the name of the file
+//   - flag: the flags such as os.O_RDONLY or os.O_CREATE
+//   - perm: the permission bits used when creating the file
+func (m *MountFS) OpenFile(name string, flag int, perm FileMode) (WritableFile, error) {
+	wfs, _, rel, err := m.writable("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := wfs.OpenFile(rel, flag, perm)
+	return f, mountErr(name, err)
+}
+
+// Stat returns a FileInfo describing the named file.
+//
+// Parameters:
+//   - name: the name of the file
+func (m *MountFS) Stat(name string) (FileInfo, error) {
+	fsys, _, rel, err := m.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if fsys != nil {
+		info, err := fs.Stat(fsys, rel)
+		if err == nil && rel == "." && name != "." {
+			return renamedInfo{FileInfo: info, name: path.Base(name)}, nil
+		}
+
+		if err == nil || !errors.Is(err, fs.ErrNotExist) || !m.isVirtualDir(name) {
+			return info, mountErr(name, err)
+		}
+	}
+
+	if !m.isVirtualDir(name) {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return virtualDirInfo(path.Base(name)), nil
+}
+
+// ReadDir reads the named directory and returns its entries sorted by name,
+// including the mount points directly below it.
+//
+// Parameters:
+//   - name: the name of the directory
+func (m *MountFS) ReadDir(name string) ([]DirEntry, error) {
+	fsys, _, rel, err := m.resolve("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	children := m.childMounts(name)
+
+	var entries []DirEntry
+	if fsys != nil {
+		entries, err = fs.ReadDir(fsys, rel)
+		if err != nil && (!errors.Is(err, fs.ErrNotExist) || len(children) == 0) {
+			return nil, mountErr(name, err)
+		}
+	} else if len(children) == 0 && name != "." {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries = slices.DeleteFunc(entries, func(e DirEntry) bool {
+		return slices.Contains(children, e.Name())
+	})
+
+	for _, child := range children {
+		info, err := m.Stat(path.Join(name, child))
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	slices.SortFunc(entries, func(a, b DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return entries, nil
+}
+
+// Mkdir creates the named directory on the mount that covers it.
+//
+// Parameters:
+//   - name: the name of the directory
+//   - perm: the permission bits
+func (m *MountFS) Mkdir(name string, perm FileMode) error {
+	wfs, _, rel, err := m.writable("mkdir", name)
+	if err != nil {
+		return err
+	}
+
+	return mountErr(name, wfs.Mkdir(rel, perm))
+}
+
+// MkdirAll creates the named directory and any missing parents on the mount
+// that covers it.
+//
+// Parameters:
+//   - name: the name of the directory
+//   - perm: the permission bits
+func (m *MountFS) MkdirAll(name string, perm FileMode) error {
+	wfs, _, rel, err := m.writable("mkdir", name)
+	if err != nil {
+		return err
+	}
+
+	return mountErr(name, wfs.MkdirAll(rel, perm))
+}
+
+// Remove removes the named file or empty directory from the mount that
+// covers it.
+//
+// Parameters:
+//   - name: the name of the file
+func (m *MountFS) Remove(name string) error {
+	wfs, _, rel, err := m.writable("remove", name)
+	if err != nil {
+		return err
+	}
+
+	return mountErr(name, wfs.Remove(rel))
+}
+
+// RemoveAll removes the named path and its children from the mount that
+// covers it. It does not remove other mounts below name.
+//
+// Parameters:
+//   - name: the path to remove
+func (m *MountFS) RemoveAll(name string) error {
+	wfs, _, rel, err := m.writable("removeall", name)
+	if err != nil {
+		return err
+	}
+
+	return mountErr(name, wfs.RemoveAll(rel))
+}
+
+// Rename renames oldname to newname. Both names must belong to the same
+// mount, otherwise the error wraps ErrCrossMount.
+//
+// Parameters:
+//   - oldname: the current name
+//   - newname: the new name
+func (m *MountFS) Rename(oldname, newname string) error {
+	oldfs, oldmount, oldrel, err := m.writable("rename", oldname)
+	if err != nil {
+		return err
+	}
+
+	_, newmount, newrel, err := m.writable("rename", newname)
+	if err != nil {
+		return err
+	}
+
+	if oldmount != newmount {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: ErrCrossMount}
+	}
+
+	err = oldfs.Rename(oldrel, newrel)
+	var linkErr *os.LinkError
+	if errors.As(err, &linkErr) {
+		return &os.LinkError{Op: linkErr.Op, Old: oldname, New: newname, Err: linkErr.Err}
+	}
+
+	return mountErr(oldname, err)
+}
+
+// mountErr rewrites the path in a *PathError returned by a mount to the
+// name used in the MountFS.
+func mountErr(name string, err error) error {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return &os.PathError{Op: pathErr.Op, Path: name, Err: pathErr.Err}
+	}
+
+	return err
+}
+
+// renamedInfo reports the root of a mount under the name of its mount point.
+type renamedInfo struct {
+	FileInfo
+	name string
+}
+
+func (i renamedInfo) Name() string { return i.name }
+
+// mountRoot is the root directory of a mount opened through the MountFS.
+type mountRoot struct {
+	fs.File
+	name string
+}
+
+func (f *mountRoot) Stat() (FileInfo, error) {
+	info, err := f.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return renamedInfo{FileInfo: info, name: f.name}, nil
+}
+
+func (f *mountRoot) ReadDir(n int) ([]DirEntry, error) {
+	dir, ok := f.File.(fs.ReadDirFile)
+	if !ok {
+		return nil, &os.PathError{Op: "readdir", Path: f.name, Err: fs.ErrInvalid}
+	}
+
+	return dir.ReadDir(n)
+}
+
+// virtualDirInfo describes an implicit directory of a MountFS.
+type virtualDirInfo string
+
+func (d virtualDirInfo) Name() string       { return string(d) }
+func (d virtualDirInfo) Size() int64        { return 0 }
+func (d virtualDirInfo) Mode() FileMode     { return fs.ModeDir | 0555 }
+func (d virtualDirInfo) ModTime() time.Time { return time.Time{} }
+func (d virtualDirInfo) IsDir() bool        { return true }
+func (d virtualDirInfo) Sys() any           { return nil }
+
+// virtualDir is an open implicit directory of a MountFS.
+type virtualDir struct {
+	name    string
+	entries []DirEntry
+	offset  int
+}
+
+func (d *virtualDir) Stat() (FileInfo, error) {
+	return virtualDirInfo(path.Base(d.name)), nil
+}
+
+func (d *virtualDir) Read([]byte) (int, error) {
+	return 0, &os.PathError{Op: "read", Path: d.name, Err: fs.ErrInvalid}
+}
+
+func (d *virtualDir) Close() error {
+	return nil
+}
+
+func (d *virtualDir) ReadDir(n int) ([]DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
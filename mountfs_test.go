@@ -0,0 +1,82 @@
+package xfs_test
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMountFS(t *testing.T) {
+	m := xfs.NewMountFS()
+	assert.NoError(t, m.Mount("/config", fstest.MapFS{
+		"app.yaml": {Data: []byte("name: app")},
+	}))
+	assert.NoError(t, m.Mount("/var/data", xfs.DirFS(t.TempDir())))
+
+	data, err := fs.ReadFile(m, "config/app.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "name: app", string(data))
+
+	f, err := m.OpenFile("var/data/out.txt", os.O_WRONLY|os.O_CREATE, 0644)
+	assert.NoError(t, err)
+	f.Write([]byte("out"))
+	f.Close()
+
+	data, err = fs.ReadFile(m, "var/data/out.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "out", string(data))
+
+	entries, err := m.ReadDir(".")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "config", entries[0].Name())
+	assert.Equal(t, "var", entries[1].Name())
+
+	info, err := m.Stat("var")
+	assert.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	assert.NoError(t, fstest.TestFS(m, "config/app.yaml", "var/data/out.txt"))
+
+	_, err = m.OpenFile("config/new.yaml", os.O_WRONLY|os.O_CREATE, 0644)
+	assert.ErrorIs(t, err, fs.ErrPermission)
+
+	err = m.Rename("var/data/out.txt", "config/out.txt")
+	assert.ErrorIs(t, err, fs.ErrPermission)
+
+	assert.NoError(t, m.Mount("tmp", xfs.DirFS(t.TempDir())))
+	err = m.Rename("var/data/out.txt", "tmp/out.txt")
+	assert.ErrorIs(t, err, xfs.ErrCrossMount)
+
+	_, err = m.Stat("missing")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	assert.True(t, m.Unmount("tmp"))
+	_, err = m.Stat("tmp")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+// tagFS is a WritableFS whose dynamic type is not comparable.
+type tagFS struct {
+	xfs.WritableFS
+	tags map[string]string
+}
+
+func TestMountFSRenameNonComparable(t *testing.T) {
+	m := xfs.NewMountFS()
+	assert.NoError(t, m.Mount("data", tagFS{WritableFS: xfs.DirFS(t.TempDir()), tags: map[string]string{}}))
+	assert.NoError(t, m.Mount("other", tagFS{WritableFS: xfs.DirFS(t.TempDir()), tags: map[string]string{}}))
+
+	f, err := m.OpenFile("data/a.txt", os.O_WRONLY|os.O_CREATE, 0644)
+	assert.NoError(t, err)
+	f.Close()
+
+	assert.NotPanics(t, func() {
+		assert.NoError(t, m.Rename("data/a.txt", "data/b.txt"))
+		assert.ErrorIs(t, m.Rename("data/b.txt", "other/b.txt"), xfs.ErrCrossMount)
+	})
+}
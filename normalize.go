@@ -0,0 +1,113 @@
+package xfs
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// UnicodeForm selects a Unicode normalization form for NormalizeUnicode.
+type UnicodeForm int
+
+const (
+	// NFC is the composed form used by most systems, e.g. "é" as U+00E9.
+	NFC UnicodeForm = iota
+	// NFD is the decomposed form used by macOS HFS+, e.g. "é" as "e" followed
+	// by U+0301.
+	NFD
+)
+
+// NormalizeOptions controls the transformations applied by NormalizeOpt.
+type NormalizeOptions struct {
+	// FoldCase lower-cases the path when the operating system's file systems
+	// are case-insensitive by default (Windows and macOS), so that paths
+	// which name the same file compare equal.
+	FoldCase bool
+	// Unicode normalizes the path to NFC, unless UnicodeForm is set.
+	Unicode bool
+	// UnicodeForm is the normalization form used when Unicode is set.
+	UnicodeForm UnicodeForm
+}
+
+// Normalize returns the shortest equivalent form of path using the operating
+// system's separator: slashes are converted to the separator and the result
+// is cleaned as by filepath.Clean.
+//
+// Parameters:
+//   - path: the path to normalize
+func Normalize(path string) string {
+	return filepath.Clean(filepath.FromSlash(path))
+}
+
+// NormalizeOpt is like Normalize but can additionally fold the case and
+// normalize the Unicode representation of the path. This makes it suitable
+// for comparing or deduplicating paths, not for opening files: the folded
+// result may not exist with that spelling on case-sensitive file systems.
+//
+// Parameters:
+//   - path: the path to normalize
+//   - opts: the normalization options, nil behaves like Normalize
+func NormalizeOpt(path string, opts *NormalizeOptions) string {
+	path = Normalize(path)
+	if opts == nil {
+		return path
+	}
+
+	if opts.Unicode {
+		path = NormalizeUnicode(path, opts.UnicodeForm)
+	}
+
+	if opts.FoldCase && caseInsensitiveOS() {
+		path = strings.ToLower(path)
+	}
+
+	return path
+}
+
+// NormalizeUnicode returns path in the Unicode normalization form form. The
+// same file name can be stored as NFC on Linux and Windows but as NFD on
+// macOS, so names must be normalized before they are compared.
+//
+// Parameters:
+//   - path: the path to normalize
+//   - form: the normalization form, NFC or NFD
+func NormalizeUnicode(path string, form UnicodeForm) string {
+	if form == NFD {
+		return norm.NFD.String(path)
+	}
+
+	return norm.NFC.String(path)
+}
+
+// ToSlash returns the result of replacing each separator character in path
+// with a slash ('/') character. Multiple separators are replaced by multiple
+// slashes.
+//
+// Parameters:
+//   - path: the path to convert
+func ToSlash(path string) string {
+	return filepath.ToSlash(path)
+}
+
+// FromSlash returns the result of replacing each slash ('/') character in
+// path with a separator character. Multiple slashes are replaced by multiple
+// separators.
+//
+// Parameters:
+//   - path: the path to convert
+func FromSlash(path string) string {
+	return filepath.FromSlash(path)
+}
+
+// caseInsensitiveOS reports whether the default file systems of the operating
+// system compare names case-insensitively.
+func caseInsensitiveOS() bool {
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios":
+		return true
+	default:
+		return false
+	}
+}
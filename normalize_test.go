@@ -0,0 +1,42 @@
+package xfs_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, filepath.FromSlash("a/c"), xfs.Normalize("a//b/../c/"))
+	assert.Equal(t, ".", xfs.Normalize(""))
+}
+
+func TestNormalizeOpt(t *testing.T) {
+	path := xfs.NormalizeOpt("Docs/Café.txt", &xfs.NormalizeOptions{FoldCase: true, Unicode: true})
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		assert.Equal(t, filepath.FromSlash("docs/café.txt"), path)
+	} else {
+		assert.Equal(t, "Docs/Café.txt", path)
+	}
+
+	assert.Equal(t, xfs.Normalize("a/./b"), xfs.NormalizeOpt("a/./b", nil))
+}
+
+func TestNormalizeUnicode(t *testing.T) {
+	composed := "caf\u00e9"
+	decomposed := "cafe\u0301"
+
+	assert.Equal(t, composed, xfs.NormalizeUnicode(decomposed, xfs.NFC))
+	assert.Equal(t, decomposed, xfs.NormalizeUnicode(composed, xfs.NFD))
+}
+
+func TestToSlash(t *testing.T) {
+	assert.Equal(t, "a/b", xfs.ToSlash(filepath.Join("a", "b")))
+}
+
+func TestFromSlash(t *testing.T) {
+	assert.Equal(t, filepath.Join("a", "b"), xfs.FromSlash("a/b"))
+}
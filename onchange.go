@@ -0,0 +1,88 @@
+package xfs
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// fileChangeDelay is how long OnFileChange waits for a burst of events to
+// settle before it reads the file.
+const fileChangeDelay = 100 * time.Millisecond
+
+// OnFileChange watches a single file and calls fn with its new content each
+// time it changes, until ctx is canceled. It is meant for reloading
+// configuration files without setting up a full watcher.
+//
+// The containing directory is watched rather than the file itself, so
+// editors that save by writing a temporary file and renaming it over the
+// original are handled, as are files that are deleted and recreated. Bursts
+// of events are coalesced and fn is only called when the content actually
+// differs from the last content seen; it is not called for the content the
+// file has when OnFileChange starts. While the file is missing no calls are
+// made.
+//
+// OnFileChange returns once the watch is set up; fn is called from a
+// separate goroutine, one call at a time.
+//
+// Parameters:
+//   - path: the file to watch
+//   - ctx: the context that ends the watch
+//   - fn: the function called with the new content
+func OnFileChange(path string, ctx context.Context, fn func(data []byte)) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return &os.PathError{Op: "watch", Path: path, Err: err}
+	}
+
+	if err := w.Add(filepath.Dir(abs)); err != nil {
+		w.Close()
+		return &os.PathError{Op: "watch", Path: path, Err: err}
+	}
+
+	last, _ := os.ReadFile(abs)
+	go func() {
+		defer w.Close()
+
+		timer := time.NewTimer(fileChangeDelay)
+		timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+
+				if filepath.Clean(ev.Name) == abs {
+					timer.Reset(fileChangeDelay)
+				}
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+			case <-timer.C:
+				data, err := os.ReadFile(abs)
+				if err != nil || bytes.Equal(data, last) {
+					continue
+				}
+
+				last = data
+				fn(data)
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package xfs_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnFileChange(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.yaml")
+	os.WriteFile(config, []byte("v1"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan string, 10)
+	err := xfs.OnFileChange(config, ctx, func(data []byte) {
+		changes <- string(data)
+	})
+	assert.NoError(t, err)
+
+	// Save the way editors do: write a temporary file and rename it.
+	os.WriteFile(config+".swp", []byte("v2"), 0644)
+	os.Rename(config+".swp", config)
+
+	select {
+	case data := <-changes:
+		assert.Equal(t, "v2", data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change reported")
+	}
+
+	os.WriteFile(filepath.Join(dir, "other"), []byte("x"), 0644)
+	os.WriteFile(config, []byte("v2"), 0644)
+
+	select {
+	case data := <-changes:
+		t.Fatalf("unexpected change %q", data)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	err = xfs.OnFileChange(filepath.Join(dir, "missing", "file"), ctx, func([]byte) {})
+	assert.Error(t, err)
+}
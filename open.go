@@ -0,0 +1,36 @@
+package xfs
+
+import "os"
+
+// OpenAppend opens the named file for appending, creating it with
+// permissions perm (before umask) if it does not exist. Every write goes to
+// the end of the file, even when other processes append to it too.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - perm: the file permissions
+func OpenAppend(filename string, perm FileMode) (*File, error) {
+	return os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, perm)
+}
+
+// OpenReadWrite opens the existing named file for reading and writing
+// without truncating it.
+//
+// Parameters:
+//   - filename: the name of the file
+func OpenReadWrite(filename string) (*File, error) {
+	return os.OpenFile(filename, os.O_RDWR, 0)
+}
+
+// OpenShared opens the named file for reading while allowing other processes
+// to write, rename and delete it. On Unix this is what Open does. On Windows,
+// where Open prevents the file from being renamed or deleted while it is
+// open, the file is opened with FILE_SHARE_READ, FILE_SHARE_WRITE and
+// FILE_SHARE_DELETE, which suits readers of files that are rotated or
+// replaced by other programs.
+//
+// Parameters:
+//   - filename: the name of the file
+func OpenShared(filename string) (*File, error) {
+	return openShared(filename)
+}
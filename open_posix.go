@@ -0,0 +1,9 @@
+//go:build !windows
+
+package xfs
+
+import "os"
+
+func openShared(filename string) (*File, error) {
+	return os.Open(filename)
+}
@@ -0,0 +1,58 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	for _, line := range []string{"one\n", "two\n"} {
+		f, err := xfs.OpenAppend(path, 0644)
+		assert.NoError(t, err)
+		f.WriteString(line)
+		f.Close()
+	}
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "one\ntwo\n", string(data))
+}
+
+func TestOpenReadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	_, err := xfs.OpenReadWrite(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	os.WriteFile(path, []byte("abc"), 0644)
+	f, err := xfs.OpenReadWrite(path)
+	assert.NoError(t, err)
+	f.WriteString("X")
+	buf := make([]byte, 2)
+	f.Read(buf)
+	f.Close()
+
+	assert.Equal(t, "bc", string(buf))
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "Xbc", string(data))
+}
+
+func TestOpenShared(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "shared")
+	os.WriteFile(path, []byte("data"), 0644)
+
+	f, err := xfs.OpenShared(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	assert.NoError(t, os.Rename(path, filepath.Join(dir, "moved")))
+
+	data := make([]byte, 4)
+	n, _ := f.Read(data)
+	assert.Equal(t, "data", string(data[:n]))
+}
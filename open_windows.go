@@ -0,0 +1,26 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func openShared(filename string) (*File, error) {
+	name, err := windows.UTF16PtrFromString(fixLongPath(filename))
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: filename, Err: err}
+	}
+
+	h, err := windows.CreateFile(name, windows.GENERIC_READ,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil, windows.OPEN_EXISTING, windows.FILE_ATTRIBUTE_NORMAL|windows.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: filename, Err: err}
+	}
+
+	return os.NewFile(uintptr(h), filename), nil
+}
@@ -0,0 +1,30 @@
+package xfs
+
+// FileOwner describes the owner and group of a file.
+type FileOwner struct {
+	// UID is the numeric user ID of the owner, or -1 on Windows.
+	UID int
+	// GID is the numeric group ID of the group, or -1 on Windows.
+	GID int
+	// User is the name of the owner, or empty if it cannot be resolved.
+	// On Windows it has the form DOMAIN\account.
+	User string
+	// Group is the name of the group, or empty if it cannot be resolved.
+	// On Windows it has the form DOMAIN\account.
+	Group string
+	// UserSID is the security identifier of the owner on Windows.
+	UserSID string
+	// GroupSID is the security identifier of the primary group on Windows.
+	GroupSID string
+}
+
+// Owner returns the owner and group of the named file, including the
+// resolved user and group names, without platform-specific casts of
+// FileInfo.Sys(). If the file is a symbolic link, it describes the link's
+// target. Names that cannot be resolved are left empty.
+//
+// Parameters:
+//   - filename: the name of the file
+func Owner(filename string) (*FileOwner, error) {
+	return owner(filename)
+}
@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package xfs
+
+import (
+	"errors"
+	"os"
+)
+
+func owner(filename string) (*FileOwner, error) {
+	if _, err := os.Stat(filename); err != nil {
+		return nil, err
+	}
+
+	return nil, &os.PathError{Op: "owner", Path: filename, Err: errors.ErrUnsupported}
+}
@@ -0,0 +1,34 @@
+package xfs_test
+
+import (
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	xfs.WriteTextFile(path, "data", 0644)
+
+	owner, err := xfs.Owner(path)
+	assert.NoError(t, err)
+
+	current, err := user.Current()
+	assert.NoError(t, err)
+
+	if runtime.GOOS == "windows" {
+		assert.Equal(t, -1, owner.UID)
+		assert.NotEmpty(t, owner.UserSID)
+	} else {
+		assert.Equal(t, current.Uid, strconv.Itoa(owner.UID))
+		assert.Equal(t, current.Username, owner.User)
+	}
+
+	_, err = xfs.Owner(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package xfs
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+func owner(filename string) (*FileOwner, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, &os.PathError{Op: "owner", Path: filename, Err: syscall.ENOTSUP}
+	}
+
+	o := &FileOwner{UID: int(st.Uid), GID: int(st.Gid)}
+	if u, err := user.LookupId(strconv.Itoa(o.UID)); err == nil {
+		o.User = u.Username
+	}
+
+	if g, err := user.LookupGroupId(strconv.Itoa(o.GID)); err == nil {
+		o.Group = g.Name
+	}
+
+	return o, nil
+}
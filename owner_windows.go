@@ -0,0 +1,44 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func owner(filename string) (*FileOwner, error) {
+	sd, err := windows.GetNamedSecurityInfo(filename, windows.SE_FILE_OBJECT,
+		windows.OWNER_SECURITY_INFORMATION|windows.GROUP_SECURITY_INFORMATION)
+	if err != nil {
+		return nil, &os.PathError{Op: "owner", Path: filename, Err: err}
+	}
+
+	o := &FileOwner{UID: -1, GID: -1}
+	if sid, _, err := sd.Owner(); err == nil && sid != nil {
+		o.UserSID = sid.String()
+		o.User = accountName(sid)
+	}
+
+	if sid, _, err := sd.Group(); err == nil && sid != nil {
+		o.GroupSID = sid.String()
+		o.Group = accountName(sid)
+	}
+
+	return o, nil
+}
+
+func accountName(sid *windows.SID) string {
+	account, domain, _, err := sid.LookupAccount("")
+	if err != nil {
+		return ""
+	}
+
+	if domain == "" {
+		return account
+	}
+
+	return domain + `\` + account
+}
@@ -0,0 +1,186 @@
+package xfs
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"hash"
+	"io"
+	"os"
+)
+
+// ErrSizeLimit is returned, wrapped in a *PathError, when more data passes a
+// Pipeline stage than its Limit allows.
+var ErrSizeLimit = errors.New("xfs: size limit exceeded")
+
+// Pipeline reads a file through a chain of stages, such as size limits,
+// hashers and decompressors, built by chaining methods:
+//
+//	sum := sha256.New()
+//	data, err := xfs.NewReader("upload.gz").Limit(1<<20).HashInto(sum).Gunzip().Limit(64<<20).ReadAll()
+//
+// Each stage applies to the data produced by the stages before it, so the
+// first Limit above bounds the compressed size and the second one the
+// decompressed size. Errors from building the chain, such as a missing file
+// or a corrupt gzip header, are kept and returned by the first Read or by
+// ReadAll, so the chain needs no checks between the calls. A Pipeline must be
+// closed unless it was consumed with ReadAll.
+type Pipeline struct {
+	name    string
+	r       io.Reader
+	closers []io.Closer
+	err     error
+}
+
+// NewReader opens the named file and returns a Pipeline reading it.
+//
+// Parameters:
+//   - filename: the name of the file
+func NewReader(filename string) *Pipeline {
+	p := &Pipeline{name: filename}
+	f, err := os.Open(filename)
+	if err != nil {
+		p.err = err
+		return p
+	}
+
+	p.r = f
+	p.closers = append(p.closers, f)
+	return p
+}
+
+// Limit fails the read with ErrSizeLimit once more than n bytes have passed
+// this stage, instead of silently truncating the data.
+//
+// Parameters:
+//   - n: the maximum number of bytes
+func (p *Pipeline) Limit(n int64) *Pipeline {
+	if p.err == nil {
+		p.r = &limitReader{r: p.r, n: n, name: p.name}
+	}
+
+	return p
+}
+
+// HashInto writes all data passing this stage to h, so the digest is
+// complete once the pipeline has been read to the end.
+//
+// Parameters:
+//   - h: the hash to update
+func (p *Pipeline) HashInto(h hash.Hash) *Pipeline {
+	if p.err == nil {
+		p.r = io.TeeReader(p.r, h)
+	}
+
+	return p
+}
+
+// Gunzip decompresses the gzip data passing this stage.
+func (p *Pipeline) Gunzip() *Pipeline {
+	if p.err != nil {
+		return p
+	}
+
+	zr, err := gzip.NewReader(p.r)
+	if err != nil {
+		p.err = &os.PathError{Op: "gunzip", Path: p.name, Err: err}
+		return p
+	}
+
+	p.r = zr
+	p.closers = append(p.closers, zr)
+	return p
+}
+
+// Decompress decompresses the data passing this stage with the registered
+// codec whose signature it starts with, like OpenCompressed, and passes data
+// in no known format through unchanged.
+func (p *Pipeline) Decompress() *Pipeline {
+	if p.err != nil {
+		return p
+	}
+
+	br := bufio.NewReader(p.r)
+	head, _ := br.Peek(maxMagicLen)
+	p.r = br
+
+	c := codecByMagic(head)
+	if c == nil {
+		return p
+	}
+
+	if c.NewReader == nil {
+		p.err = unsupportedCodec("decompress", p.name, c)
+		return p
+	}
+
+	dec, err := c.NewReader(br)
+	if err != nil {
+		p.err = &os.PathError{Op: "decompress", Path: p.name, Err: err}
+		return p
+	}
+
+	p.r = dec
+	p.closers = append(p.closers, dec)
+	return p
+}
+
+// Read reads from the last stage of the pipeline.
+//
+// Parameters:
+//   - b: the buffer to read into
+func (p *Pipeline) Read(b []byte) (int, error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+
+	return p.r.Read(b)
+}
+
+// ReadAll reads the pipeline to the end, closes it and returns the data.
+func (p *Pipeline) ReadAll() ([]byte, error) {
+	data, err := io.ReadAll(p)
+	if closeErr := p.Close(); err == nil {
+		err = closeErr
+	}
+
+	return data, err
+}
+
+// Close closes the decompressors and the file of the pipeline.
+func (p *Pipeline) Close() error {
+	var errs []error
+	for i := len(p.closers) - 1; i >= 0; i-- {
+		if err := p.closers[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	p.closers = nil
+	return errors.Join(errs...)
+}
+
+// limitReader reads from r until more than n bytes were requested to pass.
+type limitReader struct {
+	r    io.Reader
+	n    int64
+	name string
+}
+
+func (l *limitReader) Read(b []byte) (int, error) {
+	if l.n < 0 {
+		return 0, &os.PathError{Op: "read", Path: l.name, Err: ErrSizeLimit}
+	}
+
+	if int64(len(b)) > l.n+1 {
+		b = b[:l.n+1]
+	}
+
+	n, err := l.r.Read(b)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n + int(l.n), &os.PathError{Op: "read", Path: l.name, Err: ErrSizeLimit}
+	}
+
+	return n, err
+}
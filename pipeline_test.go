@@ -0,0 +1,51 @@
+package xfs_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeline(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.gz")
+	content := strings.Repeat("pipeline ", 100)
+	assert.NoError(t, xfs.WriteFileGzip(path, []byte(content), 0644))
+
+	raw, _ := os.ReadFile(path)
+	want := sha256.Sum256(raw)
+
+	sum := sha256.New()
+	data, err := xfs.NewReader(path).Limit(int64(len(raw))).HashInto(sum).Gunzip().ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(data))
+	assert.Equal(t, hex.EncodeToString(want[:]), hex.EncodeToString(sum.Sum(nil)))
+
+	data, err = xfs.NewReader(path).Decompress().ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(data))
+
+	_, err = xfs.NewReader(path).Gunzip().Limit(100).ReadAll()
+	assert.ErrorIs(t, err, xfs.ErrSizeLimit)
+
+	_, err = xfs.NewReader(path).Limit(10).Gunzip().ReadAll()
+	assert.ErrorIs(t, err, xfs.ErrSizeLimit)
+
+	_, err = xfs.NewReader(filepath.Join(dir, "missing")).Limit(10).Gunzip().ReadAll()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	plain := filepath.Join(dir, "plain.txt")
+	os.WriteFile(plain, []byte("plain"), 0644)
+	data, err = xfs.NewReader(plain).Decompress().ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", string(data))
+
+	_, err = xfs.NewReader(plain).Gunzip().ReadAll()
+	assert.Error(t, err)
+}
@@ -0,0 +1,309 @@
+package xfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is the time between scans of a PollWatcher.
+const DefaultPollInterval = time.Second
+
+// PollWatcher detects changes by scanning the watched paths at a fixed
+// interval and comparing the results, like Snapshot does without hashing.
+// It works wherever the file system can be listed, including network file
+// systems, FUSE mounts and containers where inotify or kqueue events are
+// not delivered, at the cost of latency and of a stat call per entry and
+// scan.
+//
+// A PollWatcher reports the same events as a Watcher and honors the same
+// WatchOptions, except MoveWindow: a removed and an added entry with the
+// same type, size and modification time found in one scan are reported as
+// OpMove. Changes that are undone between two scans are not seen.
+type PollWatcher struct {
+	eventFilter
+	events chan Event
+	errors chan error
+	done   chan struct{}
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	state  map[string]map[string]ManifestEntry
+	closed bool
+}
+
+// NewPollWatcher creates a PollWatcher with no watched paths that scans every
+// opts.PollInterval. Call Add to watch paths and Close to release it.
+//
+// Parameters:
+//   - opts: the watch options, nil uses the defaults
+func NewPollWatcher(opts *WatchOptions) (*PollWatcher, error) {
+	o, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &PollWatcher{
+		eventFilter: eventFilter{opts: o},
+		events:      make(chan Event, 64),
+		errors:      make(chan error, 8),
+		done:        make(chan struct{}),
+		state:       map[string]map[string]ManifestEntry{},
+	}
+
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// Events returns the channel on which events are delivered. It is closed
+// by Close.
+func (w *PollWatcher) Events() <-chan Event {
+	return w.events
+}
+
+// Errors returns the channel on which scan errors are delivered. It is
+// closed by Close.
+func (w *PollWatcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Add starts watching the named file or directory. Its current state is
+// recorded immediately, so only later changes are reported.
+//
+// Parameters:
+//   - path: the file or directory to watch
+func (w *PollWatcher) Add(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Lstat(abs); err != nil {
+		return &os.PathError{Op: "watch", Path: path, Err: err}
+	}
+
+	w.addRoot(abs)
+	entries, err := w.scan(abs)
+	if err != nil {
+		w.removeRoot(abs)
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return &os.PathError{Op: "watch", Path: path, Err: os.ErrClosed}
+	}
+
+	w.state[abs] = entries
+	return nil
+}
+
+// Remove stops watching the named file or directory.
+//
+// Parameters:
+//   - path: the file or directory
+func (w *PollWatcher) Remove(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	delete(w.state, abs)
+	w.mu.Unlock()
+	w.removeRoot(abs)
+	return nil
+}
+
+// Close stops the watcher and closes the Events and Errors channels.
+func (w *PollWatcher) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.done)
+	w.wg.Wait()
+	close(w.events)
+	close(w.errors)
+	return nil
+}
+
+func (w *PollWatcher) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// poll scans every watched path once and reports the differences.
+func (w *PollWatcher) poll() {
+	w.mu.Lock()
+	roots := make([]string, 0, len(w.state))
+	for root := range w.state {
+		roots = append(roots, root)
+	}
+	w.mu.Unlock()
+
+	sort.Strings(roots)
+	for _, root := range roots {
+		current, err := w.scan(root)
+		if err != nil {
+			w.sendError(err)
+			continue
+		}
+
+		w.mu.Lock()
+		previous, ok := w.state[root]
+		if ok {
+			w.state[root] = current
+		}
+		w.mu.Unlock()
+
+		if !ok {
+			continue
+		}
+
+		for _, ev := range pollDiff(previous, current) {
+			w.apply(ev, w.send)
+		}
+	}
+}
+
+// scan records the entries of root keyed by absolute path. A missing root
+// yields no entries, so that its removal and recreation are reported.
+func (w *PollWatcher) scan(root string) (map[string]ManifestEntry, error) {
+	entries := map[string]ManifestEntry{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if path != root && w.ignored(path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		// The root is only recorded when it is a file; for a directory its
+		// entries are reported, as with a notification based Watcher.
+		if path != root || !info.IsDir() {
+			entry, err := manifestEntry(path, path, info, nil)
+			if err != nil {
+				return err
+			}
+
+			entries[path] = entry
+		}
+
+		if path != root && d.IsDir() && !w.opts.Recursive {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	return entries, err
+}
+
+func (w *PollWatcher) send(ev Event) {
+	select {
+	case w.events <- ev:
+	case <-w.done:
+	}
+}
+
+func (w *PollWatcher) sendError(err error) {
+	select {
+	case w.errors <- err:
+	case <-w.done:
+	}
+}
+
+// pollDiff returns the events that turn the previous scan into the current
+// one, sorted by path. Like notification APIs, it does not report a
+// directory as written when its entries change.
+func pollDiff(previous, current map[string]ManifestEntry) []Event {
+	var added, removed []ManifestEntry
+	var events []Event
+	for path, entry := range current {
+		old, ok := previous[path]
+		switch {
+		case !ok:
+			added = append(added, entry)
+		case old.Mode.Type() != entry.Mode.Type() || old.Target != entry.Target ||
+			!entry.Mode.IsDir() && (old.Size != entry.Size || !old.ModTime.Equal(entry.ModTime)):
+			events = append(events, Event{Op: OpWrite, Path: path})
+		case old.Mode != entry.Mode:
+			events = append(events, Event{Op: OpChmod, Path: path})
+		}
+	}
+
+	for path, entry := range previous {
+		if _, ok := current[path]; !ok {
+			removed = append(removed, entry)
+		}
+	}
+
+	sort.Slice(added, func(i, j int) bool { return added[i].Path < added[j].Path })
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Path < removed[j].Path })
+
+	for _, old := range removed {
+		match := -1
+		for i, entry := range added {
+			if entry.Mode == old.Mode && entry.Size == old.Size &&
+				entry.ModTime.Equal(old.ModTime) && entry.Target == old.Target {
+				match = i
+				break
+			}
+		}
+
+		if match < 0 {
+			events = append(events, Event{Op: OpRemove, Path: old.Path})
+			continue
+		}
+
+		events = append(events, Event{Op: OpMove, Path: added[match].Path, OldPath: old.Path})
+		added = append(added[:match], added[match+1:]...)
+	}
+
+	for _, entry := range added {
+		events = append(events, Event{Op: OpCreate, Path: entry.Path})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
@@ -0,0 +1,68 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollWatcher(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	os.WriteFile(a, []byte("a"), 0644)
+	os.WriteFile(filepath.Join(dir, "skip.tmp"), nil, 0644)
+
+	w, err := xfs.NewPollWatcher(&xfs.WatchOptions{
+		PollInterval: 20 * time.Millisecond,
+		Ignore:       []string{"*.tmp"},
+		Recursive:    true,
+	})
+	assert.NoError(t, err)
+	defer w.Close()
+	assert.NoError(t, w.Add(dir))
+
+	next := func() xfs.Event {
+		t.Helper()
+		select {
+		case ev := <-w.Events():
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event")
+			return xfs.Event{}
+		}
+	}
+
+	os.WriteFile(a, []byte("changed"), 0644)
+	assert.Equal(t, xfs.Event{Op: xfs.OpWrite, Path: a}, next())
+
+	b := filepath.Join(dir, "sub", "b")
+	xfs.MkdirAllDefault(filepath.Dir(b))
+	assert.Equal(t, xfs.Event{Op: xfs.OpCreate, Path: filepath.Dir(b)}, next())
+
+	os.Rename(a, b)
+	assert.Equal(t, xfs.Event{Op: xfs.OpMove, Path: b, OldPath: a}, next())
+
+	os.WriteFile(filepath.Join(dir, "skip.tmp"), []byte("x"), 0644)
+	os.Remove(b)
+	assert.Equal(t, xfs.Event{Op: xfs.OpRemove, Path: b}, next())
+
+	assert.NoError(t, w.Close())
+	for range w.Events() {
+	}
+}
+
+func TestWatcherPoll(t *testing.T) {
+	dir := t.TempDir()
+	w, err := xfs.NewWatcher(&xfs.WatchOptions{Poll: true, PollInterval: 20 * time.Millisecond})
+	assert.NoError(t, err)
+	defer w.Close()
+	assert.NoError(t, w.Add(dir))
+
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, nil, 0644)
+	assert.Equal(t, xfs.Event{Op: xfs.OpCreate, Path: file}, nextEvent(t, w))
+}
@@ -0,0 +1,35 @@
+package xfs
+
+import (
+	"os"
+)
+
+// Preallocate reserves storage for the open file so that it can grow to size
+// bytes without running out of disk space, and extends the file to size if it
+// is smaller. Reserving the space up front lets large downloads and disk
+// images fail early when the disk is too small and helps the file system
+// allocate contiguous blocks. Preallocate never shrinks a file.
+//
+// On Linux this uses fallocate(2), on macOS F_PREALLOCATE and on Windows
+// SetEndOfFile. On other platforms the file is only extended, which does not
+// guarantee that the space is reserved.
+//
+// Parameters:
+//   - f: the file, opened for writing
+//   - size: the size to reserve in bytes
+func Preallocate(f *File, size int64) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if size <= info.Size() {
+		return nil
+	}
+
+	if err := preallocate(f, info.Size(), size); err != nil {
+		return &os.PathError{Op: "preallocate", Path: f.Name(), Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+//go:build darwin || ios
+
+package xfs
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+func preallocate(f *File, current, size int64) error {
+	store := &unix.Fstore_t{
+		Flags:   unix.F_ALLOCATECONTIG | unix.F_ALLOCATEALL,
+		Posmode: unix.F_PEOFPOSMODE,
+		Length:  size - current,
+	}
+
+	// Contiguous space is preferred but not required.
+	if err := unix.FcntlFstore(f.Fd(), unix.F_PREALLOCATE, store); err != nil {
+		store.Flags = unix.F_ALLOCATEALL
+		if err := unix.FcntlFstore(f.Fd(), unix.F_PREALLOCATE, store); err != nil {
+			return err
+		}
+	}
+
+	return f.Truncate(size)
+}
@@ -0,0 +1,16 @@
+//go:build linux
+
+package xfs
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+func preallocate(f *File, current, size int64) error {
+	err := unix.Fallocate(int(f.Fd()), 0, current, size-current)
+	if err == unix.EOPNOTSUPP {
+		return f.Truncate(size)
+	}
+
+	return err
+}
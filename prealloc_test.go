@@ -0,0 +1,32 @@
+package xfs_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreallocate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disk.img")
+	f, err := xfs.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	f.WriteString("header")
+	assert.NoError(t, xfs.Preallocate(f, 1<<20))
+
+	info, err := f.Stat()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1<<20), info.Size())
+
+	// Preallocate never shrinks.
+	assert.NoError(t, xfs.Preallocate(f, 10))
+	info, _ = f.Stat()
+	assert.Equal(t, int64(1<<20), info.Size())
+
+	head := make([]byte, 6)
+	f.ReadAt(head, 0)
+	assert.Equal(t, "header", string(head))
+}
@@ -0,0 +1,32 @@
+package xfs
+
+import (
+	"os"
+)
+
+// PunchHole deallocates the byte range [offset, offset+length) of the named
+// file without changing its size. Reads from the range return zeros and the
+// file system may release the underlying storage, turning the range into a
+// hole of a sparse file.
+//
+// On Linux this uses fallocate(2) with FALLOC_FL_PUNCH_HOLE and on Windows it
+// uses FSCTL_SET_ZERO_DATA on a file marked sparse. On other platforms, or on
+// file systems without support, the error wraps errors.ErrUnsupported.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - offset: the start of the range in bytes
+//   - length: the length of the range in bytes
+func PunchHole(filename string, offset, length int64) error {
+	f, err := os.OpenFile(filename, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := punchHole(f, offset, length); err != nil {
+		return &os.PathError{Op: "punchhole", Path: filename, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build linux
+
+package xfs
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+func punchHole(f *File, offset, length int64) error {
+	err := unix.Fallocate(int(f.Fd()), unix.FALLOC_FL_PUNCH_HOLE|unix.FALLOC_FL_KEEP_SIZE, offset, length)
+	if err != nil {
+		return unsupportedErrno(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !windows
+
+package xfs
+
+import (
+	"errors"
+)
+
+func punchHole(f *File, offset, length int64) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,43 @@
+package xfs_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	xfs.WriteTextFile(path, "hello world", 0644)
+
+	assert.NoError(t, xfs.Truncate(path, 5))
+	data, _ := xfs.ReadTextFile(path)
+	assert.Equal(t, "hello", data)
+}
+
+func TestPunchHole(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.bin")
+	data := make([]byte, 64*1024)
+	for i := range data {
+		data[i] = 0xff
+	}
+	xfs.WriteFile(path, data, 0644)
+
+	err := xfs.PunchHole(path, 4096, 8192)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("hole punching is not supported here")
+	}
+	assert.NoError(t, err)
+
+	info, _ := os.Stat(path)
+	assert.Equal(t, int64(len(data)), info.Size())
+
+	punched, _ := xfs.ReadFile(path)
+	assert.Equal(t, byte(0xff), punched[4095])
+	assert.Equal(t, make([]byte, 8192), punched[4096:4096+8192])
+	assert.Equal(t, byte(0xff), punched[4096+8192])
+}
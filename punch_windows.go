@@ -0,0 +1,34 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// fileZeroDataInformation mirrors FILE_ZERO_DATA_INFORMATION.
+type fileZeroDataInformation struct {
+	FileOffset      int64
+	BeyondFinalZero int64
+}
+
+func punchHole(f *File, offset, length int64) error {
+	handle := windows.Handle(f.Fd())
+	var returned uint32
+	err := windows.DeviceIoControl(handle, windows.FSCTL_SET_SPARSE, nil, 0, nil, 0, &returned, nil)
+	if err != nil {
+		return unsupportedErrno(err)
+	}
+
+	info := fileZeroDataInformation{FileOffset: offset, BeyondFinalZero: offset + length}
+	err = windows.DeviceIoControl(handle, windows.FSCTL_SET_ZERO_DATA,
+		(*byte)(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)), nil, 0, &returned, nil)
+	if err != nil {
+		return unsupportedErrno(err)
+	}
+
+	return nil
+}
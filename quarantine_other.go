@@ -0,0 +1,5 @@
+//go:build !darwin && !ios
+
+package xfs
+
+func clearQuarantine(filename string) {}
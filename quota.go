@@ -0,0 +1,285 @@
+package xfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrQuotaExceeded is returned when a write would push the space used below
+// a directory above its byte budget.
+var ErrQuotaExceeded = errors.New("xfs: quota exceeded")
+
+// WriteFileQuota writes data to the named file like WriteFile, unless the
+// total size of the regular files below dir would then exceed limit bytes,
+// in which case it writes nothing and returns an error wrapping
+// ErrQuotaExceeded. The current size of filename, which should be inside
+// dir, is not counted, since the write replaces it.
+//
+// The usage is measured by walking dir on every call. Use a QuotaFS to
+// enforce a budget over many writes.
+//
+// The data is flushed to stable storage according to DefaultDurability.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - data: the data to write
+//   - perm: the file permissions
+//   - dir: the directory the budget applies to
+//   - limit: the budget in bytes
+func WriteFileQuota(filename string, data []byte, perm FileMode, dir string, limit int64) error {
+	used, err := usedBytes(os.DirFS(dir), ".")
+	if err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(filename); err == nil && info.Mode().IsRegular() && IsSubPath(dir, filename) {
+		used -= info.Size()
+	}
+
+	if used+int64(len(data)) > limit {
+		return &os.PathError{Op: "write", Path: filename, Err: ErrQuotaExceeded}
+	}
+
+	return writeFile(filename, data, perm, DefaultDurability)
+}
+
+// usedBytes returns the total size of the regular files in fsys below root.
+func usedBytes(fsys fs.FS, root string) (int64, error) {
+	var used int64
+	err := fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			used += info.Size()
+		}
+
+		return nil
+	})
+
+	return used, err
+}
+
+// QuotaFS wraps a WritableFS and rejects writes that would push the total
+// size of its regular files above a byte budget with an error wrapping
+// ErrQuotaExceeded. The usage is measured once by NewQuotaFS and then
+// tracked through the operations made via the QuotaFS; changes made to the
+// underlying file system directly are not seen.
+//
+// Writes are checked before they reach the file, so a rejected write leaves
+// the file unchanged. A QuotaFS is safe for concurrent use, but concurrent
+// writers to the same file may overestimate the usage.
+type QuotaFS struct {
+	fsys  WritableFS
+	limit int64
+
+	mu   sync.Mutex
+	used int64
+}
+
+// NewQuotaFS returns a QuotaFS that limits fsys to limit bytes.
+//
+// Parameters:
+//   - fsys: the file system to limit
+//   - limit: the budget in bytes
+func NewQuotaFS(fsys WritableFS, limit int64) (*QuotaFS, error) {
+	used, err := usedBytes(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuotaFS{fsys: fsys, limit: limit, used: used}, nil
+}
+
+// Used returns the number of bytes currently counted against the budget.
+func (q *QuotaFS) Used() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.used
+}
+
+// Limit returns the budget in bytes.
+func (q *QuotaFS) Limit() int64 {
+	return q.limit
+}
+
+// reserve counts n more bytes against the budget, failing if that would
+// exceed it.
+func (q *QuotaFS) reserve(n int64) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.used+n > q.limit {
+		return false
+	}
+
+	q.used += n
+	return true
+}
+
+func (q *QuotaFS) release(n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.used -= n
+}
+
+// regularSize returns the size of name if it is a regular file, or 0.
+func (q *QuotaFS) regularSize(name string) int64 {
+	info, err := q.fsys.Stat(name)
+	if err != nil || !info.Mode().IsRegular() {
+		return 0
+	}
+
+	return info.Size()
+}
+
+// Open opens the named file for reading.
+//
+// Parameters:
+//   - name: the name of the file
+func (q *QuotaFS) Open(name string) (fs.File, error) {
+	return q.fsys.Open(name)
+}
+
+// Stat returns a FileInfo describing the named file.
+//
+// Parameters:
+//   - name: the name of the file
+func (q *QuotaFS) Stat(name string) (FileInfo, error) {
+	return q.fsys.Stat(name)
+}
+
+// ReadDir reads the named directory and returns its entries sorted by name.
+//
+// Parameters:
+//   - name: the name of the directory
+func (q *QuotaFS) ReadDir(name string) ([]DirEntry, error) {
+	return q.fsys.ReadDir(name)
+}
+
+// OpenFile opens the named file. Writes to a file opened for writing are
+// checked against the budget, and truncating the file returns its size to
+// the budget.
+//
+// Parameters:
+//   - name: the name of the file
+//   - flag: the open flags
+//   - perm: the permissions of a newly created file
+func (q *QuotaFS) OpenFile(name string, flag int, perm FileMode) (WritableFile, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return q.fsys.OpenFile(name, flag, perm)
+	}
+
+	size := q.regularSize(name)
+	f, err := q.fsys.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	qf := &quotaFile{WritableFile: f, q: q, name: name, size: size}
+	if flag&os.O_TRUNC != 0 {
+		q.release(size)
+		qf.size = 0
+	}
+
+	if flag&os.O_APPEND != 0 {
+		qf.off = qf.size
+	}
+
+	return qf, nil
+}
+
+// Mkdir creates the named directory.
+//
+// Parameters:
+//   - name: the name of the directory
+//   - perm: the directory permissions
+func (q *QuotaFS) Mkdir(name string, perm FileMode) error {
+	return q.fsys.Mkdir(name, perm)
+}
+
+// MkdirAll creates the named directory along with any missing parents.
+//
+// Parameters:
+//   - name: the name of the directory
+//   - perm: the directory permissions
+func (q *QuotaFS) MkdirAll(name string, perm FileMode) error {
+	return q.fsys.MkdirAll(name, perm)
+}
+
+// Remove removes the named file or empty directory and returns its size to
+// the budget.
+//
+// Parameters:
+//   - name: the name of the file or directory
+func (q *QuotaFS) Remove(name string) error {
+	size := q.regularSize(name)
+	if err := q.fsys.Remove(name); err != nil {
+		return err
+	}
+
+	q.release(size)
+	return nil
+}
+
+// RemoveAll removes the named path and any children it contains and returns
+// the size of the removed files to the budget.
+//
+// Parameters:
+//   - name: the name of the file or directory
+func (q *QuotaFS) RemoveAll(name string) error {
+	before, _ := usedBytes(q.fsys, name)
+	err := q.fsys.RemoveAll(name)
+	after, _ := usedBytes(q.fsys, name)
+	q.release(before - after)
+	return err
+}
+
+// Rename renames oldname to newname. The size of a file replaced by the
+// rename is returned to the budget.
+//
+// Parameters:
+//   - oldname: the current name
+//   - newname: the new name
+func (q *QuotaFS) Rename(oldname, newname string) error {
+	replaced := q.regularSize(newname)
+	if err := q.fsys.Rename(oldname, newname); err != nil {
+		return err
+	}
+
+	q.release(replaced)
+	return nil
+}
+
+// quotaFile checks every write against the budget of its QuotaFS. It assumes
+// sequential writes from the start of the file, or from its end in append
+// mode.
+type quotaFile struct {
+	WritableFile
+	q    *QuotaFS
+	name string
+	size int64
+	off  int64
+}
+
+func (f *quotaFile) Write(p []byte) (int, error) {
+	growth := max(0, f.off+int64(len(p))-f.size)
+	if !f.q.reserve(growth) {
+		return 0, &os.PathError{Op: "write", Path: filepath.FromSlash(f.name), Err: ErrQuotaExceeded}
+	}
+
+	n, err := f.WritableFile.Write(p)
+	actual := max(0, f.off+int64(n)-f.size)
+	f.q.release(growth - actual)
+	f.off += int64(n)
+	f.size = max(f.size, f.off)
+	return n, err
+}
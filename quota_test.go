@@ -0,0 +1,55 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileQuota(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a"), make([]byte, 60), 0644)
+
+	file := filepath.Join(dir, "b")
+	err := xfs.WriteFileQuota(file, make([]byte, 50), 0644, dir, 100)
+	assert.ErrorIs(t, err, xfs.ErrQuotaExceeded)
+	assert.NoFileExists(t, file)
+
+	assert.NoError(t, xfs.WriteFileQuota(file, make([]byte, 40), 0644, dir, 100))
+
+	// Replacing a file only counts the difference.
+	assert.NoError(t, xfs.WriteFileQuota(file, make([]byte, 40), 0644, dir, 100))
+}
+
+func TestQuotaFS(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a"), make([]byte, 60), 0644)
+
+	q, err := xfs.NewQuotaFS(xfs.DirFS(dir), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(60), q.Used())
+
+	assert.NoError(t, xfs.WriteFileFS(q, "b", make([]byte, 30), 0644))
+	assert.Equal(t, int64(90), q.Used())
+
+	f, err := q.OpenFile("b", os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoError(t, err)
+	_, err = f.Write(make([]byte, 20))
+	assert.ErrorIs(t, err, xfs.ErrQuotaExceeded)
+	_, err = f.Write(make([]byte, 10))
+	assert.NoError(t, err)
+	f.Close()
+	assert.Equal(t, int64(100), q.Used())
+
+	info, _ := os.Stat(filepath.Join(dir, "b"))
+	assert.Equal(t, int64(40), info.Size())
+
+	assert.NoError(t, q.Remove("a"))
+	assert.Equal(t, int64(40), q.Used())
+
+	assert.NoError(t, xfs.WriteFileFS(q, "b", make([]byte, 5), 0644))
+	assert.Equal(t, int64(5), q.Used())
+}
@@ -0,0 +1,76 @@
+package xfs
+
+import (
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// ReadFileAppend reads the named file and appends its contents to buf,
+// returning the extended slice. Passing buf[:0] of a previous result reuses
+// its memory, so loops reading many small files allocate only when a file
+// is larger than any seen before.
+//
+// Parameters:
+//   - buf: the buffer to append to, may be nil
+//   - filename: the name of the file
+func ReadFileAppend(buf []byte, filename string) ([]byte, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return buf, err
+	}
+	defer f.Close()
+
+	// One extra byte lets the final Read observe EOF without growing.
+	if info, err := f.Stat(); err == nil && info.Size() > 0 && int64(int(info.Size())) == info.Size() {
+		buf = slices.Grow(buf, int(info.Size())+1)
+	}
+
+	for {
+		if len(buf) == cap(buf) {
+			buf = slices.Grow(buf, 512)
+		}
+
+		n, err := f.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if err == io.EOF {
+			return buf, nil
+		}
+
+		if err != nil {
+			return buf, err
+		}
+	}
+}
+
+// ReaderPool reads files into pooled buffers, for hot loops that read many
+// files and only need each file's content briefly. The zero value is ready
+// to use and a ReaderPool is safe for concurrent use.
+type ReaderPool struct {
+	pool sync.Pool
+}
+
+// ReadFile reads the named file into a pooled buffer and calls fn with its
+// contents. The data is only valid until fn returns and must not be
+// retained; copy it if needed. ReadFile returns the error from reading the
+// file or from fn.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - fn: the function to call with the contents
+func (p *ReaderPool) ReadFile(filename string, fn func(data []byte) error) error {
+	bufp, _ := p.pool.Get().(*[]byte)
+	if bufp == nil {
+		bufp = new([]byte)
+	}
+	defer p.pool.Put(bufp)
+
+	data, err := ReadFileAppend((*bufp)[:0], filename)
+	*bufp = data
+	if err != nil {
+		return err
+	}
+
+	return fn(data)
+}
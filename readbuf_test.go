@@ -0,0 +1,97 @@
+package xfs_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFileAppend(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	os.WriteFile(a, []byte("hello "), 0644)
+	os.WriteFile(b, bytes.Repeat([]byte("x"), 2000), 0644)
+
+	buf, err := xfs.ReadFileAppend(nil, a)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello ", string(buf))
+
+	buf, err = xfs.ReadFileAppend(buf, b)
+	assert.NoError(t, err)
+	assert.Equal(t, 2006, len(buf))
+
+	buf, err = xfs.ReadFileAppend(buf[:0], a)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello ", string(buf))
+}
+
+func TestReaderPool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(path, []byte("pooled"), 0644)
+
+	var pool xfs.ReaderPool
+	for i := 0; i < 3; i++ {
+		err := pool.ReadFile(path, func(data []byte) error {
+			assert.Equal(t, "pooled", string(data))
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+
+	err := pool.ReadFile(filepath.Join(t.TempDir(), "missing"), func([]byte) error { return nil })
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func smallFiles(b *testing.B) []string {
+	dir := b.TempDir()
+	var files []string
+	for i := 0; i < 100; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("f%d", i))
+		os.WriteFile(name, bytes.Repeat([]byte("x"), 1024+i), 0644)
+		files = append(files, name)
+	}
+
+	return files
+}
+
+func BenchmarkReadFile(b *testing.B) {
+	files := smallFiles(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := xfs.ReadFile(files[i%len(files)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadFileAppend(b *testing.B) {
+	files := smallFiles(b)
+	var buf []byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = xfs.ReadFileAppend(buf[:0], files[i%len(files)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReaderPool(b *testing.B) {
+	files := smallFiles(b)
+	var pool xfs.ReaderPool
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := pool.ReadFile(files[i%len(files)], func([]byte) error { return nil }); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package xfs
+
+import (
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+)
+
+// ReadDirNames reads the named directory and returns the names of its
+// entries sorted by name.
+//
+// Parameters:
+//   - dir: the name of the directory
+func ReadDirNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+
+	return names, nil
+}
+
+// ReadDirFiles reads the named directory and returns the names of the entries
+// that are not directories, sorted by name. Symbolic links are listed unless
+// they point to a directory.
+//
+// Parameters:
+//   - dir: the name of the directory
+func ReadDirFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		if entry.Type()&os.ModeSymlink != 0 && IsDir(filepath.Join(dir, entry.Name())) {
+			continue
+		}
+
+		names = append(names, entry.Name())
+	}
+
+	return names, nil
+}
+
+// ReadDirInfos reads the named directory and returns the FileInfo of its
+// entries sorted by name, as returned by Lstat. Entries removed while the
+// directory is read are left out.
+//
+// Parameters:
+//   - dir: the name of the directory
+func ReadDirInfos(dir string) ([]FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// readDirBatch is the number of entries ReadDirIter requests from the
+// operating system at a time.
+const readDirBatch = 1024
+
+// ReadDirIter returns an iterator over the entries of the named directory
+// that reads them from the operating system in batches, so directories with
+// millions of entries are processed without holding them all in memory.
+// Entries are yielded in directory order, not sorted. An error opening or
+// reading the directory is yielded once with a nil entry and ends the
+// iteration. The directory is closed when the loop ends, including on break.
+//
+// Parameters:
+//   - dir: the name of the directory
+func ReadDirIter(dir string) iter.Seq2[DirEntry, error] {
+	return func(yield func(DirEntry, error) bool) {
+		f, err := os.Open(dir)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer f.Close()
+
+		for {
+			entries, err := f.ReadDir(readDirBatch)
+			for _, entry := range entries {
+				if !yield(entry, nil) {
+					return
+				}
+			}
+
+			if err == io.EOF {
+				return
+			}
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
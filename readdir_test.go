@@ -0,0 +1,65 @@
+package xfs_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDirListings(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "b.txt"), []byte("bb"), 0644)
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+
+	names, err := xfs.ReadDirNames(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "b.txt", "sub"}, names)
+
+	files, err := xfs.ReadDirFiles(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "b.txt"}, files)
+
+	infos, err := xfs.ReadDirInfos(dir)
+	assert.NoError(t, err)
+	assert.Len(t, infos, 3)
+	assert.Equal(t, int64(2), infos[1].Size())
+	assert.True(t, infos[2].IsDir())
+
+	_, err = xfs.ReadDirNames(filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReadDirIter(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 2500; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%04d", i)), nil, 0644)
+	}
+
+	seen := map[string]bool{}
+	for entry, err := range xfs.ReadDirIter(dir) {
+		assert.NoError(t, err)
+		seen[entry.Name()] = true
+	}
+
+	assert.Len(t, seen, 2500)
+
+	count := 0
+	for range xfs.ReadDirIter(dir) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+
+	assert.Equal(t, 10, count)
+
+	for entry, err := range xfs.ReadDirIter(filepath.Join(dir, "missing")) {
+		assert.Nil(t, entry)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	}
+}
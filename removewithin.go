@@ -0,0 +1,78 @@
+package xfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrEscapesRoot is returned by RemoveAllWithin when the path to remove is
+// outside the root directory or reaches it through a symbolic link.
+var ErrEscapesRoot = errors.New("xfs: path escapes from root")
+
+// RemoveAllWithin removes path and any children it contains, like RemoveAll,
+// but only if path stays inside root. The path must not leave root
+// lexically and no directory between root and path may be a symbolic link;
+// otherwise the error wraps ErrEscapesRoot and nothing is removed. Symbolic
+// links inside the removed tree are deleted, never followed.
+//
+// On Unix systems the tree is traversed with openat(2) relative to an open
+// root directory, so a concurrent swap of a directory for a symbolic link
+// cannot redirect the removal outside root. On other platforms the path is
+// checked before RemoveAll is called, which narrows but does not close that
+// window.
+//
+// A relative path is interpreted relative to root. If path does not exist,
+// RemoveAllWithin returns nil. Removing root itself is rejected with an error
+// wrapping fs.ErrInvalid.
+//
+// Parameters:
+//   - root: the directory that confines the removal
+//   - path: the path to remove
+func RemoveAllWithin(root, path string) error {
+	parts, err := rootRelativeParts(root, path)
+	if err != nil {
+		return &os.PathError{Op: "removeall", Path: path, Err: err}
+	}
+
+	if len(parts) == 0 {
+		return &os.PathError{Op: "removeall", Path: path, Err: fs.ErrInvalid}
+	}
+
+	if err := removeAllWithin(root, parts); err != nil {
+		return &os.PathError{Op: "removeall", Path: path, Err: err}
+	}
+
+	return nil
+}
+
+// rootRelativeParts returns the components of path relative to root, or
+// ErrEscapesRoot if path is lexically outside root.
+func rootRelativeParts(root, path string) ([]string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, ok := relWithin(absRoot, absPath)
+	if !ok {
+		return nil, ErrEscapesRoot
+	}
+
+	if rel == "." {
+		return nil, nil
+	}
+
+	return strings.Split(rel, string(filepath.Separator)), nil
+}
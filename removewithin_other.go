@@ -0,0 +1,31 @@
+//go:build !unix
+
+package xfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+func removeAllWithin(root string, parts []string) error {
+	dir := root
+	for _, part := range parts[:len(parts)-1] {
+		dir = filepath.Join(dir, part)
+		info, err := os.Lstat(dir)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if info.Mode()&(fs.ModeSymlink|fs.ModeIrregular) != 0 {
+			return ErrEscapesRoot
+		}
+	}
+
+	return os.RemoveAll(filepath.Join(dir, parts[len(parts)-1]))
+}
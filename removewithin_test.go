@@ -0,0 +1,47 @@
+package xfs_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveAllWithin(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	outside := filepath.Join(base, "outside")
+	xfs.MkdirAllDefault(filepath.Join(root, "a", "b"))
+	xfs.MkdirAllDefault(outside)
+	os.WriteFile(filepath.Join(root, "a", "b", "file"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(outside, "keep"), []byte("x"), 0644)
+
+	err := xfs.RemoveAllWithin(root, filepath.Join("..", "outside"))
+	assert.ErrorIs(t, err, xfs.ErrEscapesRoot)
+	assert.True(t, xfs.Exists(filepath.Join(outside, "keep")))
+
+	err = xfs.RemoveAllWithin(root, root)
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+
+	if runtime.GOOS != "windows" {
+		os.Symlink(outside, filepath.Join(root, "link"))
+		os.Symlink(outside, filepath.Join(root, "a", "inner"))
+
+		err = xfs.RemoveAllWithin(root, filepath.Join(root, "link", "keep"))
+		assert.ErrorIs(t, err, xfs.ErrEscapesRoot)
+		assert.True(t, xfs.Exists(filepath.Join(outside, "keep")))
+
+		assert.NoError(t, xfs.RemoveAllWithin(root, "link"))
+		assert.False(t, xfs.Exists(filepath.Join(root, "link")))
+	}
+
+	assert.NoError(t, xfs.RemoveAllWithin(root, "a"))
+	assert.False(t, xfs.Exists(filepath.Join(root, "a")))
+	assert.True(t, xfs.Exists(filepath.Join(outside, "keep")))
+
+	assert.NoError(t, xfs.RemoveAllWithin(root, "missing/child"))
+}
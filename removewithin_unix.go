@@ -0,0 +1,105 @@
+//go:build unix
+
+package xfs
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+const openDirFlags = unix.O_RDONLY | unix.O_DIRECTORY | unix.O_NOFOLLOW | unix.O_CLOEXEC
+
+func removeAllWithin(root string, parts []string) error {
+	dirfd, err := unix.Open(root, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer func() { unix.Close(dirfd) }()
+
+	for _, part := range parts[:len(parts)-1] {
+		fd, err := unix.Openat(dirfd, part, openDirFlags, 0)
+		if err != nil {
+			return openDirError(dirfd, part, err)
+		}
+
+		unix.Close(dirfd)
+		dirfd = fd
+	}
+
+	return removeAt(dirfd, parts[len(parts)-1])
+}
+
+// openDirError interprets a failure to open name below dirfd without
+// following symbolic links. A missing entry means there is nothing to remove.
+func openDirError(dirfd int, name string, err error) error {
+	if err == unix.ENOENT {
+		return nil
+	}
+
+	var st unix.Stat_t
+	if unix.Fstatat(dirfd, name, &st, unix.AT_SYMLINK_NOFOLLOW) == nil && st.Mode&unix.S_IFMT == unix.S_IFLNK {
+		return ErrEscapesRoot
+	}
+
+	if err == unix.ENOTDIR {
+		return nil
+	}
+
+	return err
+}
+
+// removeAt removes name below dirfd and, if it is a directory, everything in
+// it, without following symbolic links.
+func removeAt(dirfd int, name string) error {
+	var st unix.Stat_t
+	if err := unix.Fstatat(dirfd, name, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		if err == unix.ENOENT {
+			return nil
+		}
+
+		return err
+	}
+
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		return ignoreENOENT(unix.Unlinkat(dirfd, name, 0))
+	}
+
+	for {
+		fd, err := unix.Openat(dirfd, name, openDirFlags, 0)
+		if err != nil {
+			return ignoreENOENT(err)
+		}
+
+		dir := os.NewFile(uintptr(fd), name)
+		names, err := dir.Readdirnames(-1)
+		if err != nil {
+			dir.Close()
+			return err
+		}
+
+		for _, child := range names {
+			if err := removeAt(fd, child); err != nil {
+				dir.Close()
+				return err
+			}
+		}
+
+		dir.Close()
+
+		// Entries created while the directory was being emptied make the
+		// removal fail with ENOTEMPTY, in which case it is emptied again.
+		err = unix.Unlinkat(dirfd, name, unix.AT_REMOVEDIR)
+		if err != unix.ENOTEMPTY && err != unix.EEXIST {
+			return ignoreENOENT(err)
+		}
+	}
+}
+
+func ignoreENOENT(err error) error {
+	if err == unix.ENOENT {
+		return nil
+	}
+
+	return err
+}
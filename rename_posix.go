@@ -0,0 +1,38 @@
+//go:build !windows
+
+package xfs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// RenameAtomic renames (moves) oldpath to newpath, replacing newpath if it
+// exists, and flushes the affected directories to stable storage so that the
+// rename survives a power loss once RenameAtomic returns. This is the commit
+// step of the write-then-rename pattern.
+//
+// On Unix the rename itself is atomic and is followed by an fsync of the
+// destination's parent directory, and of the source's parent directory when
+// it differs. On Windows the rename uses MoveFileEx with
+// MOVEFILE_REPLACE_EXISTING and MOVEFILE_WRITE_THROUGH.
+//
+// Parameters:
+//   - oldpath: the current name of the file or directory
+//   - newpath: the new name of the file or directory
+func RenameAtomic(oldpath, newpath string) error {
+	if err := os.Rename(oldpath, newpath); err != nil {
+		return err
+	}
+
+	newDir := filepath.Dir(newpath)
+	if err := SyncDir(newDir); err != nil {
+		return err
+	}
+
+	if oldDir := filepath.Dir(oldpath); oldDir != newDir {
+		return SyncDir(oldDir)
+	}
+
+	return nil
+}
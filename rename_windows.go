@@ -0,0 +1,62 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+// RenameAtomic renames (moves) oldpath to newpath, replacing newpath if it
+// exists, and flushes the affected directories to stable storage so that the
+// rename survives a power loss once RenameAtomic returns. This is the commit
+// step of the write-then-rename pattern.
+//
+// On Unix the rename itself is atomic and is followed by an fsync of the
+// destination's parent directory, and of the source's parent directory when
+// it differs. On Windows the rename uses MoveFileEx with
+// MOVEFILE_REPLACE_EXISTING and MOVEFILE_WRITE_THROUGH.
+//
+// Parameters:
+//   - oldpath: the current name of the file or directory
+//   - newpath: the new name of the file or directory
+func RenameAtomic(oldpath, newpath string) error {
+	from, err := windows.UTF16PtrFromString(fixLongPath(oldpath))
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+
+	to, err := windows.UTF16PtrFromString(fixLongPath(newpath))
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+
+	err = windows.MoveFileEx(from, to, windows.MOVEFILE_REPLACE_EXISTING|windows.MOVEFILE_WRITE_THROUGH)
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+
+	return nil
+}
+
+// fixLongPath returns the extended-length (\\?\-prefixed) form of an absolute
+// path so that Windows APIs accept paths longer than MAX_PATH.
+func fixLongPath(path string) string {
+	if len(path) < 248 || len(path) >= 4 && path[:4] == `\\?\` {
+		return path
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	if len(abs) >= 2 && abs[:2] == `\\` {
+		return `\\?\UNC\` + abs[2:]
+	}
+
+	return `\\?\` + abs
+}
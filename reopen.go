@@ -0,0 +1,107 @@
+package xfs
+
+import (
+	"io"
+	"os"
+)
+
+// ReopeningReader reads a file that is rotated while it is read, such as an
+// active log file. When it reaches the end of the open file, it checks
+// whether the name now refers to a different file and, if so, switches to
+// it; if the file was truncated in place, it starts over from the beginning.
+// At the end of the current file Read returns io.EOF, and a later Read picks
+// up whatever was written in the meantime, so callers tailing a file poll
+// Read until they are done.
+type ReopeningReader struct {
+	path   string
+	f      *File
+	info   FileInfo
+	offset int64
+}
+
+// OpenReopening opens the named file for reading with a ReopeningReader.
+// The file is opened like OpenShared, so that it can be rotated by renaming
+// it on Windows as well.
+//
+// Parameters:
+//   - filename: the name of the file
+func OpenReopening(filename string) (*ReopeningReader, error) {
+	r := &ReopeningReader{path: filename}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *ReopeningReader) open() error {
+	f, err := openShared(r.path)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	if r.f != nil {
+		r.f.Close()
+	}
+
+	r.f, r.info, r.offset = f, info, 0
+	return nil
+}
+
+// Name returns the name of the file being read.
+func (r *ReopeningReader) Name() string {
+	return r.path
+}
+
+// Read reads up to len(p) bytes from the current file, switching to the new
+// file first if the old one was fully read and has been rotated. While the
+// file is missing, for example between a rotation's rename and the creation
+// of the new file, Read reports io.EOF.
+//
+// Parameters:
+//   - p: the buffer to read into
+func (r *ReopeningReader) Read(p []byte) (int, error) {
+	n, err := r.read(p)
+	if n > 0 || err != io.EOF {
+		return n, err
+	}
+
+	current, statErr := os.Stat(r.path)
+	if statErr != nil {
+		return 0, io.EOF
+	}
+
+	switch {
+	case !os.SameFile(current, r.info):
+		if r.open() != nil {
+			return 0, io.EOF
+		}
+	case current.Size() < r.offset:
+		if _, err := r.f.Seek(0, io.SeekStart); err != nil {
+			return 0, err
+		}
+
+		r.offset = 0
+	default:
+		return 0, io.EOF
+	}
+
+	return r.read(p)
+}
+
+func (r *ReopeningReader) read(p []byte) (int, error) {
+	n, err := r.f.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+// Close closes the file being read.
+func (r *ReopeningReader) Close() error {
+	return r.f.Close()
+}
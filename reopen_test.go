@@ -0,0 +1,43 @@
+package xfs_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenReopening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte("one\n"), 0644)
+
+	r, err := xfs.OpenReopening(path)
+	assert.NoError(t, err)
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "one\n", string(data))
+
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.WriteString("two\n")
+	f.Close()
+
+	data, _ = io.ReadAll(r)
+	assert.Equal(t, "two\n", string(data))
+
+	os.WriteFile(path, []byte("3\n"), 0644)
+	data, _ = io.ReadAll(r)
+	assert.Equal(t, "3\n", string(data))
+
+	assert.NoError(t, os.Rename(path, path+".1"))
+	data, _ = io.ReadAll(r)
+	assert.Empty(t, data)
+
+	os.WriteFile(path, []byte("rotated\n"), 0644)
+	data, _ = io.ReadAll(r)
+	assert.Equal(t, "rotated\n", string(data))
+}
@@ -0,0 +1,51 @@
+package xfs
+
+import (
+	"io"
+	"os"
+)
+
+// ReplaceExecutable replaces the executable file at path with the content of
+// newBinary, even while path is the running program, for self-updating
+// tools. The new file keeps the permissions of the old one, or gets 0755 if
+// path does not exist, and is written next to it before it takes its place,
+// so path never refers to a partially written binary.
+//
+// On Unix the new file is renamed over path; the running process keeps
+// executing the old file, which is freed when it exits. On macOS the
+// com.apple.quarantine attribute is also removed from the new file so that
+// Gatekeeper does not block it. On Windows, where a running executable cannot
+// be overwritten but can be renamed, the old file is first moved aside to
+// path+".old", which is removed by the next ReplaceExecutable once the old
+// program has exited. If the replacement fails, the old file is moved back.
+//
+// Parameters:
+//   - path: the executable to replace, e.g. from os.Executable
+//   - newBinary: the content of the new executable
+func ReplaceExecutable(path string, newBinary io.Reader) error {
+	perm := FileMode(0755)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	w, err := NewAtomicWriter(path, perm, nil)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if _, err := io.Copy(w, newBinary); err != nil {
+		return err
+	}
+
+	if err := w.finish(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(w.Name(), perm); err != nil {
+		return err
+	}
+
+	clearQuarantine(w.Name())
+	return replaceExecutable(w, path)
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package xfs
+
+import "os"
+
+func replaceExecutable(w *AtomicWriter, path string) error {
+	w.closed = true
+	if err := w.publish(); err != nil {
+		os.Remove(w.Name())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceExecutable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tool")
+	os.WriteFile(path, []byte("v1"), 0750)
+	os.Chmod(path, 0750)
+
+	running, err := xfs.OpenShared(path)
+	assert.NoError(t, err)
+	defer running.Close()
+
+	assert.NoError(t, xfs.ReplaceExecutable(path, strings.NewReader("v2")))
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "v2", string(data))
+
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(path)
+		assert.Equal(t, xfs.FileMode(0750), info.Mode().Perm())
+
+		old := make([]byte, 2)
+		running.Read(old)
+		assert.Equal(t, "v1", string(old))
+
+		names, _ := xfs.ReadDirNames(dir)
+		assert.Equal(t, []string{"tool"}, names)
+	}
+
+	fresh := filepath.Join(dir, "fresh")
+	assert.NoError(t, xfs.ReplaceExecutable(fresh, strings.NewReader("new")))
+	assert.True(t, xfs.IsFile(fresh))
+}
@@ -0,0 +1,39 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import "os"
+
+func replaceExecutable(w *AtomicWriter, path string) error {
+	old := path + ".old"
+	if err := os.Remove(old); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	moved := false
+	if _, err := os.Lstat(path); err == nil {
+		if err := os.Rename(path, old); err != nil {
+			return err
+		}
+
+		moved = true
+	}
+
+	w.closed = true
+	if err := w.publish(); err != nil {
+		os.Remove(w.Name())
+		if moved {
+			os.Rename(old, path)
+		}
+
+		return err
+	}
+
+	if moved {
+		// Succeeds unless old is the running program.
+		os.Remove(old)
+	}
+
+	return nil
+}
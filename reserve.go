@@ -0,0 +1,150 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// PartialSuffix is appended to the name of a file reserved with Reserve
+// until the reservation is committed. Consumers scanning a directory should
+// skip names with this suffix, see IsPartial.
+const PartialSuffix = ".partial"
+
+// errReservationClosed is returned when a Reservation is used after it was
+// committed or aborted.
+var errReservationClosed = errors.New("reservation already closed")
+
+// Reservation is a placeholder for a file that is being produced. The data
+// is written to the placeholder, named after the target with PartialSuffix,
+// which becomes the target on Commit.
+type Reservation struct {
+	f      *File
+	path   string
+	closed bool
+}
+
+// Reserve claims the named file for a producer by creating its placeholder
+// exclusively and preallocating size bytes, so that a second producer of the
+// same target fails with an error matching os.ErrExist and a full disk is
+// detected before any data is written. Reserve also fails if the target
+// itself already exists. A Reservation must be finished with Commit or
+// Abort; Close aborts an uncommitted one.
+//
+// Parameters:
+//   - filename: the name of the target file
+//   - size: the final size of the file in bytes, zero to skip preallocation
+func Reserve(filename string, size int64) (*Reservation, error) {
+	if _, err := os.Lstat(filename); err == nil {
+		return nil, &os.PathError{Op: "reserve", Path: filename, Err: os.ErrExist}
+	}
+
+	f, err := os.OpenFile(filename+PartialSuffix, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if size > 0 {
+		if err := Preallocate(f, size); err != nil {
+			f.Close()
+			os.Remove(f.Name())
+			return nil, err
+		}
+	}
+
+	return &Reservation{f: f, path: filename}, nil
+}
+
+// IsPartial reports whether the name is the placeholder of a reservation
+// that has not been committed.
+//
+// Parameters:
+//   - filename: the name of the file
+func IsPartial(filename string) bool {
+	return strings.HasSuffix(filename, PartialSuffix)
+}
+
+// Name returns the name of the placeholder file.
+func (r *Reservation) Name() string {
+	return r.f.Name()
+}
+
+// File returns the open placeholder file, e.g. to write ranges with WriteAt.
+func (r *Reservation) File() *File {
+	return r.f
+}
+
+// Write writes p to the placeholder file.
+//
+// Parameters:
+//   - p: the data to write
+func (r *Reservation) Write(p []byte) (int, error) {
+	if r.closed {
+		return 0, &os.PathError{Op: "write", Path: r.path, Err: errReservationClosed}
+	}
+
+	return r.f.Write(p)
+}
+
+// Commit closes the placeholder and publishes it under the target name,
+// making the file visible to consumers. The target is never replaced: if it
+// was created by someone else after Reserve, Commit fails with an error
+// matching os.ErrExist. On failure the placeholder is removed.
+//
+// The placeholder is hard linked to the target and then removed. Where hard
+// links are not supported, Commit checks that the target does not exist and
+// renames the placeholder, which leaves a short window for a race.
+func (r *Reservation) Commit() error {
+	if r.closed {
+		return &os.PathError{Op: "commit", Path: r.path, Err: errReservationClosed}
+	}
+
+	r.closed = true
+	err := syncData(r.f, DefaultDurability)
+	if closeErr := r.f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = publishNoReplace(r.f.Name(), r.path)
+	}
+
+	os.Remove(r.f.Name())
+	return err
+}
+
+// publishNoReplace gives the file at oldpath the name newpath, failing if
+// newpath exists. oldpath is left in place for the caller to remove.
+func publishNoReplace(oldpath, newpath string) error {
+	err := os.Link(oldpath, newpath)
+	if err != nil && !os.IsExist(err) && cannotLink(err) {
+		if _, statErr := os.Lstat(newpath); statErr == nil {
+			return &os.LinkError{Op: "commit", Old: oldpath, New: newpath, Err: os.ErrExist}
+		}
+
+		err = os.Rename(oldpath, newpath)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return syncParent(newpath, DefaultDurability)
+}
+
+// Abort removes the placeholder and releases the target for other producers.
+// Aborting a committed or aborted reservation is a no-op.
+func (r *Reservation) Abort() error {
+	if r.closed {
+		return nil
+	}
+
+	r.closed = true
+	r.f.Close()
+	return os.Remove(r.f.Name())
+}
+
+// Close aborts the reservation unless it was committed.
+func (r *Reservation) Close() error {
+	return r.Abort()
+}
@@ -0,0 +1,58 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReserve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.mp4")
+
+	r, err := xfs.Reserve(path, 10)
+	assert.NoError(t, err)
+	defer r.Close()
+	assert.True(t, xfs.IsPartial(r.Name()))
+
+	info, _ := os.Stat(r.Name())
+	assert.Equal(t, int64(10), info.Size())
+
+	_, err = xfs.Reserve(path, 10)
+	assert.ErrorIs(t, err, os.ErrExist)
+
+	r.Write([]byte("0123456789"))
+	assert.False(t, xfs.Exists(path))
+	assert.NoError(t, r.Commit())
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "0123456789", string(data))
+	assert.False(t, xfs.Exists(path+xfs.PartialSuffix))
+
+	_, err = xfs.Reserve(path, 0)
+	assert.ErrorIs(t, err, os.ErrExist)
+
+	other := filepath.Join(filepath.Dir(path), "other")
+	r, err = xfs.Reserve(other, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Abort())
+	assert.False(t, xfs.Exists(other+xfs.PartialSuffix))
+	assert.False(t, xfs.Exists(other))
+}
+
+func TestReserveCommitNoReplace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.csv")
+
+	r, err := xfs.Reserve(path, 0)
+	assert.NoError(t, err)
+	r.Write([]byte("late"))
+
+	os.WriteFile(path, []byte("first"), 0644)
+	assert.ErrorIs(t, r.Commit(), os.ErrExist)
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "first", string(data))
+	assert.False(t, xfs.Exists(path+xfs.PartialSuffix))
+}
@@ -0,0 +1,115 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrIsSymlink is returned, wrapped in a *PathError, by ResolveOpt in strict
+// mode when the path passes through a symbolic link.
+var ErrIsSymlink = errors.New("xfs: path is a symbolic link")
+
+// ResolveOptions controls ResolveOpt.
+type ResolveOptions struct {
+	// Strict requires the resolved path to stay inside the base directory,
+	// to exist and to reach its target without passing through symbolic
+	// links. Violations are reported with errors wrapping ErrNotSubPath,
+	// fs.ErrNotExist and ErrIsSymlink respectively.
+	Strict bool
+	// AllowSymlinks permits symbolic links in strict mode as long as the
+	// fully resolved path is still inside the base directory.
+	AllowSymlinks bool
+}
+
+// ResolveOpt resolves the relative path to an absolute path like Resolve. In
+// strict mode it also validates the result against the base directory,
+// which makes it suitable for paths taken from users, requests or
+// configuration that must not reach outside a given directory.
+//
+// Parameters:
+//   - relative: the relative path
+//   - base: the base path, the current working directory if empty
+//   - opts: the resolve options, nil behaves like Resolve
+func ResolveOpt(relative string, base string, opts *ResolveOptions) (string, error) {
+	if base == "" {
+		base, _ = Cwd()
+	}
+
+	resolved, err := Resolve(relative, base)
+	if err != nil || opts == nil || !opts.Strict {
+		return resolved, err
+	}
+
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+
+	resolved = filepath.Clean(resolved)
+	rel, inside := relWithin(absBase, resolved)
+	if !inside {
+		return "", &os.PathError{Op: "resolve", Path: relative, Err: ErrNotSubPath}
+	}
+
+	if opts.AllowSymlinks {
+		if err := checkResolvedTarget(relative, absBase, resolved); err != nil {
+			return "", err
+		}
+
+		return resolved, nil
+	}
+
+	current := absBase
+	if rel != "." {
+		for _, part := range strings.Split(rel, string(filepath.Separator)) {
+			current = filepath.Join(current, part)
+			info, err := os.Lstat(current)
+			if err != nil {
+				return "", &os.PathError{Op: "resolve", Path: relative, Err: unwrapPathError(err)}
+			}
+
+			if info.Mode()&os.ModeSymlink != 0 {
+				return "", &os.PathError{Op: "resolve", Path: relative, Err: ErrIsSymlink}
+			}
+		}
+	}
+
+	if _, err := os.Stat(resolved); err != nil {
+		return "", &os.PathError{Op: "resolve", Path: relative, Err: unwrapPathError(err)}
+	}
+
+	return resolved, nil
+}
+
+// checkResolvedTarget checks that resolved exists and that its target, with
+// all symbolic links evaluated, is inside base.
+func checkResolvedTarget(relative, base, resolved string) error {
+	target, err := filepath.EvalSymlinks(resolved)
+	if err != nil {
+		return &os.PathError{Op: "resolve", Path: relative, Err: unwrapPathError(err)}
+	}
+
+	realBase, err := filepath.EvalSymlinks(base)
+	if err != nil {
+		return &os.PathError{Op: "resolve", Path: relative, Err: unwrapPathError(err)}
+	}
+
+	if _, inside := relWithin(realBase, target); !inside {
+		return &os.PathError{Op: "resolve", Path: relative, Err: ErrNotSubPath}
+	}
+
+	return nil
+}
+
+// unwrapPathError returns the underlying error of a *PathError so it can be
+// rewrapped with another operation and path.
+func unwrapPathError(err error) error {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+
+	return err
+}
@@ -0,0 +1,50 @@
+package xfs_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveOptStrict(t *testing.T) {
+	base := t.TempDir()
+	os.MkdirAll(filepath.Join(base, "docs"), 0755)
+	os.WriteFile(filepath.Join(base, "docs", "a.txt"), nil, 0644)
+	strict := &xfs.ResolveOptions{Strict: true}
+
+	path, err := xfs.ResolveOpt("docs/a.txt", base, strict)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "docs", "a.txt"), path)
+
+	_, err = xfs.ResolveOpt("../outside", base, strict)
+	assert.ErrorIs(t, err, xfs.ErrNotSubPath)
+
+	_, err = xfs.ResolveOpt("docs/missing.txt", base, strict)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	path, err = xfs.ResolveOpt("../outside", base, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(base), "outside"), path)
+
+	if !xfs.CanSymlink() {
+		return
+	}
+
+	os.Symlink("docs", filepath.Join(base, "inner"))
+	os.Symlink(os.TempDir(), filepath.Join(base, "escape"))
+
+	_, err = xfs.ResolveOpt("inner/a.txt", base, strict)
+	assert.ErrorIs(t, err, xfs.ErrIsSymlink)
+
+	allow := &xfs.ResolveOptions{Strict: true, AllowSymlinks: true}
+	_, err = xfs.ResolveOpt("inner/a.txt", base, allow)
+	assert.NoError(t, err)
+
+	resolved, err := xfs.ResolveOpt("escape", base, allow)
+	assert.ErrorIs(t, err, xfs.ErrNotSubPath)
+	assert.Empty(t, resolved)
+}
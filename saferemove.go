@@ -0,0 +1,128 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrRefusedUnsafePath is returned by RemoveAll when the path is a file system
+// root, the user's home directory or one of its parents, or is too shallow to
+// be removed without the Force option.
+var ErrRefusedUnsafePath = errors.New("xfs: refusing to remove unsafe path")
+
+// DefaultRemoveMinDepth is the number of path elements below the file system
+// root that a path needs before RemoveAll removes it, so "/usr" or "C:\Users"
+// are refused while "/tmp/build" is allowed.
+const DefaultRemoveMinDepth = 2
+
+// RemoveOptions controls the safety checks of RemoveAllOpt.
+type RemoveOptions struct {
+	// Force disables the safety checks.
+	Force bool
+	// MinDepth is the number of path elements below the file system root
+	// that the absolute path must have. Zero uses DefaultRemoveMinDepth.
+	MinDepth int
+	// Filter makes RemoveAllOpt remove only the files below path that it
+	// matches, symbolic links included, and keep all directories, e.g. to
+	// prune old logs.
+	Filter *FileFilter
+}
+
+// RemoveAllOpt removes path and any children it contains like RemoveAll,
+// after checking that path is safe to remove. Unless opts.Force is set, it
+// returns an error wrapping ErrRefusedUnsafePath for "/", drive and UNC share
+// roots, the home directory and its parents, and paths with fewer than
+// opts.MinDepth elements, which guards against an empty or mistyped variable
+// turning a cleanup into a wipe.
+//
+// Parameters:
+//   - path: the name of the file or directory
+//   - opts: the safety options, nil uses the defaults
+func RemoveAllOpt(path string, opts *RemoveOptions) error {
+	if opts == nil {
+		opts = &RemoveOptions{}
+	}
+
+	if path != "" && !opts.Force {
+		if err := checkRemovePath(path, opts.MinDepth); err != nil {
+			return &os.PathError{Op: "removeall", Path: path, Err: err}
+		}
+	}
+
+	if opts.Filter != nil {
+		return removeMatching(path, opts.Filter)
+	}
+
+	return os.RemoveAll(path)
+}
+
+// removeMatching removes the files below root matched by filter.
+func removeMatching(root string, filter *FileFilter) error {
+	return filepath.WalkDir(root, func(path string, d DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !filter.Match(info) {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// checkRemovePath returns ErrRefusedUnsafePath if path must not be removed.
+func checkRemovePath(path string, minDepth int) error {
+	if minDepth <= 0 {
+		minDepth = DefaultRemoveMinDepth
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	if pathElements(abs) < minDepth {
+		return ErrRefusedUnsafePath
+	}
+
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if absHome, err := filepath.Abs(home); err == nil {
+			if _, inside := relWithin(abs, absHome); inside {
+				return ErrRefusedUnsafePath
+			}
+		}
+	}
+
+	return nil
+}
+
+// pathElements returns the number of elements of the absolute, clean path
+// below its volume root.
+func pathElements(abs string) int {
+	rest := strings.Trim(abs[len(filepath.VolumeName(abs)):], string(filepath.Separator))
+	if rest == "" {
+		return 0
+	}
+
+	return strings.Count(rest, string(filepath.Separator)) + 1
+}
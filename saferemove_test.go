@@ -0,0 +1,43 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveAllOpt(t *testing.T) {
+	root := string(filepath.Separator)
+	if vol := filepath.VolumeName(os.TempDir()); vol != "" {
+		root = vol + root
+	}
+
+	err := xfs.RemoveAll(root)
+	assert.ErrorIs(t, err, xfs.ErrRefusedUnsafePath)
+
+	err = xfs.RemoveAll(filepath.Join(root, "usr"))
+	assert.ErrorIs(t, err, xfs.ErrRefusedUnsafePath)
+
+	if home, err := os.UserHomeDir(); err == nil {
+		err = xfs.RemoveAll(home)
+		assert.ErrorIs(t, err, xfs.ErrRefusedUnsafePath)
+
+		err = xfs.RemoveAll(filepath.Join(home, ".."))
+		assert.ErrorIs(t, err, xfs.ErrRefusedUnsafePath)
+	}
+
+	dir := filepath.Join(t.TempDir(), "build")
+	xfs.MkdirAllDefault(filepath.Join(dir, "out"))
+
+	err = xfs.RemoveAllOpt(dir, &xfs.RemoveOptions{MinDepth: 100})
+	assert.ErrorIs(t, err, xfs.ErrRefusedUnsafePath)
+	assert.True(t, xfs.Exists(dir))
+
+	assert.NoError(t, xfs.RemoveAllOpt(dir, &xfs.RemoveOptions{MinDepth: 100, Force: true}))
+	assert.False(t, xfs.Exists(dir))
+
+	assert.NoError(t, xfs.RemoveAll(""))
+}
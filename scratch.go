@@ -0,0 +1,105 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+var (
+	scratchMu   sync.Mutex
+	scratchDirs = map[string]struct{}{}
+)
+
+// NewScratchDir creates a new temporary directory in the default directory
+// for temporary files, like MkdirTemp with the pattern prefix+"*", and records
+// it in a package-level registry so that CleanupAll can remove it together
+// with every other scratch directory the program created.
+//
+// Parameters:
+//   - prefix: the directory name prefix
+func NewScratchDir(prefix string) (string, error) {
+	dir, err := os.MkdirTemp("", prefix+"*")
+	if err != nil {
+		return "", err
+	}
+
+	scratchMu.Lock()
+	scratchDirs[dir] = struct{}{}
+	scratchMu.Unlock()
+	return dir, nil
+}
+
+// RemoveScratchDir removes a directory created by NewScratchDir along with
+// its contents and drops it from the registry.
+//
+// Parameters:
+//   - dir: the scratch directory
+func RemoveScratchDir(dir string) error {
+	scratchMu.Lock()
+	delete(scratchDirs, dir)
+	scratchMu.Unlock()
+	return os.RemoveAll(dir)
+}
+
+// CleanupAll removes every directory created by NewScratchDir that has not
+// been removed yet. Directories that cannot be removed stay registered, and
+// the errors are joined.
+func CleanupAll() error {
+	scratchMu.Lock()
+	defer scratchMu.Unlock()
+
+	var errs []error
+	for dir := range scratchDirs {
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		delete(scratchDirs, dir)
+	}
+
+	return errors.Join(errs...)
+}
+
+// CleanupOnSignal runs CleanupAll when the process receives one of the
+// signals, os.Interrupt if none are given, and then lets the signal take its
+// default effect, which normally terminates the process. Pass syscall.SIGTERM
+// as well to clean up when the process is asked to stop. Call the returned
+// function to stop handling the signals, typically with defer in main,
+// together with a final CleanupAll.
+//
+// Parameters:
+//   - sigs: the signals to handle
+func CleanupOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		select {
+		case sig := <-ch:
+			CleanupAll()
+			signal.Reset(sigs...)
+			if p, err := os.FindProcess(os.Getpid()); err == nil && p.Signal(sig) == nil {
+				return
+			}
+
+			os.Exit(1)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package xfs_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScratchDirs(t *testing.T) {
+	a, err := xfs.NewScratchDir("xfs-scratch-")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(filepath.Base(a), "xfs-scratch-"))
+	assert.True(t, xfs.IsDir(a))
+
+	b, err := xfs.NewScratchDir("xfs-scratch-")
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, b)
+	xfs.WriteTextFile(filepath.Join(b, "file.txt"), "data", 0644)
+
+	c, err := xfs.NewScratchDir("xfs-scratch-")
+	assert.NoError(t, err)
+	assert.NoError(t, xfs.RemoveScratchDir(c))
+	assert.False(t, xfs.Exists(c))
+
+	assert.NoError(t, xfs.CleanupAll())
+	assert.False(t, xfs.Exists(a))
+	assert.False(t, xfs.Exists(b))
+	assert.NoError(t, xfs.CleanupAll())
+}
+
+func TestCleanupOnSignal(t *testing.T) {
+	stop := xfs.CleanupOnSignal()
+	stop()
+	stop()
+}
@@ -0,0 +1,198 @@
+package xfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ScrubProblemKind classifies a problem found by Scrub.
+type ScrubProblemKind int
+
+const (
+	// ScrubMissing is a file of the manifest that no longer exists.
+	ScrubMissing ScrubProblemKind = iota
+	// ScrubCorrupt is a file whose content hash, or a symbolic link whose
+	// target, differs from the manifest.
+	ScrubCorrupt
+	// ScrubError is a file that could not be read.
+	ScrubError
+)
+
+// String returns the name of the kind.
+func (k ScrubProblemKind) String() string {
+	switch k {
+	case ScrubMissing:
+		return "missing"
+	case ScrubCorrupt:
+		return "corrupt"
+	case ScrubError:
+		return "error"
+	}
+
+	return "unknown"
+}
+
+// ScrubProblem is a file reported by Scrub.
+type ScrubProblem struct {
+	// Path is the slash separated path relative to the scrubbed root.
+	Path string
+	// Kind classifies the problem.
+	Kind ScrubProblemKind
+	// Expected is the hash or link target recorded in the manifest.
+	Expected string
+	// Actual is the hash or link target found, empty unless Kind is
+	// ScrubCorrupt.
+	Actual string
+	// Err is the error for ScrubMissing and ScrubError.
+	Err error
+}
+
+// ScrubOptions controls Scrub.
+type ScrubOptions struct {
+	// BytesPerSecond limits the read rate so that scrubbing does not compete
+	// with the real workload. Zero means no limit.
+	BytesPerSecond int64
+	// Interval makes Scrub run continuously: after each pass it waits for
+	// the interval and starts over, until the context is done. Zero runs a
+	// single pass.
+	Interval time.Duration
+	// OnProblem is called for every missing, corrupt or unreadable file.
+	OnProblem func(p ScrubProblem)
+	// OnPass is called after each complete pass with the number of files
+	// checked.
+	OnPass func(files int)
+}
+
+// Scrub re-hashes the files below root one at a time and compares them with
+// manifest, a snapshot taken when the data was known to be good, reporting
+// silent corruption and lost files through opts.OnProblem. Reads are
+// throttled to opts.BytesPerSecond, and with opts.Interval set Scrub keeps
+// verifying the tree until ctx is done, which suits storage daemons that
+// scrub in the background. Files added since the manifest was taken are
+// ignored. Scrub returns the context's error once ctx is done, or nil after a
+// single pass.
+//
+// Parameters:
+//   - ctx: the context that stops scrubbing
+//   - root: the directory to scrub
+//   - manifest: the manifest to verify against, see Snapshot
+//   - opts: the scrub options, nil uses the defaults
+func Scrub(ctx context.Context, root string, manifest *Manifest, opts *ScrubOptions) error {
+	o := ScrubOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var limiter *rateLimiter
+	if o.BytesPerSecond > 0 {
+		limiter = &rateLimiter{rate: o.BytesPerSecond}
+	}
+
+	report := func(p ScrubProblem) {
+		if o.OnProblem != nil {
+			o.OnProblem(p)
+		}
+	}
+
+	for {
+		files := 0
+		for _, entry := range manifest.Entries {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if entry.Hash == "" && entry.Target == "" {
+				continue
+			}
+
+			files++
+			if err := scrubEntry(ctx, root, entry, limiter, report); err != nil {
+				return err
+			}
+		}
+
+		if o.OnPass != nil {
+			o.OnPass(files)
+		}
+
+		if o.Interval <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(o.Interval):
+		}
+	}
+}
+
+// scrubEntry verifies a single manifest entry. It only returns an error
+// when ctx is done.
+func scrubEntry(ctx context.Context, root string, entry ManifestEntry, limiter *rateLimiter, report func(ScrubProblem)) error {
+	path := filepath.Join(root, filepath.FromSlash(entry.Path))
+	if entry.Target != "" {
+		target, err := os.Readlink(path)
+		switch {
+		case os.IsNotExist(err):
+			report(ScrubProblem{Path: entry.Path, Kind: ScrubMissing, Expected: entry.Target, Err: err})
+		case err != nil:
+			report(ScrubProblem{Path: entry.Path, Kind: ScrubError, Expected: entry.Target, Err: err})
+		case target != entry.Target:
+			report(ScrubProblem{Path: entry.Path, Kind: ScrubCorrupt, Expected: entry.Target, Actual: target})
+		}
+
+		return nil
+	}
+
+	sum, err := hashFileThrottled(ctx, path, limiter)
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case os.IsNotExist(err):
+		report(ScrubProblem{Path: entry.Path, Kind: ScrubMissing, Expected: entry.Hash, Err: err})
+	case err != nil:
+		report(ScrubProblem{Path: entry.Path, Kind: ScrubError, Expected: entry.Hash, Err: err})
+	case sum != entry.Hash:
+		report(ScrubProblem{Path: entry.Path, Kind: ScrubCorrupt, Expected: entry.Hash, Actual: sum})
+	}
+
+	return nil
+}
+
+// hashFileThrottled hashes the named file like HashFile, reading at most at
+// the limiter's rate and stopping when ctx is done.
+func hashFileThrottled(ctx context.Context, filename string, limiter *rateLimiter) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	buf := make([]byte, copyQueueChunk)
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		n, err := f.Read(buf)
+		if n > 0 {
+			limiter.wait(n)
+			h.Write(buf[:n])
+		}
+
+		if err == io.EOF {
+			return hex.EncodeToString(h.Sum(nil)), nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+	}
+}
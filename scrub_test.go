@@ -0,0 +1,79 @@
+package xfs_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrub(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "sub"), 0755)
+	os.WriteFile(filepath.Join(root, "good.txt"), []byte("good"), 0644)
+	os.WriteFile(filepath.Join(root, "sub", "rot.txt"), []byte("original"), 0644)
+	os.WriteFile(filepath.Join(root, "lost.txt"), []byte("lost"), 0644)
+
+	manifest, err := xfs.Snapshot(root)
+	assert.NoError(t, err)
+
+	os.WriteFile(filepath.Join(root, "sub", "rot.txt"), []byte("0riginal"), 0644)
+	os.Remove(filepath.Join(root, "lost.txt"))
+	os.WriteFile(filepath.Join(root, "new.txt"), []byte("new"), 0644)
+
+	var problems []xfs.ScrubProblem
+	passes := 0
+	err = xfs.Scrub(context.Background(), root, manifest, &xfs.ScrubOptions{
+		OnProblem: func(p xfs.ScrubProblem) { problems = append(problems, p) },
+		OnPass:    func(files int) { passes++; assert.Equal(t, 3, files) },
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, passes)
+	assert.Len(t, problems, 2)
+	assert.Equal(t, "lost.txt", problems[0].Path)
+	assert.Equal(t, xfs.ScrubMissing, problems[0].Kind)
+	assert.ErrorIs(t, problems[0].Err, os.ErrNotExist)
+	assert.Equal(t, "sub/rot.txt", problems[1].Path)
+	assert.Equal(t, "corrupt", problems[1].Kind.String())
+	assert.NotEqual(t, problems[1].Expected, problems[1].Actual)
+}
+
+func TestScrubContinuous(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "data"), make([]byte, 64<<10), 0644)
+	manifest, _ := xfs.Snapshot(root)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	passes := 0
+	done := make(chan error)
+	go func() {
+		done <- xfs.Scrub(ctx, root, manifest, &xfs.ScrubOptions{
+			BytesPerSecond: 1 << 20,
+			Interval:       time.Millisecond,
+			OnPass: func(int) {
+				mu.Lock()
+				passes++
+				if passes == 3 {
+					cancel()
+				}
+				mu.Unlock()
+			},
+		})
+	}()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(10 * time.Second):
+		t.Fatal("scrub did not stop")
+	}
+
+	assert.Equal(t, 3, passes)
+}
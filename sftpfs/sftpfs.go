@@ -0,0 +1,324 @@
+// Package sftpfs provides an xfs.WritableFS backed by an SFTP connection, so
+// that the file system helpers of xfs, such as EnsureDirFS, WriteTextFileFS
+// and CopyDirFS, can operate on remote hosts.
+package sftpfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/pkg/sftp"
+)
+
+// FS is an xfs.WritableFS for the tree of files rooted at a directory on an
+// SFTP server. Names are resolved relative to the root with forward slashes,
+// as the SFTP protocol requires, and cannot escape it lexically.
+//
+// Permission bits passed to OpenFile, Mkdir and MkdirAll are applied with an
+// explicit chmod, so the remote umask does not affect them.
+type FS struct {
+	client *sftp.Client
+	root   string
+}
+
+var _ xfs.WritableFS = (*FS)(nil)
+
+// New returns an FS for the directory root on the server client is
+// connected to. The caller remains responsible for closing the client.
+//
+// Parameters:
+//   - client: the SFTP client
+//   - root: the remote directory, such as "/srv/app" or "." for the login directory
+func New(client *sftp.Client, root string) *FS {
+	return &FS{client: client, root: root}
+}
+
+func (fsys *FS) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &os.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	return path.Join(fsys.root, name), nil
+}
+
+// pathErr reports err, which may come from the server or the sftp package,
+// as a *PathError for name.
+func pathErr(op, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &os.PathError{Op: op, Path: name, Err: cause(err)}
+}
+
+// cause strips the path from err and maps SFTP status codes to the
+// corresponding fs errors.
+func cause(err error) error {
+	var pe *os.PathError
+	if errors.As(err, &pe) {
+		err = pe.Err
+	}
+
+	var status *sftp.StatusError
+	if errors.As(err, &status) {
+		switch status.FxCode() {
+		case sftp.ErrSSHFxNoSuchFile:
+			return fs.ErrNotExist
+		case sftp.ErrSSHFxPermissionDenied:
+			return fs.ErrPermission
+		}
+	}
+
+	return err
+}
+
+// Open opens the named file for reading.
+//
+// Parameters:
+//   - name: the name of the file
+func (fsys *FS) Open(name string) (fs.File, error) {
+	return fsys.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile opens the named file with the given flags. When the file is
+// created, its permissions are set to perm.
+//
+// Parameters:
+//   - name: the name of the file
+//   - flag: the flags such as os.O_RDONLY or os.O_CREATE
+//   - perm: the permission bits used when creating the file
+func (fsys *FS) OpenFile(name string, flag int, perm xfs.FileMode) (xfs.WritableFile, error) {
+	p, err := fsys.join("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	created := false
+	if flag&os.O_CREATE != 0 {
+		_, err := fsys.client.Lstat(p)
+		created = err != nil
+	}
+
+	f, err := fsys.client.OpenFile(p, flag)
+	if err != nil {
+		return nil, pathErr("open", name, err)
+	}
+
+	if created {
+		if err := fsys.chmodIfDiffers(p, perm); err != nil {
+			f.Close()
+			return nil, pathErr("chmod", name, err)
+		}
+	}
+
+	return f, nil
+}
+
+// Stat returns a FileInfo describing the named file.
+//
+// Parameters:
+//   - name: the name of the file
+func (fsys *FS) Stat(name string) (xfs.FileInfo, error) {
+	p, err := fsys.join("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := fsys.client.Stat(p)
+	if err != nil {
+		return nil, pathErr("stat", name, err)
+	}
+
+	return info, nil
+}
+
+// ReadDir reads the named directory and returns its entries sorted by name.
+//
+// Parameters:
+//   - name: the name of the directory
+func (fsys *FS) ReadDir(name string) ([]xfs.DirEntry, error) {
+	p, err := fsys.join("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := fsys.client.ReadDir(p)
+	if err != nil {
+		return nil, pathErr("readdir", name, err)
+	}
+
+	entries := make([]xfs.DirEntry, 0, len(infos))
+	for _, info := range infos {
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	slices.SortFunc(entries, func(a, b xfs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return entries, nil
+}
+
+// Mkdir creates the named directory with permissions perm.
+//
+// Parameters:
+//   - name: the name of the directory
+//   - perm: the directory permissions
+func (fsys *FS) Mkdir(name string, perm xfs.FileMode) error {
+	p, err := fsys.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+
+	if err := fsys.client.Mkdir(p); err != nil {
+		if _, statErr := fsys.client.Lstat(p); statErr == nil {
+			return &os.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+		}
+
+		return pathErr("mkdir", name, err)
+	}
+
+	return pathErr("chmod", name, fsys.chmodIfDiffers(p, perm))
+}
+
+// chmodIfDiffers sets the permissions of the remote path to perm unless the
+// server already created it with those permissions.
+func (fsys *FS) chmodIfDiffers(p string, perm xfs.FileMode) error {
+	info, err := fsys.client.Lstat(p)
+	if err != nil {
+		return err
+	}
+
+	if info.Mode().Perm() == perm.Perm() {
+		return nil
+	}
+
+	return fsys.client.Chmod(p, perm.Perm())
+}
+
+// MkdirAll creates the named directory along with any missing parents. The
+// directories it creates get permissions perm.
+//
+// Parameters:
+//   - name: the name of the directory
+//   - perm: the directory permissions
+func (fsys *FS) MkdirAll(name string, perm xfs.FileMode) error {
+	if _, err := fsys.join("mkdir", name); err != nil {
+		return err
+	}
+
+	if info, err := fsys.Stat(name); err == nil {
+		if info.IsDir() {
+			return nil
+		}
+
+		return &os.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+
+	if parent := path.Dir(name); parent != name {
+		if err := fsys.MkdirAll(parent, perm); err != nil {
+			return err
+		}
+	}
+
+	err := fsys.Mkdir(name, perm)
+	if errors.Is(err, fs.ErrExist) {
+		return nil
+	}
+
+	return err
+}
+
+// Remove removes the named file or empty directory.
+//
+// Parameters:
+//   - name: the name of the file
+func (fsys *FS) Remove(name string) error {
+	p, err := fsys.join("remove", name)
+	if err != nil {
+		return err
+	}
+
+	return pathErr("remove", name, fsys.client.Remove(p))
+}
+
+// RemoveAll removes the named path and any children it contains. Symbolic
+// links are removed, not followed, and a missing path is not an error.
+//
+// Parameters:
+//   - name: the path to remove
+func (fsys *FS) RemoveAll(name string) error {
+	p, err := fsys.join("removeall", name)
+	if err != nil {
+		return err
+	}
+
+	if name == "." {
+		return &os.PathError{Op: "removeall", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return pathErr("removeall", name, fsys.removeAll(p))
+}
+
+func (fsys *FS) removeAll(p string) error {
+	info, err := fsys.client.Lstat(p)
+	if err != nil {
+		if errors.Is(cause(err), fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	if info.IsDir() {
+		infos, err := fsys.client.ReadDir(p)
+		if err != nil {
+			return err
+		}
+
+		for _, child := range infos {
+			if err := fsys.removeAll(path.Join(p, child.Name())); err != nil {
+				return err
+			}
+		}
+
+		return fsys.client.RemoveDirectory(p)
+	}
+
+	return fsys.client.Remove(p)
+}
+
+// Rename renames oldname to newname, replacing newname if it exists and the
+// server supports the posix-rename extension.
+//
+// Parameters:
+//   - oldname: the current name
+//   - newname: the new name
+func (fsys *FS) Rename(oldname, newname string) error {
+	oldpath, err := fsys.join("rename", oldname)
+	if err != nil {
+		return err
+	}
+
+	newpath, err := fsys.join("rename", newname)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := fsys.client.HasExtension("posix-rename@openssh.com"); ok {
+		err = fsys.client.PosixRename(oldpath, newpath)
+	} else {
+		err = fsys.client.Rename(oldpath, newpath)
+	}
+
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: cause(err)}
+	}
+
+	return nil
+}
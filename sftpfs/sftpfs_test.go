@@ -0,0 +1,88 @@
+package sftpfs_test
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/jolt9dev/go-xfs/sftpfs"
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+)
+
+type pipeConn struct {
+	io.Reader
+	io.WriteCloser
+}
+
+// newTestClient connects a client to an in-memory SFTP server.
+func newTestClient(t *testing.T) *sftp.Client {
+	clientRead, serverWrite := io.Pipe()
+	serverRead, clientWrite := io.Pipe()
+
+	server := sftp.NewRequestServer(pipeConn{serverRead, serverWrite}, sftp.InMemHandler())
+	go server.Serve()
+
+	client, err := sftp.NewClientPipe(clientRead, clientWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		server.Close()
+		client.Close()
+	})
+
+	return client
+}
+
+func TestFS(t *testing.T) {
+	fsys := sftpfs.New(newTestClient(t), "/srv")
+	assert.NoError(t, fsys.MkdirAll(".", 0755))
+
+	assert.NoError(t, xfs.EnsureDirFS(fsys, "app/config", 0755))
+	assert.NoError(t, xfs.WriteTextFileFS(fsys, "app/config/app.yaml", "name: app", 0644))
+
+	data, err := fs.ReadFile(fsys, "app/config/app.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "name: app", string(data))
+
+	entries, err := fsys.ReadDir("app")
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.True(t, entries[0].IsDir())
+	}
+
+	assert.NoError(t, fsys.Rename("app/config/app.yaml", "app/app.yaml"))
+	_, err = fsys.Stat("app/config/app.yaml")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = fsys.Open("../etc/passwd")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+
+	assert.NoError(t, fsys.RemoveAll("app"))
+	assert.NoError(t, fsys.RemoveAll("app"))
+	_, err = fsys.Stat("app")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestCopyDirFS(t *testing.T) {
+	src := t.TempDir()
+	xfs.MkdirAllDefault(filepath.Join(src, "bin"))
+	os.WriteFile(filepath.Join(src, "bin", "run.sh"), []byte("#!/bin/sh"), 0755)
+	os.WriteFile(filepath.Join(src, "README"), []byte("readme"), 0644)
+
+	fsys := sftpfs.New(newTestClient(t), "/deploy")
+	assert.NoError(t, xfs.CopyDirFS(fsys, "release", os.DirFS(src), false))
+
+	data, err := fs.ReadFile(fsys, "release/bin/run.sh")
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh", string(data))
+
+	data, err = fs.ReadFile(fsys, "release/README")
+	assert.NoError(t, err)
+	assert.Equal(t, "readme", string(data))
+}
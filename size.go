@@ -0,0 +1,193 @@
+package xfs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Size units in the SI (powers of 1000) and IEC (powers of 1024) flavors.
+const (
+	KB int64 = 1000
+	MB       = KB * 1000
+	GB       = MB * 1000
+	TB       = GB * 1000
+	PB       = TB * 1000
+	EB       = PB * 1000
+
+	KiB int64 = 1 << 10
+	MiB       = KiB << 10
+	GiB       = MiB << 10
+	TiB       = GiB << 10
+	PiB       = TiB << 10
+	EiB       = PiB << 10
+)
+
+var sizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   KB,
+	"kb":  KB,
+	"m":   MB,
+	"mb":  MB,
+	"g":   GB,
+	"gb":  GB,
+	"t":   TB,
+	"tb":  TB,
+	"p":   PB,
+	"pb":  PB,
+	"e":   EB,
+	"eb":  EB,
+	"ki":  KiB,
+	"kib": KiB,
+	"mi":  MiB,
+	"mib": MiB,
+	"gi":  GiB,
+	"gib": GiB,
+	"ti":  TiB,
+	"tib": TiB,
+	"pi":  PiB,
+	"pib": PiB,
+	"ei":  EiB,
+	"eib": EiB,
+}
+
+var (
+	iecSuffixes = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siSuffixes  = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// FormatSize formats a size in bytes using IEC units (powers of 1024) with
+// one decimal place, e.g. "1.4 GiB". Sizes below 1 KiB are printed in bytes.
+//
+// Parameters:
+//   - size: the size in bytes
+func FormatSize(size int64) string {
+	return formatSize(size, 1024, iecSuffixes)
+}
+
+// FormatSizeSI formats a size in bytes using SI units (powers of 1000) with
+// one decimal place, e.g. "1.5 GB". Sizes below 1 kB are printed in bytes.
+//
+// Parameters:
+//   - size: the size in bytes
+func FormatSizeSI(size int64) string {
+	return formatSize(size, 1000, siSuffixes)
+}
+
+func formatSize(size int64, base float64, suffixes []string) string {
+	sign := ""
+	value := float64(size)
+	if size < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	if value < base {
+		return fmt.Sprintf("%s%d B", sign, int64(value))
+	}
+
+	i := 0
+	for value >= base && i < len(suffixes)-1 {
+		value /= base
+		i++
+	}
+
+	return fmt.Sprintf("%s%.1f %s", sign, value, suffixes[i])
+}
+
+// ParseSize parses a human-readable size such as "512MB", "1.5 GiB" or "100"
+// into a number of bytes. Units are case-insensitive; "KB", "MB", "GB" and the
+// single letter forms are SI units (powers of 1000) while "KiB", "MiB", "GiB"
+// are IEC units (powers of 1024). A number without a unit is in bytes. Sizes
+// that do not fit in an int64 are an error.
+//
+// Parameters:
+//   - s: the size to parse
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+
+	if i == 0 {
+		return 0, fmt.Errorf("xfs: invalid size %q", s)
+	}
+
+	unit, ok := sizeUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok {
+		return 0, fmt.Errorf("xfs: invalid size unit in %q", s)
+	}
+
+	if !strings.Contains(s[:i], ".") {
+		n, err := strconv.ParseInt(s[:i], 10, 64)
+		if err != nil || n > math.MaxInt64/unit {
+			return 0, fmt.Errorf("xfs: invalid size %q", s)
+		}
+
+		return n * unit, nil
+	}
+
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("xfs: invalid size %q", s)
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63, the first value that does
+	// not fit in an int64.
+	size := n * float64(unit)
+	if size >= float64(math.MaxInt64) {
+		return 0, fmt.Errorf("xfs: invalid size %q", s)
+	}
+
+	return int64(size), nil
+}
+
+// errIsDirectory is returned by FileSize for directories.
+var errIsDirectory = errors.New("is a directory")
+
+// FileSizeOptions controls FileSizeOpt.
+type FileSizeOptions struct {
+	// NoFollow returns the size of a symbolic link itself, the length of its
+	// target path on most systems, instead of the size of the file it points
+	// to.
+	NoFollow bool
+}
+
+// FileSize returns the size of the named file in bytes. If the file is a
+// symbolic link, the size of the link's target is returned. Directories,
+// whose reported size is meaningless, yield an error.
+//
+// Parameters:
+//   - filename: the name of the file
+func FileSize(filename string) (int64, error) {
+	return FileSizeOpt(filename, nil)
+}
+
+// FileSizeOpt returns the size of the named file in bytes like FileSize,
+// optionally without following a symbolic link.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - opts: the size options, nil uses the defaults
+func FileSizeOpt(filename string, opts *FileSizeOptions) (int64, error) {
+	stat := os.Stat
+	if opts != nil && opts.NoFollow {
+		stat = os.Lstat
+	}
+
+	info, err := stat(filename)
+	if err != nil {
+		return 0, err
+	}
+
+	if info.IsDir() {
+		return 0, &os.PathError{Op: "size", Path: filename, Err: errIsDirectory}
+	}
+
+	return info.Size(), nil
+}
@@ -0,0 +1,86 @@
+package xfs_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "0 B", xfs.FormatSize(0))
+	assert.Equal(t, "1023 B", xfs.FormatSize(1023))
+	assert.Equal(t, "1.0 KiB", xfs.FormatSize(1024))
+	assert.Equal(t, "1.5 MiB", xfs.FormatSize(3*xfs.MiB/2))
+	assert.Equal(t, "1.4 GiB", xfs.FormatSize(1503238554))
+	assert.Equal(t, "-2.0 KiB", xfs.FormatSize(-2048))
+}
+
+func TestFormatSizeSI(t *testing.T) {
+	assert.Equal(t, "999 B", xfs.FormatSizeSI(999))
+	assert.Equal(t, "1.0 kB", xfs.FormatSizeSI(1000))
+	assert.Equal(t, "1.5 GB", xfs.FormatSizeSI(1500*xfs.MB))
+}
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]int64{
+		"100":                 100,
+		"100B":                100,
+		"512MB":               512 * xfs.MB,
+		"512mb":               512 * xfs.MB,
+		"1.5 GiB":             3 * xfs.GiB / 2,
+		"10K":                 10 * xfs.KB,
+		"4KiB":                4 * xfs.KiB,
+		"2Ti":                 2 * xfs.TiB,
+		"9223372036854775807": math.MaxInt64,
+		"7EiB":                7 * xfs.EiB,
+	}
+
+	for input, expected := range cases {
+		size, err := xfs.ParseSize(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, size, input)
+	}
+
+	_, err := xfs.ParseSize("MB")
+	assert.Error(t, err)
+
+	_, err = xfs.ParseSize("10 parsecs")
+	assert.Error(t, err)
+
+	for _, input := range []string{"100EiB", "8EiB", "9223372036854775808", "9.3EB"} {
+		_, err = xfs.ParseSize(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestFileSize(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, make([]byte, 42), 0644)
+
+	size, err := xfs.FileSize(file)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), size)
+
+	_, err = xfs.FileSize(dir)
+	assert.Error(t, err)
+
+	_, err = xfs.FileSize(filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	if xfs.CanSymlink() {
+		link := filepath.Join(dir, "link")
+		os.Symlink("file", link)
+
+		size, _ = xfs.FileSize(link)
+		assert.Equal(t, int64(42), size)
+
+		size, err = xfs.FileSizeOpt(link, &xfs.FileSizeOptions{NoFollow: true})
+		assert.NoError(t, err)
+		assert.NotEqual(t, int64(42), size)
+	}
+}
@@ -0,0 +1,218 @@
+package xfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ManifestEntry describes a single file system entry captured by Snapshot.
+type ManifestEntry struct {
+	// Path is the slash separated path relative to the snapshot root.
+	Path string `json:"path"`
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+	// Mode is the file mode, including the type bits.
+	Mode FileMode `json:"mode"`
+	// ModTime is the modification time of the entry.
+	ModTime time.Time `json:"mtime"`
+	// Hash is the hex encoded SHA-256 digest of a regular file's content.
+	Hash string `json:"hash,omitempty"`
+	// Target is the target of a symbolic link.
+	Target string `json:"target,omitempty"`
+}
+
+// Manifest is a serializable snapshot of a directory tree.
+type Manifest struct {
+	Entries []ManifestEntry `json:"entries"`
+}
+
+// SnapshotDiff reports the differences found by VerifySnapshot. The paths
+// are slash separated and relative to the verified root.
+type SnapshotDiff struct {
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+// Changed reports whether any difference was found.
+func (d *SnapshotDiff) Changed() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Modified) > 0
+}
+
+// HashFile returns the hex encoded SHA-256 digest of the named file's content.
+//
+// Parameters:
+//   - filename: the name of the file
+func HashFile(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Snapshot walks the tree rooted at root and records the path, size, mode,
+// modification time and content hash of every entry below it. Symbolic links
+// are recorded with their target and are not followed.
+//
+// Parameters:
+//   - root: the directory to snapshot
+func Snapshot(root string) (*Manifest, error) {
+	return snapshot(root, HashFile)
+}
+
+func snapshot(root string, hash func(string) (string, error)) (*Manifest, error) {
+	m := &Manifest{}
+	err := filepath.WalkDir(root, func(path string, d DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		entry, err := manifestEntry(path, filepath.ToSlash(rel), info, hash)
+		if err != nil {
+			return err
+		}
+
+		m.Entries = append(m.Entries, entry)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// manifestEntry describes the entry at path. Regular files are hashed with
+// hash unless it is nil.
+func manifestEntry(path, rel string, info FileInfo, hash func(string) (string, error)) (ManifestEntry, error) {
+	entry := ManifestEntry{
+		Path:    rel,
+		Mode:    info.Mode(),
+		ModTime: info.ModTime(),
+	}
+
+	var err error
+	switch {
+	case info.Mode().IsRegular():
+		entry.Size = info.Size()
+		if hash != nil {
+			entry.Hash, err = hash(path)
+		}
+	case info.Mode()&os.ModeSymlink != 0:
+		entry.Target, err = os.Readlink(path)
+	}
+
+	return entry, err
+}
+
+// VerifySnapshot compares the tree rooted at root against a manifest produced
+// by Snapshot and reports entries that were added, removed or modified. An
+// entry is modified when its type, permissions, size, content hash or link
+// target differ; a changed modification time alone is not reported.
+//
+// Parameters:
+//   - root: the directory to verify
+//   - manifest: the manifest to verify against
+func VerifySnapshot(root string, manifest *Manifest) (*SnapshotDiff, error) {
+	current, err := Snapshot(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest.Diff(current), nil
+}
+
+// Diff compares the manifest with a newer one and reports the entries that
+// were added, removed or modified in other.
+//
+// Parameters:
+//   - other: the newer manifest
+func (m *Manifest) Diff(other *Manifest) *SnapshotDiff {
+	expected := make(map[string]ManifestEntry, len(m.Entries))
+	for _, e := range m.Entries {
+		expected[e.Path] = e
+	}
+
+	diff := &SnapshotDiff{}
+	for _, e := range other.Entries {
+		old, ok := expected[e.Path]
+		if !ok {
+			diff.Added = append(diff.Added, e.Path)
+			continue
+		}
+
+		delete(expected, e.Path)
+		if old.Mode != e.Mode || old.Size != e.Size || old.Hash != e.Hash || old.Target != e.Target {
+			diff.Modified = append(diff.Modified, e.Path)
+		}
+	}
+
+	for path := range expected {
+		diff.Removed = append(diff.Removed, path)
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Modified)
+	return diff
+}
+
+// Save writes the manifest to the named file as JSON.
+//
+// Parameters:
+//   - filename: the name of the file
+func (m *Manifest) Save(filename string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0644)
+}
+
+// LoadManifest reads a manifest previously written by Manifest.Save.
+//
+// Parameters:
+//   - filename: the name of the file
+func LoadManifest(filename string) (*Manifest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,65 @@
+package xfs_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	xfs.WriteTextFile(path, "hello", 0644)
+
+	sum, err := xfs.HashFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", sum)
+}
+
+func TestSnapshot(t *testing.T) {
+	root := t.TempDir()
+	xfs.MkdirAllDefault(filepath.Join(root, "bin"))
+	xfs.WriteTextFile(filepath.Join(root, "bin", "app"), "binary", 0755)
+	xfs.WriteTextFile(filepath.Join(root, "config.json"), "{}", 0644)
+
+	m, err := xfs.Snapshot(root)
+	assert.NoError(t, err)
+	assert.Len(t, m.Entries, 3)
+	assert.Equal(t, "bin", m.Entries[0].Path)
+	assert.Equal(t, "bin/app", m.Entries[1].Path)
+	assert.Equal(t, int64(6), m.Entries[1].Size)
+	assert.NotEmpty(t, m.Entries[1].Hash)
+
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	assert.NoError(t, m.Save(manifestPath))
+
+	loaded, err := xfs.LoadManifest(manifestPath)
+	assert.NoError(t, err)
+	assert.Equal(t, len(m.Entries), len(loaded.Entries))
+	assert.Equal(t, m.Entries[1].Hash, loaded.Entries[1].Hash)
+}
+
+func TestVerifySnapshot(t *testing.T) {
+	root := t.TempDir()
+	xfs.WriteTextFile(filepath.Join(root, "a.txt"), "a", 0644)
+	xfs.WriteTextFile(filepath.Join(root, "b.txt"), "b", 0644)
+
+	m, err := xfs.Snapshot(root)
+	assert.NoError(t, err)
+
+	diff, err := xfs.VerifySnapshot(root, m)
+	assert.NoError(t, err)
+	assert.False(t, diff.Changed())
+
+	xfs.WriteTextFile(filepath.Join(root, "a.txt"), "tampered", 0644)
+	xfs.Remove(filepath.Join(root, "b.txt"))
+	xfs.WriteTextFile(filepath.Join(root, "c.txt"), "c", 0644)
+
+	diff, err = xfs.VerifySnapshot(root, m)
+	assert.NoError(t, err)
+	assert.True(t, diff.Changed())
+	assert.Equal(t, []string{"c.txt"}, diff.Added)
+	assert.Equal(t, []string{"b.txt"}, diff.Removed)
+	assert.Equal(t, []string{"a.txt"}, diff.Modified)
+}
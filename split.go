@@ -0,0 +1,125 @@
+package xfs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// errBadChunkSize is returned by SplitFile for a chunk size below one byte.
+var errBadChunkSize = errors.New("chunk size must be positive")
+
+// errBadPartPattern is returned when a part pattern does not produce distinct
+// names for distinct part numbers.
+var errBadPartPattern = errors.New("part pattern must contain one integer verb")
+
+// SplitFile splits the source file into parts of at most chunkSize bytes and
+// returns their names in order. The part names are produced by formatting
+// dstPattern with the part number, starting at 0, so "archive.tar.%03d"
+// yields archive.tar.000, archive.tar.001 and so on. An empty source yields a
+// single empty part.
+//
+// Parts left over from an earlier, longer split of the same pattern are
+// removed so that JoinFiles does not pick them up. If an error occurs, the
+// parts written so far are removed.
+//
+// Parameters:
+//   - src: the name of the source file
+//   - chunkSize: the maximum size of a part in bytes
+//   - dstPattern: the fmt pattern for the part names
+func SplitFile(src string, chunkSize int64, dstPattern string) ([]string, error) {
+	if chunkSize <= 0 {
+		return nil, &os.PathError{Op: "split", Path: src, Err: errBadChunkSize}
+	}
+
+	if err := checkPartPattern(dstPattern); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []string
+	for i := 0; i == 0 || int64(i)*chunkSize < info.Size(); i++ {
+		name := fmt.Sprintf(dstPattern, i)
+		parts = append(parts, name)
+		if err := writePart(name, f, chunkSize, info.Mode().Perm()); err != nil {
+			for _, part := range parts {
+				os.Remove(part)
+			}
+
+			return nil, err
+		}
+	}
+
+	for i := len(parts); ; i++ {
+		if err := os.Remove(fmt.Sprintf(dstPattern, i)); err != nil {
+			break
+		}
+	}
+
+	return parts, nil
+}
+
+func writePart(name string, r io.Reader, size int64, perm FileMode) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.CopyN(f, r, size); err != nil && err != io.EOF {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// JoinFiles reassembles the parts written by SplitFile with the same pattern
+// into the destination file. Parts are read in order from number 0 until the
+// first missing one; it is an error if there is no part 0. The destination is
+// replaced atomically.
+//
+// Parameters:
+//   - srcPattern: the fmt pattern for the part names
+//   - dst: the name of the destination file
+func JoinFiles(srcPattern string, dst string) error {
+	if err := checkPartPattern(srcPattern); err != nil {
+		return err
+	}
+
+	var parts []string
+	for i := 0; ; i++ {
+		name := fmt.Sprintf(srcPattern, i)
+		if _, err := os.Stat(name); err != nil {
+			if i == 0 || !os.IsNotExist(err) {
+				return err
+			}
+
+			break
+		}
+
+		parts = append(parts, name)
+	}
+
+	return ConcatFiles(dst, parts...)
+}
+
+func checkPartPattern(pattern string) error {
+	first := fmt.Sprintf(pattern, 0)
+	if strings.Contains(first, "%!") || first == fmt.Sprintf(pattern, 1) {
+		return &os.PathError{Op: "split", Path: pattern, Err: errBadPartPattern}
+	}
+
+	return nil
+}
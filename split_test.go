@@ -0,0 +1,47 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	pattern := filepath.Join(dir, "src.%03d")
+	os.WriteFile(src, []byte("0123456789"), 0644)
+
+	parts, err := xfs.SplitFile(src, 4, pattern)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "src.000"),
+		filepath.Join(dir, "src.001"),
+		filepath.Join(dir, "src.002"),
+	}, parts)
+
+	data, _ := os.ReadFile(parts[2])
+	assert.Equal(t, "89", string(data))
+
+	dst := filepath.Join(dir, "dst")
+	assert.NoError(t, xfs.JoinFiles(pattern, dst))
+	data, _ = os.ReadFile(dst)
+	assert.Equal(t, "0123456789", string(data))
+
+	parts, err = xfs.SplitFile(src, 5, pattern)
+	assert.NoError(t, err)
+	assert.Len(t, parts, 2)
+	assert.NoFileExists(t, filepath.Join(dir, "src.002"))
+
+	_, err = xfs.SplitFile(src, 0, pattern)
+	assert.Error(t, err)
+
+	_, err = xfs.SplitFile(src, 4, filepath.Join(dir, "part"))
+	assert.Error(t, err)
+
+	err = xfs.JoinFiles(filepath.Join(dir, "missing.%d"), dst)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
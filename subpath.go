@@ -0,0 +1,191 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotSubPath is returned by RelativeTo when the target is not inside base.
+var ErrNotSubPath = errors.New("xfs: path is not inside the base directory")
+
+// IsSubPath reports whether child is parent itself or a path inside parent.
+// Both paths are made absolute and cleaned, and symbolic links in the
+// existing part of each path are resolved, so "/srv/app/../etc" and a link
+// pointing outside parent are correctly reported as outside. The comparison
+// is done on whole path components, so "/srv/app2" is not inside "/srv/app",
+// and is case-insensitive on Windows and macOS.
+//
+// Parameters:
+//   - parent: the containing directory
+//   - child: the path to check
+func IsSubPath(parent, child string) bool {
+	_, err := RelativeTo(parent, child)
+	return err == nil
+}
+
+// RelativeTo returns the path of target relative to base, after resolving
+// both paths like IsSubPath does. If target is not base or inside it, the
+// error wraps ErrNotSubPath; use filepath.Rel to build paths containing "..".
+//
+// Parameters:
+//   - base: the base directory
+//   - target: the path to make relative
+func RelativeTo(base, target string) (string, error) {
+	resolvedBase, err := resolvePath(base)
+	if err != nil {
+		return "", err
+	}
+
+	resolvedTarget, err := resolvePath(target)
+	if err != nil {
+		return "", err
+	}
+
+	rel, ok := relWithin(resolvedBase, resolvedTarget)
+	if !ok {
+		return "", &os.PathError{Op: "rel", Path: target, Err: ErrNotSubPath}
+	}
+
+	return rel, nil
+}
+
+// MustRel is like RelativeTo but panics if target is not inside base. It is
+// intended for paths that are known to be related, such as those produced by
+// walking base.
+//
+// Parameters:
+//   - base: the base directory
+//   - target: the path to make relative
+func MustRel(base, target string) string {
+	rel, err := RelativeTo(base, target)
+	if err != nil {
+		panic(err)
+	}
+
+	return rel
+}
+
+// relWithin returns the relative path from base to target if target is base
+// or below it. Both paths must be absolute and clean. Components are compared
+// one by one so that case folding, which may change the length of a string,
+// never shifts the returned path.
+func relWithin(base, target string) (string, bool) {
+	sep := string(filepath.Separator)
+	baseElems := strings.Split(strings.TrimSuffix(base, sep), sep)
+	targetElems := strings.Split(strings.TrimSuffix(target, sep), sep)
+	if len(targetElems) < len(baseElems) {
+		return "", false
+	}
+
+	fold := caseInsensitiveOS()
+	for i, elem := range baseElems {
+		if elem != targetElems[i] && !(fold && strings.EqualFold(elem, targetElems[i])) {
+			return "", false
+		}
+	}
+
+	if len(targetElems) == len(baseElems) {
+		return ".", true
+	}
+
+	return strings.Join(targetElems[len(baseElems):], sep), true
+}
+
+// maxSymlinks bounds the number of symbolic links resolvePath follows.
+const maxSymlinks = 255
+
+// resolvePath returns the absolute, clean form of path with symbolic links
+// resolved in the longest existing prefix of the path. Components are
+// resolved one at a time, so ".." applies to the target of a preceding link
+// like it does for the kernel, not to the link's own name.
+func resolvePath(path string) (string, error) {
+	sep := string(filepath.Separator)
+	path = filepath.FromSlash(path)
+	if !filepath.IsAbs(path) {
+		if filepath.VolumeName(path) != "" {
+			// A drive relative path such as "C:foo" on Windows.
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return "", err
+			}
+
+			path = abs
+		} else {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return "", err
+			}
+
+			if strings.HasPrefix(path, sep) {
+				path = filepath.VolumeName(cwd) + path
+			} else {
+				path = cwd + sep + path
+			}
+		}
+	}
+
+	vol := filepath.VolumeName(path)
+	resolved := vol + sep
+	parts := strings.Split(path[len(vol):], sep)
+	missing := false
+	links := 0
+	for len(parts) > 0 {
+		part := parts[0]
+		parts = parts[1:]
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, part)
+		if missing {
+			resolved = next
+			continue
+		}
+
+		info, err := os.Lstat(next)
+		if errors.Is(err, os.ErrNotExist) {
+			missing = true
+			resolved = next
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", &os.PathError{Op: "resolve", Path: path, Err: errSymlinkLoop}
+		}
+
+		target, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+
+		target = filepath.FromSlash(target)
+		switch {
+		case filepath.IsAbs(target):
+			vol := filepath.VolumeName(target)
+			resolved = vol + sep
+			target = target[len(vol):]
+		case strings.HasPrefix(target, sep):
+			resolved = filepath.VolumeName(resolved) + sep
+		}
+
+		parts = append(strings.Split(target, sep), parts...)
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,80 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSubPath(t *testing.T) {
+	root := t.TempDir()
+	app := filepath.Join(root, "app")
+	xfs.MkdirAllDefault(filepath.Join(app, "data"))
+	xfs.MkdirAllDefault(filepath.Join(root, "app2"))
+	xfs.MkdirAllDefault(filepath.Join(root, "etc"))
+
+	assert.True(t, xfs.IsSubPath(app, app))
+	assert.True(t, xfs.IsSubPath(app, filepath.Join(app, "data")))
+	assert.True(t, xfs.IsSubPath(app+string(filepath.Separator), filepath.Join(app, "data")))
+	assert.True(t, xfs.IsSubPath(app, filepath.Join(app, "not", "created", "yet")))
+	assert.False(t, xfs.IsSubPath(app, filepath.Join(root, "app2")))
+	assert.False(t, xfs.IsSubPath(app, filepath.Join(app, "..", "etc")))
+
+	if runtime.GOOS != "windows" {
+		escape := filepath.Join(app, "escape")
+		os.Symlink(filepath.Join(root, "etc"), escape)
+		assert.False(t, xfs.IsSubPath(app, filepath.Join(escape, "passwd")))
+
+		// The kernel applies ".." to the target of the link, root/etc.
+		assert.False(t, xfs.IsSubPath(app, escape+string(filepath.Separator)+".."+string(filepath.Separator)+"x"))
+		assert.True(t, xfs.IsSubPath(root, escape+string(filepath.Separator)+".."+string(filepath.Separator)+"x"))
+
+		inner := filepath.Join(app, "inner")
+		os.Symlink("data", inner)
+		assert.True(t, xfs.IsSubPath(app, inner+string(filepath.Separator)+".."+string(filepath.Separator)+"x"))
+	}
+}
+
+func TestRelativeTo(t *testing.T) {
+	root := t.TempDir()
+
+	rel, err := xfs.RelativeTo(root, filepath.Join(root, "a", "b"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("a", "b"), rel)
+
+	rel, err = xfs.RelativeTo(root, root)
+	assert.NoError(t, err)
+	assert.Equal(t, ".", rel)
+
+	_, err = xfs.RelativeTo(filepath.Join(root, "a"), root)
+	assert.ErrorIs(t, err, xfs.ErrNotSubPath)
+}
+
+func TestRelativeToNonASCII(t *testing.T) {
+	// Lower-casing "İ" or the Kelvin sign changes their length in bytes.
+	base := filepath.Join(t.TempDir(), "İstanbul \u212a")
+	xfs.MkdirAllDefault(base)
+
+	rel, err := xfs.RelativeTo(base, filepath.Join(base, "data", "file"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("data", "file"), rel)
+
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		rel, err = xfs.RelativeTo(base, filepath.Join(strings.ToUpper(base), "Data"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Data", rel)
+	}
+}
+
+func TestMustRel(t *testing.T) {
+	root := t.TempDir()
+	assert.Equal(t, "file", xfs.MustRel(root, filepath.Join(root, "file")))
+	assert.Panics(t, func() {
+		xfs.MustRel(filepath.Join(root, "a"), root)
+	})
+}
@@ -0,0 +1,65 @@
+package xfs
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SymlinkOptions controls SymlinkOpt.
+type SymlinkOptions struct {
+	// Fallback makes SymlinkOpt fall back when the process is not allowed to
+	// create symbolic links, as on Windows without developer mode or
+	// administrator rights: a directory target gets a junction and a file
+	// target is copied. The target must exist for the fallback to apply.
+	Fallback bool
+}
+
+// SymlinkOpt creates newname as a symbolic link to oldname like Symlink,
+// optionally falling back to a junction or a copy where symbolic links are
+// not permitted. A relative oldname is interpreted relative to the directory
+// of newname, as for the link itself.
+//
+// Parameters:
+//   - oldname: the target of the link
+//   - newname: the name of the link
+//   - opts: the symlink options, nil uses the defaults
+func SymlinkOpt(oldname, newname string, opts *SymlinkOptions) error {
+	err := os.Symlink(oldname, newname)
+	if err == nil || opts == nil || !opts.Fallback || !symlinkNotPermitted(err) {
+		return err
+	}
+
+	target := oldname
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(newname), oldname)
+	}
+
+	info, statErr := os.Stat(target)
+	if statErr != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return createJunction(target, newname)
+	}
+
+	return copyFile(target, newname, info, false)
+}
+
+var canSymlink = sync.OnceValue(func() bool {
+	dir, err := os.MkdirTemp("", "xfs-symlink-*")
+	if err != nil {
+		return false
+	}
+	defer os.RemoveAll(dir)
+
+	return os.Symlink("target", filepath.Join(dir, "link")) == nil
+})
+
+// CanSymlink reports whether the process may create symbolic links, by
+// creating one in the temporary directory. The result is computed once and
+// cached.
+func CanSymlink() bool {
+	return canSymlink()
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package xfs
+
+import (
+	"os"
+)
+
+// symlinkNotPermitted reports whether a symbolic link failed for lack of
+// privileges, which only happens on Windows.
+func symlinkNotPermitted(err error) bool {
+	return false
+}
+
+func createJunction(target, link string) error {
+	return &os.LinkError{Op: "junction", Old: target, New: link, Err: &unsupportedError{os.ErrInvalid}}
+}
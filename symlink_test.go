@@ -0,0 +1,27 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymlinkOpt(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "target"), 0755)
+	os.WriteFile(filepath.Join(dir, "target", "file"), []byte("data"), 0644)
+
+	link := filepath.Join(dir, "link")
+	assert.NoError(t, xfs.SymlinkOpt("target", link, &xfs.SymlinkOptions{Fallback: true}))
+
+	data, err := os.ReadFile(filepath.Join(link, "file"))
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	if xfs.CanSymlink() {
+		assert.True(t, xfs.IsSymlink(link))
+	}
+}
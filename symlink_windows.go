@@ -0,0 +1,85 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"unicode/utf16"
+
+	"golang.org/x/sys/windows"
+)
+
+// symlinkNotPermitted reports whether a symbolic link failed because the
+// process lacks SeCreateSymbolicLinkPrivilege.
+func symlinkNotPermitted(err error) bool {
+	return errors.Is(err, windows.ERROR_PRIVILEGE_NOT_HELD)
+}
+
+// createJunction creates link as an NTFS junction to the directory target.
+// Junctions need no privileges but only work for local, absolute targets.
+func createJunction(target, link string) error {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return &os.LinkError{Op: "junction", Old: target, New: link, Err: err}
+	}
+
+	if err := os.Mkdir(link, 0777); err != nil {
+		return err
+	}
+
+	if err := setMountPoint(link, abs); err != nil {
+		os.Remove(link)
+		return &os.LinkError{Op: "junction", Old: target, New: link, Err: err}
+	}
+
+	return nil
+}
+
+// setMountPoint turns the empty directory dir into a mount point reparse
+// point for target.
+func setMountPoint(dir, target string) error {
+	name, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return err
+	}
+
+	h, err := windows.CreateFile(name, windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING,
+		windows.FILE_FLAG_OPEN_REPARSE_POINT|windows.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(h)
+
+	subst := utf16.Encode([]rune(`\??\` + target))
+	printName := utf16.Encode([]rune(target))
+
+	// REPARSE_DATA_BUFFER with a MountPointReparseBuffer: both names are
+	// stored NUL terminated, their lengths exclude the terminator.
+	pathLen := (len(subst) + 1 + len(printName) + 1) * 2
+	buf := make([]byte, 16+pathLen)
+	binary.LittleEndian.PutUint32(buf[0:], windows.IO_REPARSE_TAG_MOUNT_POINT)
+	binary.LittleEndian.PutUint16(buf[4:], uint16(8+pathLen))
+	binary.LittleEndian.PutUint16(buf[8:], 0)
+	binary.LittleEndian.PutUint16(buf[10:], uint16(len(subst)*2))
+	binary.LittleEndian.PutUint16(buf[12:], uint16((len(subst)+1)*2))
+	binary.LittleEndian.PutUint16(buf[14:], uint16(len(printName)*2))
+
+	pos := 16
+	for _, c := range subst {
+		binary.LittleEndian.PutUint16(buf[pos:], c)
+		pos += 2
+	}
+
+	pos += 2
+	for _, c := range printName {
+		binary.LittleEndian.PutUint16(buf[pos:], c)
+		pos += 2
+	}
+
+	var returned uint32
+	return windows.DeviceIoControl(h, windows.FSCTL_SET_REPARSE_POINT, &buf[0], uint32(len(buf)), nil, 0, &returned, nil)
+}
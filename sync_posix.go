@@ -0,0 +1,25 @@
+//go:build !windows
+
+package xfs
+
+import (
+	"os"
+)
+
+// SyncDir flushes the directory entry metadata of the named directory to
+// stable storage. Call it after creating, renaming or removing files in the
+// directory when the change must survive a power loss.
+//
+// On Windows directories cannot be flushed and SyncDir is a no-op.
+//
+// Parameters:
+//   - dir: the name of the directory
+func SyncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return f.Sync()
+}
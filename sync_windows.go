@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"os"
+)
+
+// SyncDir flushes the directory entry metadata of the named directory to
+// stable storage. Call it after creating, renaming or removing files in the
+// directory when the change must survive a power loss.
+//
+// On Windows directories cannot be flushed and SyncDir only verifies that the
+// directory exists.
+//
+// Parameters:
+//   - dir: the name of the directory
+func SyncDir(dir string) error {
+	_, err := os.Stat(dir)
+	return err
+}
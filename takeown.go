@@ -0,0 +1,83 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// errEntryReplaced is reported by TakeOwnership when an entry changed
+// between the walk and restoring its setuid and setgid bits.
+var errEntryReplaced = errors.New("entry was replaced during the walk")
+
+// OwnershipOptions controls TakeOwnership.
+type OwnershipOptions struct {
+	// PreserveSetid restores the setuid and setgid bits that changing the
+	// owner clears on most systems. Without it, such files lose the bits,
+	// which is the safe choice when the new owner is not trusted.
+	PreserveSetid bool
+}
+
+// OwnershipFailure is an entry TakeOwnership could not change, with the
+// error.
+type OwnershipFailure struct {
+	Path string
+	Err  error
+}
+
+// TakeOwnership changes the numeric owner and group of root and every entry
+// below it, like chown -R, and continues past entries it cannot change,
+// which it reports. A uid or gid of -1 keeps that value. The returned error
+// is set only if the walk itself fails, e.g. because root does not exist.
+//
+// Symbolic links are changed themselves and never followed, so link targets
+// outside the tree are left alone. Setuid and setgid bits are restored
+// through a descriptor of the walked entry, never through a link that
+// replaced it.
+//
+// On Windows and Plan 9 every entry fails, as for Chown.
+//
+// Parameters:
+//   - root: the root of the tree
+//   - uid: the numeric user id of the new owner
+//   - gid: the numeric group id
+//   - opts: the ownership options, nil uses the defaults
+func TakeOwnership(root string, uid, gid int, opts *OwnershipOptions) ([]OwnershipFailure, error) {
+	if opts == nil {
+		opts = &OwnershipOptions{}
+	}
+
+	if _, err := os.Lstat(root); err != nil {
+		return nil, err
+	}
+
+	var failures []OwnershipFailure
+	err := filepath.WalkDir(root, func(path string, d DirEntry, err error) error {
+		if err != nil {
+			failures = append(failures, OwnershipFailure{Path: path, Err: err})
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			failures = append(failures, OwnershipFailure{Path: path, Err: err})
+			return nil
+		}
+
+		if err := os.Lchown(path, uid, gid); err != nil {
+			failures = append(failures, OwnershipFailure{Path: path, Err: err})
+			return nil
+		}
+
+		setid := info.Mode() & (os.ModeSetuid | os.ModeSetgid)
+		if opts.PreserveSetid && setid != 0 && info.Mode()&os.ModeSymlink == 0 {
+			if err := restoreSetid(path, info, info.Mode().Perm()|setid|info.Mode()&os.ModeSticky); err != nil {
+				failures = append(failures, OwnershipFailure{Path: path, Err: err})
+			}
+		}
+
+		return nil
+	})
+
+	return failures, err
+}
@@ -0,0 +1,22 @@
+//go:build !unix
+
+package xfs
+
+import (
+	"os"
+)
+
+// restoreSetid sets mode on the entry at path if it is still the file
+// described by info and not a symbolic link.
+func restoreSetid(path string, info FileInfo, mode FileMode) error {
+	current, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	if !os.SameFile(info, current) || current.Mode()&os.ModeSymlink != 0 {
+		return &os.PathError{Op: "chmod", Path: path, Err: errEntryReplaced}
+	}
+
+	return os.Chmod(path, mode)
+}
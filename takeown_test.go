@@ -0,0 +1,34 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTakeOwnership(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("chown is not supported on windows")
+	}
+
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "a", "b"), 0755)
+	tool := filepath.Join(root, "a", "tool")
+	os.WriteFile(tool, nil, 0755)
+	os.Chmod(tool, 0755|os.ModeSetgid)
+	os.Symlink(os.TempDir(), filepath.Join(root, "a", "outside"))
+
+	failures, err := xfs.TakeOwnership(root, os.Getuid(), -1, &xfs.OwnershipOptions{PreserveSetid: true})
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+
+	info, _ := os.Stat(tool)
+	assert.NotZero(t, info.Mode()&os.ModeSetgid)
+
+	_, err = xfs.TakeOwnership(filepath.Join(root, "missing"), os.Getuid(), -1, nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package xfs
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// restoreSetid sets mode on the entry at path, which must still be the file
+// described by info. The entry is opened without following symbolic links
+// and changed through the descriptor, so it cannot be swapped in between.
+func restoreSetid(path string, info FileInfo, mode FileMode) error {
+	f, err := os.OpenFile(path, os.O_RDONLY|unix.O_NOFOLLOW|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	current, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if !os.SameFile(info, current) {
+		return &os.PathError{Op: "chmod", Path: path, Err: errEntryReplaced}
+	}
+
+	return f.Chmod(mode)
+}
@@ -0,0 +1,160 @@
+package xfs
+
+import (
+	"os"
+)
+
+// TeeWriter fans a stream out to several files. Each destination is written
+// through its own temporary file, and the temporary files are renamed into
+// place only after every write and close succeeded, so a failed write leaves
+// all destinations untouched.
+//
+// Like AtomicWriter, a TeeWriter must be finished with Commit or Abort, and
+// Close aborts an uncommitted writer.
+type TeeWriter struct {
+	writers []*AtomicWriter
+	closed  bool
+}
+
+// NewTeeWriter starts an atomic write of each of the named files. The
+// temporary files are created with permissions perm (before umask).
+//
+// Parameters:
+//   - paths: the names of the destination files
+//   - perm: the file permissions
+//   - opts: the write options, nil uses the defaults
+func NewTeeWriter(paths []string, perm FileMode, opts *AtomicOptions) (*TeeWriter, error) {
+	t := &TeeWriter{}
+	for _, path := range paths {
+		w, err := NewAtomicWriter(path, perm, opts)
+		if err != nil {
+			t.Abort()
+			return nil, err
+		}
+
+		t.writers = append(t.writers, w)
+	}
+
+	return t, nil
+}
+
+// Write writes p to every temporary file.
+//
+// Parameters:
+//   - p: the data to write
+func (t *TeeWriter) Write(p []byte) (int, error) {
+	if t.closed {
+		return 0, &os.PathError{Op: "write", Path: t.name(), Err: errWriterClosed}
+	}
+
+	for _, w := range t.writers {
+		if _, err := w.Write(p); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// WriteString writes s to every temporary file.
+//
+// Parameters:
+//   - s: the text to write
+func (t *TeeWriter) WriteString(s string) (int, error) {
+	return t.Write([]byte(s))
+}
+
+// Commit closes all temporary files and, if that succeeded, renames each of
+// them over its destination. If closing any file fails, all temporary files
+// are removed and no destination changes. A failing rename, which is rare
+// once the files are closed, stops the commit and leaves the destinations
+// renamed before it updated.
+func (t *TeeWriter) Commit() error {
+	if t.closed {
+		return &os.PathError{Op: "commit", Path: t.name(), Err: errWriterClosed}
+	}
+
+	t.closed = true
+	for _, w := range t.writers {
+		w.closed = true
+	}
+
+	for i, w := range t.writers {
+		if err := w.finish(); err != nil {
+			for _, other := range t.writers[i+1:] {
+				other.f.Close()
+			}
+
+			t.removeTemps(t.writers)
+			return err
+		}
+	}
+
+	for i, w := range t.writers {
+		if err := w.publish(); err != nil {
+			t.removeTemps(t.writers[i:])
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Abort discards all temporary files and leaves the destinations untouched.
+// Aborting a committed or aborted writer is a no-op.
+func (t *TeeWriter) Abort() error {
+	if t.closed {
+		return nil
+	}
+
+	t.closed = true
+	var first error
+	for _, w := range t.writers {
+		if err := w.Abort(); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// Close aborts the write unless it was committed.
+func (t *TeeWriter) Close() error {
+	return t.Abort()
+}
+
+func (t *TeeWriter) name() string {
+	if len(t.writers) == 0 {
+		return ""
+	}
+
+	return t.writers[0].path
+}
+
+func (t *TeeWriter) removeTemps(writers []*AtomicWriter) {
+	for _, w := range writers {
+		os.Remove(w.f.Name())
+	}
+}
+
+// WriteFileMulti writes data to each of the named files atomically. The
+// destinations are replaced only if data was written to all of them, see
+// TeeWriter.
+//
+// Parameters:
+//   - data: the data to write
+//   - perm: the file permissions
+//   - paths: the names of the destination files
+func WriteFileMulti(data []byte, perm FileMode, paths ...string) error {
+	t, err := NewTeeWriter(paths, perm, nil)
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+
+	if _, err := t.Write(data); err != nil {
+		return err
+	}
+
+	return t.Commit()
+}
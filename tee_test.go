@@ -0,0 +1,49 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileMulti(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "sub", "b")
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+
+	assert.NoError(t, xfs.WriteFileMulti([]byte("data"), 0644, a, b))
+	for _, path := range []string{a, b} {
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "data", string(data))
+	}
+
+	err := xfs.WriteFileMulti([]byte("new"), 0644, a, filepath.Join(dir, "missing", "c"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	data, _ := os.ReadFile(a)
+	assert.Equal(t, "data", string(data))
+
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 2)
+}
+
+func TestTeeWriterAbort(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+
+	w, err := xfs.NewTeeWriter([]string{a, b}, 0644, nil)
+	assert.NoError(t, err)
+	w.WriteString("partial")
+	assert.NoError(t, w.Close())
+
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries)
+
+	_, err = w.Write([]byte("more"))
+	assert.Error(t, err)
+}
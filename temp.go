@@ -0,0 +1,88 @@
+package xfs
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+var errPatternHasStar = errors.New("prefix or extension contains '*'")
+
+// MkdirTemp creates a new temporary directory in the directory dir and returns
+// the pathname of the new directory. The new directory's name is generated by
+// adding a random string to the end of pattern. If pattern includes a "*", the
+// random string replaces the last "*" instead. If dir is the empty string,
+// MkdirTemp uses the default directory for temporary files, as returned by
+// os.TempDir. It is the caller's responsibility to remove the directory when
+// it is no longer needed.
+//
+// Parameters:
+//   - dir: the directory in which to create the directory
+//   - pattern: the directory name pattern
+func MkdirTemp(dir, pattern string) (string, error) {
+	return os.MkdirTemp(dir, pattern)
+}
+
+// WithTempDir creates a temporary directory in the default temp directory,
+// calls fn with its path and removes the directory and its contents
+// afterwards, even if fn panics. It returns the error returned by fn, or the
+// error from creating the directory.
+//
+// Parameters:
+//   - pattern: the directory name pattern, see MkdirTemp
+//   - fn: the function to run
+func WithTempDir(pattern string, fn func(dir string) error) error {
+	dir, err := os.MkdirTemp("", pattern)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	return fn(dir)
+}
+
+// WithTempFile creates a temporary file in dir, calls fn with the open file
+// and closes and removes it afterwards, even if fn panics. If dir is the empty
+// string, the default directory for temporary files is used. fn may close the
+// file or rename it into place itself; the cleanup then has nothing to do.
+// It returns the error returned by fn, or the error from creating the file.
+//
+// Parameters:
+//   - dir: the directory in which to create the file
+//   - pattern: the file name pattern, see CreateTemp
+//   - fn: the function to run
+func WithTempFile(dir, pattern string, fn func(f *File) error) error {
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	return fn(f)
+}
+
+// CreateTempExt creates a new temporary file in dir whose name starts with
+// prefix and ends with the extension ext, opens it for reading and writing and
+// returns it. A leading dot is added to ext when missing. If dir is the empty
+// string, the default directory for temporary files is used. It is the
+// caller's responsibility to remove the file when no longer needed.
+//
+// Parameters:
+//   - dir: the directory in which to create the file
+//   - prefix: the file name prefix
+//   - ext: the file extension e.g. ".json"
+func CreateTempExt(dir, prefix, ext string) (*File, error) {
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	if strings.ContainsAny(prefix+ext, "*") {
+		return nil, &os.PathError{Op: "createtemp", Path: prefix + "*" + ext, Err: errPatternHasStar}
+	}
+
+	return os.CreateTemp(dir, prefix+"*"+ext)
+}
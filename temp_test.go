@@ -0,0 +1,94 @@
+package xfs_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMkdirTemp(t *testing.T) {
+	dir, err := xfs.MkdirTemp(t.TempDir(), "scratch-*")
+	assert.NoError(t, err)
+	assert.True(t, xfs.IsDir(dir))
+}
+
+func TestWithTempDir(t *testing.T) {
+	var seen string
+	err := xfs.WithTempDir("xfs-*", func(dir string) error {
+		seen = dir
+		assert.True(t, xfs.IsDir(dir))
+		return xfs.WriteTextFile(filepath.Join(dir, "file.txt"), "data", 0644)
+	})
+	assert.NoError(t, err)
+	assert.False(t, xfs.Exists(seen))
+
+	boom := errors.New("boom")
+	err = xfs.WithTempDir("xfs-*", func(dir string) error {
+		seen = dir
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+	assert.False(t, xfs.Exists(seen))
+
+	assert.Panics(t, func() {
+		xfs.WithTempDir("xfs-*", func(dir string) error {
+			seen = dir
+			panic("boom")
+		})
+	})
+	assert.False(t, xfs.Exists(seen))
+}
+
+func TestWithTempFile(t *testing.T) {
+	dir := t.TempDir()
+
+	var seen string
+	err := xfs.WithTempFile(dir, "stage-*.txt", func(f *xfs.File) error {
+		seen = f.Name()
+		_, err := f.WriteString("data")
+		return err
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, seen)
+	assert.False(t, xfs.Exists(seen))
+
+	final := filepath.Join(dir, "final.txt")
+	err = xfs.WithTempFile(dir, "stage-*.txt", func(f *xfs.File) error {
+		if _, err := f.WriteString("data"); err != nil {
+			return err
+		}
+
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		return xfs.Rename(f.Name(), final)
+	})
+	assert.NoError(t, err)
+
+	data, err := xfs.ReadTextFile(final)
+	assert.NoError(t, err)
+	assert.Equal(t, "data", data)
+}
+
+func TestCreateTempExt(t *testing.T) {
+	dir := t.TempDir()
+
+	f, err := xfs.CreateTempExt(dir, "config-", ".json")
+	assert.NoError(t, err)
+	f.Close()
+	assert.Equal(t, ".json", filepath.Ext(f.Name()))
+	assert.True(t, strings.HasPrefix(filepath.Base(f.Name()), "config-"))
+
+	f, err = xfs.CreateTempExt(dir, "script", "sh")
+	assert.NoError(t, err)
+	f.Close()
+	assert.True(t, strings.HasSuffix(f.Name(), ".sh"))
+
+	_, err = xfs.CreateTempExt(dir, "bad*", ".json")
+	assert.Error(t, err)
+}
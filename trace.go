@@ -0,0 +1,113 @@
+package xfs
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// OpEvent describes a completed file system operation reported to Metrics.
+type OpEvent struct {
+	// Op is the name of the operation, e.g. "readfile" or "rename".
+	Op string
+	// Path is the file the operation acted on; for copies and renames it is
+	// the source.
+	Path string
+	// Bytes is the number of bytes read or written, or zero for operations
+	// that transfer no data or do not report it.
+	Bytes int64
+	// Duration is the time the operation took.
+	Duration time.Duration
+	// Err is the error returned by the operation, or nil.
+	Err error
+}
+
+// Span is an operation in progress started by a Tracer.
+type Span interface {
+	// End finishes the span with the number of bytes transferred and the
+	// error returned by the operation.
+	End(bytes int64, err error)
+}
+
+// Tracer starts a Span for every instrumented operation. It maps directly to
+// an OpenTelemetry tracer: Start starts a span named after op with the path
+// as an attribute, and End records the bytes and the error.
+type Tracer interface {
+	Start(op, path string) Span
+}
+
+// Metrics receives an OpEvent for every instrumented operation, e.g. to feed
+// latency histograms and byte counters.
+type Metrics interface {
+	RecordOp(ev OpEvent)
+}
+
+type tracerHolder struct{ Tracer }
+
+type metricsHolder struct{ Metrics }
+
+var (
+	tracer  atomic.Pointer[tracerHolder]
+	metrics atomic.Pointer[metricsHolder]
+)
+
+// SetTracer registers t to trace the operations of the package, or removes
+// the tracer if t is nil. The instrumented functions are ReadFile,
+// ReadTextFile, ReadFileLines, ReadFileLinesOpt, WriteFile, WriteTextFile,
+// WriteFileLines, WriteFileLinesSep, WriteFileLinesRaw, WriteFileAtomic,
+// Open, OpenFile, OpenCompressed, Create, CopyFile, CopyFileOpt, CopyDir,
+// CopyDirOpt, Remove, RemoveAll, Rename, Mkdir, MkdirAll, Chmod and Chown.
+// Other functions are not reported, except where they call one of these.
+//
+// Parameters:
+//   - t: the tracer
+func SetTracer(t Tracer) {
+	if t == nil {
+		tracer.Store(nil)
+		return
+	}
+
+	tracer.Store(&tracerHolder{t})
+}
+
+// SetMetrics registers m to receive an OpEvent for every operation
+// instrumented for SetTracer, or removes it if m is nil.
+//
+// Parameters:
+//   - m: the metrics receiver
+func SetMetrics(m Metrics) {
+	if m == nil {
+		metrics.Store(nil)
+		return
+	}
+
+	metrics.Store(&metricsHolder{m})
+}
+
+// noopDone is returned by instrument when nothing is registered, so
+// uninstrumented programs pay for two atomic loads only.
+func noopDone(int64, error) {}
+
+// instrument starts tracking an operation and returns the function to call
+// with its outcome.
+func instrument(op, path string) func(bytes int64, err error) {
+	t, m := tracer.Load(), metrics.Load()
+	if t == nil && m == nil {
+		return noopDone
+	}
+
+	var span Span
+	if t != nil {
+		span = t.Start(op, path)
+	}
+
+	start := time.Now()
+	return func(bytes int64, err error) {
+		if span != nil {
+			span.End(bytes, err)
+		}
+
+		if m != nil {
+			m.RecordOp(OpEvent{Op: op, Path: path, Bytes: bytes, Duration: time.Since(start), Err: err})
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	spans  []string
+	events []xfs.OpEvent
+}
+
+type recordedSpan struct {
+	r  *recorder
+	op string
+}
+
+func (r *recorder) Start(op, path string) xfs.Span {
+	return &recordedSpan{r: r, op: op}
+}
+
+func (s *recordedSpan) End(bytes int64, err error) {
+	s.r.mu.Lock()
+	defer s.r.mu.Unlock()
+	s.r.spans = append(s.r.spans, s.op)
+}
+
+func (r *recorder) RecordOp(ev xfs.OpEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+}
+
+func TestInstrumentation(t *testing.T) {
+	r := &recorder{}
+	xfs.SetTracer(r)
+	xfs.SetMetrics(r)
+	defer xfs.SetTracer(nil)
+	defer xfs.SetMetrics(nil)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file")
+	xfs.WriteFile(path, []byte("hello"), 0644)
+	xfs.ReadFile(path)
+	xfs.Rename(path, path+".new")
+	xfs.Remove(filepath.Join(dir, "missing"))
+
+	assert.Equal(t, []string{"writefile", "readfile", "rename", "remove"}, r.spans)
+	assert.Len(t, r.events, 4)
+	assert.Equal(t, path, r.events[0].Path)
+	assert.Equal(t, int64(5), r.events[0].Bytes)
+	assert.Equal(t, int64(5), r.events[1].Bytes)
+	assert.NoError(t, r.events[2].Err)
+	assert.ErrorIs(t, r.events[3].Err, os.ErrNotExist)
+
+	xfs.SetTracer(nil)
+	xfs.SetMetrics(nil)
+	xfs.ReadFile(path + ".new")
+	assert.Len(t, r.events, 4)
+}
+
+func TestInstrumentationCopy(t *testing.T) {
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "a"), []byte("hello"), 0644)
+	os.WriteFile(filepath.Join(src, "b"), []byte("world!"), 0644)
+	dst := filepath.Join(t.TempDir(), "copy")
+
+	r := &recorder{}
+	xfs.SetMetrics(r)
+	defer xfs.SetMetrics(nil)
+
+	assert.NoError(t, xfs.CopyDir(src, dst, false))
+	assert.NoError(t, xfs.CopyFile(filepath.Join(src, "a"), filepath.Join(dst, "c"), false))
+	xfs.ReadFileLines(filepath.Join(dst, "b"))
+
+	assert.Len(t, r.events, 3)
+	assert.Equal(t, "copydir", r.events[0].Op)
+	assert.Equal(t, int64(11), r.events[0].Bytes)
+	assert.Equal(t, "copyfile", r.events[1].Op)
+	assert.Equal(t, int64(5), r.events[1].Bytes)
+	assert.Equal(t, "readfile", r.events[2].Op)
+	assert.Equal(t, int64(6), r.events[2].Bytes)
+}
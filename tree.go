@@ -0,0 +1,123 @@
+package xfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TreeOptions controls the output of Tree.
+type TreeOptions struct {
+	// MaxDepth limits how many levels below the root are rendered.
+	// Zero or a negative value means no limit.
+	MaxDepth int
+	// Pattern only lists files whose base name matches the pattern, using the
+	// same syntax as filepath.Match. Directories are always listed.
+	Pattern string
+	// ShowSize annotates files with their size in bytes.
+	ShowSize bool
+	// ShowHidden includes entries whose name starts with a dot.
+	ShowHidden bool
+}
+
+// Tree renders the directory tree rooted at root in the style of tree(1).
+// Entries are listed in lexical order and symbolic links are shown with
+// their target rather than followed. The output ends with a newline, which
+// makes it suitable for golden files.
+//
+// Parameters:
+//   - root: the directory to render
+//   - opts: the rendering options, nil uses the defaults
+func Tree(root string, opts *TreeOptions) (string, error) {
+	if opts == nil {
+		opts = &TreeOptions{}
+	}
+
+	if opts.Pattern != "" {
+		if _, err := filepath.Match(opts.Pattern, ""); err != nil {
+			return "", err
+		}
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", err
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(root)
+	sb.WriteString("\n")
+
+	if !info.IsDir() {
+		return sb.String(), nil
+	}
+
+	if err := writeTree(sb, root, "", 1, opts); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+func writeTree(sb *strings.Builder, dir, prefix string, depth int, opts *TreeOptions) error {
+	if opts.MaxDepth > 0 && depth > opts.MaxDepth {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	visible := entries[:0]
+	for _, entry := range entries {
+		if !opts.ShowHidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		if opts.Pattern != "" && !entry.IsDir() {
+			if ok, _ := filepath.Match(opts.Pattern, entry.Name()); !ok {
+				continue
+			}
+		}
+
+		visible = append(visible, entry)
+	}
+
+	for i, entry := range visible {
+		connector, indent := "├── ", "│   "
+		if i == len(visible)-1 {
+			connector, indent = "└── ", "    "
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		sb.WriteString(prefix)
+		sb.WriteString(connector)
+		sb.WriteString(entry.Name())
+
+		if entry.Type()&os.ModeSymlink != 0 {
+			if target, err := os.Readlink(path); err == nil {
+				sb.WriteString(" -> ")
+				sb.WriteString(target)
+			}
+		} else if opts.ShowSize && !entry.IsDir() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(sb, " [%d]", info.Size())
+		}
+
+		sb.WriteString("\n")
+
+		if entry.IsDir() {
+			if err := writeTree(sb, path, prefix+indent, depth+1, opts); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package xfs_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	root := t.TempDir()
+	xfs.MkdirAllDefault(filepath.Join(root, "src", "pkg"))
+	xfs.WriteTextFile(filepath.Join(root, "src", "main.go"), "package main", 0644)
+	xfs.WriteTextFile(filepath.Join(root, "src", "pkg", "lib.go"), "package pkg", 0644)
+	xfs.WriteTextFile(filepath.Join(root, "README.md"), "readme", 0644)
+	xfs.WriteTextFile(filepath.Join(root, ".hidden"), "", 0644)
+
+	out, err := xfs.Tree(root, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, root+"\n"+
+		"├── README.md\n"+
+		"└── src\n"+
+		"    ├── main.go\n"+
+		"    └── pkg\n"+
+		"        └── lib.go\n", out)
+
+	out, err = xfs.Tree(root, &xfs.TreeOptions{MaxDepth: 1, ShowSize: true})
+	assert.NoError(t, err)
+	assert.Equal(t, root+"\n"+
+		"├── README.md [6]\n"+
+		"└── src\n", out)
+
+	out, err = xfs.Tree(root, &xfs.TreeOptions{Pattern: "*.go"})
+	assert.NoError(t, err)
+	assert.Equal(t, root+"\n"+
+		"└── src\n"+
+		"    ├── main.go\n"+
+		"    └── pkg\n"+
+		"        └── lib.go\n", out)
+}
@@ -0,0 +1,248 @@
+package xfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// errTxnDone is returned when a Txn is used after Commit or Rollback.
+var errTxnDone = errors.New("transaction already finished")
+
+type txnOpKind int
+
+const (
+	txnWrite txnOpKind = iota
+	txnRename
+	txnRemove
+)
+
+type txnOp struct {
+	kind txnOpKind
+	src  string
+	dst  string
+}
+
+// Txn groups file writes, renames and removals so that they are applied
+// all-or-nothing. New content is written to a hidden staging directory right
+// away, but nothing outside it changes until Commit, which applies the
+// operations in order with renames. If an operation fails, the ones already
+// applied are reverted, including restoring files that were replaced or
+// removed. Rollback discards everything that was staged.
+//
+// Renames are only atomic within one file system, so the staging directory
+// should live on the same file system as the files the transaction touches.
+// Parent directories created by Commit are kept on rollback.
+type Txn struct {
+	staging string
+	ops     []txnOp
+	undo    []func() error
+	seq     int
+	done    bool
+}
+
+// NewTxn starts a transaction whose staging directory is a new hidden
+// directory inside dir.
+//
+// Parameters:
+//   - dir: the directory that holds the staging directory
+func NewTxn(dir string) (*Txn, error) {
+	staging, err := os.MkdirTemp(dir, ".xfs-txn-")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Txn{staging: staging}, nil
+}
+
+// StagingDir returns the path of the staging directory.
+func (t *Txn) StagingDir() string {
+	return t.staging
+}
+
+func (t *Txn) next() string {
+	t.seq++
+	return filepath.Join(t.staging, fmt.Sprintf("%d", t.seq))
+}
+
+// Create stages a new file that replaces filename on Commit and returns it
+// open for writing. The caller must close the file before calling Commit.
+//
+// Parameters:
+//   - filename: the name of the file to create or replace
+//   - perm: the file permissions
+func (t *Txn) Create(filename string, perm FileMode) (*File, error) {
+	if t.done {
+		return nil, &os.PathError{Op: "create", Path: filename, Err: errTxnDone}
+	}
+
+	staged := t.next()
+	f, err := os.OpenFile(staged, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	t.ops = append(t.ops, txnOp{kind: txnWrite, src: staged, dst: filename})
+	return f, nil
+}
+
+// WriteFile stages data to be written to filename on Commit.
+//
+// Parameters:
+//   - filename: the name of the file to create or replace
+//   - data: the data to write
+//   - perm: the file permissions
+func (t *Txn) WriteFile(filename string, data []byte, perm FileMode) error {
+	f, err := t.Create(filename, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// WriteTextFile stages the string data to be written to filename on Commit.
+//
+// Parameters:
+//   - filename: the name of the file to create or replace
+//   - data: the text to write
+//   - perm: the file permissions
+func (t *Txn) WriteTextFile(filename string, data string, perm FileMode) error {
+	return t.WriteFile(filename, []byte(data), perm)
+}
+
+// Rename stages renaming oldpath to newpath on Commit, replacing newpath if
+// it exists.
+//
+// Parameters:
+//   - oldpath: the current name
+//   - newpath: the new name
+func (t *Txn) Rename(oldpath, newpath string) error {
+	if t.done {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: errTxnDone}
+	}
+
+	t.ops = append(t.ops, txnOp{kind: txnRename, src: oldpath, dst: newpath})
+	return nil
+}
+
+// Remove stages removing the named file or directory, with its children, on
+// Commit. Committing fails if the path does not exist by then.
+//
+// Parameters:
+//   - path: the file or directory to remove
+func (t *Txn) Remove(path string) error {
+	if t.done {
+		return &os.PathError{Op: "remove", Path: path, Err: errTxnDone}
+	}
+
+	t.ops = append(t.ops, txnOp{kind: txnRemove, dst: path})
+	return nil
+}
+
+// Commit applies the staged operations in order. If one fails, the applied
+// operations are reverted and the error is returned. The staging directory
+// is removed afterwards, unless reverting failed too: it then still holds
+// the files that were replaced or removed, and the error includes a
+// *PathError for the staging directory so that they can be recovered.
+func (t *Txn) Commit() error {
+	if t.done {
+		return &os.PathError{Op: "commit", Path: t.staging, Err: errTxnDone}
+	}
+
+	t.done = true
+	for _, op := range t.ops {
+		if err := t.apply(op); err != nil {
+			if revertErr := t.revert(); revertErr != nil {
+				return errors.Join(err, &os.PathError{Op: "revert", Path: t.staging, Err: revertErr})
+			}
+
+			os.RemoveAll(t.staging)
+			return err
+		}
+	}
+
+	return os.RemoveAll(t.staging)
+}
+
+// Rollback discards the staged operations and the staging directory. Rolling
+// back a finished transaction is a no-op.
+func (t *Txn) Rollback() error {
+	if t.done {
+		return nil
+	}
+
+	t.done = true
+	return os.RemoveAll(t.staging)
+}
+
+func (t *Txn) apply(op txnOp) error {
+	switch op.kind {
+	case txnWrite:
+		if err := MkdirAll(filepath.Dir(op.dst), 0755); err != nil {
+			return err
+		}
+
+		if err := t.setAside(op.dst, false); err != nil {
+			return err
+		}
+
+		return t.rename(op.src, op.dst)
+	case txnRename:
+		if _, err := os.Lstat(op.src); err != nil {
+			return err
+		}
+
+		if err := t.setAside(op.dst, false); err != nil {
+			return err
+		}
+
+		return t.rename(op.src, op.dst)
+	default:
+		return t.setAside(op.dst, true)
+	}
+}
+
+// rename renames src to dst and records how to undo it.
+func (t *Txn) rename(src, dst string) error {
+	if err := os.Rename(src, dst); err != nil {
+		return err
+	}
+
+	t.undo = append(t.undo, func() error { return os.Rename(dst, src) })
+	return nil
+}
+
+// setAside moves path into the staging directory so that it can be restored
+// by revert. A missing path is an error only if mustExist is set.
+func (t *Txn) setAside(path string, mustExist bool) error {
+	if _, err := os.Lstat(path); err != nil {
+		if !mustExist && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	return t.rename(path, t.next())
+}
+
+// revert undoes the applied operations in reverse order.
+func (t *Txn) revert() error {
+	var errs []error
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		if err := t.undo[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	t.undo = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,94 @@
+package xfs_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxnCommit(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old.txt")
+	os.WriteFile(old, []byte("old"), 0644)
+	os.WriteFile(filepath.Join(dir, "config.txt"), []byte("v1"), 0644)
+
+	txn, err := xfs.NewTxn(dir)
+	assert.NoError(t, err)
+	assert.NoError(t, txn.WriteTextFile(filepath.Join(dir, "config.txt"), "v2", 0644))
+	assert.NoError(t, txn.WriteTextFile(filepath.Join(dir, "bin", "tool"), "tool", 0755))
+	assert.NoError(t, txn.Rename(old, filepath.Join(dir, "renamed.txt")))
+	assert.NoError(t, txn.Remove(filepath.Join(dir, "renamed.txt")))
+
+	data, _ := os.ReadFile(filepath.Join(dir, "config.txt"))
+	assert.Equal(t, "v1", string(data))
+
+	assert.NoError(t, txn.Commit())
+
+	data, _ = os.ReadFile(filepath.Join(dir, "config.txt"))
+	assert.Equal(t, "v2", string(data))
+	assert.True(t, xfs.Exists(filepath.Join(dir, "bin", "tool")))
+	assert.False(t, xfs.Exists(old))
+	assert.False(t, xfs.Exists(filepath.Join(dir, "renamed.txt")))
+	assert.False(t, xfs.Exists(txn.StagingDir()))
+}
+
+func TestTxnRevert(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.txt")
+	os.WriteFile(config, []byte("v1"), 0644)
+
+	txn, _ := xfs.NewTxn(dir)
+	txn.WriteTextFile(config, "v2", 0644)
+	txn.Remove(filepath.Join(dir, "missing"))
+
+	err := txn.Commit()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	data, _ := os.ReadFile(config)
+	assert.Equal(t, "v1", string(data))
+
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+}
+
+func TestTxnRevertFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	os.WriteFile(path, []byte("original"), 0644)
+
+	// Writing below the removed file makes it a directory, which the undo
+	// cannot rename the original file over.
+	txn, _ := xfs.NewTxn(dir)
+	txn.Remove(path)
+	txn.WriteTextFile(filepath.Join(path, "inner"), "new", 0644)
+	txn.Remove(filepath.Join(dir, "missing"))
+
+	err := txn.Commit()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	assert.Contains(t, err.Error(), "revert "+txn.StagingDir())
+
+	var contents []string
+	entries, _ := os.ReadDir(txn.StagingDir())
+	for _, entry := range entries {
+		data, _ := os.ReadFile(filepath.Join(txn.StagingDir(), entry.Name()))
+		contents = append(contents, string(data))
+	}
+
+	assert.Contains(t, contents, "original")
+}
+
+func TestTxnRollback(t *testing.T) {
+	dir := t.TempDir()
+	txn, _ := xfs.NewTxn(dir)
+	txn.WriteTextFile(filepath.Join(dir, "new.txt"), "new", 0644)
+
+	assert.NoError(t, txn.Rollback())
+	assert.False(t, xfs.Exists(filepath.Join(dir, "new.txt")))
+	assert.False(t, xfs.Exists(txn.StagingDir()))
+	assert.Error(t, txn.WriteTextFile(filepath.Join(dir, "new.txt"), "new", 0644))
+}
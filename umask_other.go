@@ -0,0 +1,28 @@
+//go:build !unix
+
+package xfs
+
+// GetUmask returns the file mode creation mask of the process. The mask is
+// removed from the permissions requested when files and directories are
+// created, e.g. a umask of 022 turns 0777 into 0755.
+//
+// On Windows, which has no umask, GetUmask returns 0.
+func GetUmask() FileMode {
+	return 0
+}
+
+// SetUmask sets the file mode creation mask of the process and returns the
+// previous mask. The umask is process-wide, so changing it affects files
+// created concurrently by other goroutines.
+//
+// On Windows, which has no umask, SetUmask does nothing and returns 0.
+//
+// Parameters:
+//   - mask: the new mask e.g. 022
+func SetUmask(mask FileMode) FileMode {
+	return 0
+}
+
+func getUmask() FileMode {
+	return 0
+}
@@ -0,0 +1,68 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetUmask(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no umask")
+	}
+
+	old := xfs.SetUmask(027)
+	defer xfs.SetUmask(old)
+
+	assert.Equal(t, os.FileMode(027), xfs.GetUmask())
+	assert.Equal(t, os.FileMode(027), xfs.SetUmask(022))
+	assert.Equal(t, os.FileMode(022), xfs.GetUmask())
+}
+
+func TestEnsureDirExact(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no umask")
+	}
+
+	old := xfs.SetUmask(022)
+	defer xfs.SetUmask(old)
+
+	dir := filepath.Join(t.TempDir(), "a", "b")
+	assert.NoError(t, xfs.EnsureDirExact(dir, 0775))
+
+	for _, p := range []string{dir, filepath.Dir(dir)} {
+		info, err := os.Stat(p)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0775), info.Mode().Perm(), p)
+	}
+
+	assert.NoError(t, xfs.EnsureDirExact(dir, 0700))
+	info, _ := os.Stat(dir)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+}
+
+func TestEnsureFileExact(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no umask")
+	}
+
+	old := xfs.SetUmask(077)
+	defer xfs.SetUmask(old)
+
+	path := filepath.Join(t.TempDir(), "shared.txt")
+	assert.NoError(t, xfs.EnsureFileExact(path, 0664))
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0664), info.Mode().Perm())
+
+	xfs.WriteTextFile(path, "keep", 0664)
+	assert.NoError(t, xfs.EnsureFileExact(path, 0600))
+	info, _ = os.Stat(path)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	data, _ := xfs.ReadTextFile(path)
+	assert.Equal(t, "keep", data)
+}
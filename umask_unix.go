@@ -0,0 +1,59 @@
+//go:build unix
+
+package xfs
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+var umaskMu sync.Mutex
+
+// GetUmask returns the file mode creation mask of the process. The mask is
+// removed from the permissions requested when files and directories are
+// created, e.g. a umask of 022 turns 0777 into 0755.
+//
+// On Windows, which has no umask, GetUmask returns 0.
+func GetUmask() FileMode {
+	return getUmask()
+}
+
+// SetUmask sets the file mode creation mask of the process and returns the
+// previous mask. The umask is process-wide, so changing it affects files
+// created concurrently by other goroutines.
+//
+// On Windows, which has no umask, SetUmask does nothing and returns 0.
+//
+// Parameters:
+//   - mask: the new mask e.g. 022
+func SetUmask(mask FileMode) FileMode {
+	umaskMu.Lock()
+	defer umaskMu.Unlock()
+	return FileMode(unix.Umask(int(mask.Perm())))
+}
+
+func getUmask() FileMode {
+	// Linux 4.7+ reports the umask in /proc, which avoids briefly changing it.
+	if f, err := os.Open("/proc/self/status"); err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if value, ok := strings.CutPrefix(scanner.Text(), "Umask:"); ok {
+				if mask, err := strconv.ParseUint(strings.TrimSpace(value), 8, 32); err == nil {
+					return FileMode(mask)
+				}
+			}
+		}
+	}
+
+	umaskMu.Lock()
+	defer umaskMu.Unlock()
+	mask := unix.Umask(0)
+	unix.Umask(mask)
+	return FileMode(mask)
+}
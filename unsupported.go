@@ -0,0 +1,23 @@
+package xfs
+
+import (
+	"errors"
+)
+
+// unsupportedError wraps a platform error that indicates a missing feature so
+// that it matches errors.ErrUnsupported while keeping the original error.
+type unsupportedError struct {
+	err error
+}
+
+func (e *unsupportedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unsupportedError) Unwrap() error {
+	return e.err
+}
+
+func (e *unsupportedError) Is(target error) bool {
+	return target == errors.ErrUnsupported
+}
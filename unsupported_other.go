@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package xfs
+
+// unsupportedErrno maps the errno values used to signal missing file system
+// support to an error that also matches errors.ErrUnsupported.
+func unsupportedErrno(err error) error {
+	return err
+}
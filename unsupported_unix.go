@@ -0,0 +1,17 @@
+//go:build unix
+
+package xfs
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// unsupportedErrno maps the errno values used to signal missing file system
+// support to an error that also matches errors.ErrUnsupported.
+func unsupportedErrno(err error) error {
+	if err == unix.EOPNOTSUPP || err == unix.ENOTSUP || err == unix.ENOSYS || err == unix.ENOTTY {
+		return &unsupportedError{err}
+	}
+
+	return err
+}
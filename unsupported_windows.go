@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package xfs
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// unsupportedErrno maps the errors used to signal missing file system
+// support to an error that also matches errors.ErrUnsupported.
+func unsupportedErrno(err error) error {
+	if err == windows.ERROR_INVALID_FUNCTION || err == windows.ERROR_NOT_SUPPORTED {
+		return &unsupportedError{err}
+	}
+
+	return err
+}
@@ -0,0 +1,566 @@
+package xfs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Op describes a kind of change reported by a Watcher. Ops can be combined
+// into a mask for WatchOptions.Ops.
+type Op uint32
+
+const (
+	// OpCreate reports a new file or directory.
+	OpCreate Op = 1 << iota
+	// OpWrite reports modified content.
+	OpWrite
+	// OpRemove reports a removed file or directory.
+	OpRemove
+	// OpRename reports a file or directory renamed to a name that is not
+	// watched.
+	OpRename
+	// OpChmod reports changed attributes.
+	OpChmod
+	// OpMove reports a file or directory renamed within the watched paths;
+	// the event carries both the old and the new path.
+	OpMove
+
+	// OpAll matches every kind of change.
+	OpAll = OpCreate | OpWrite | OpRemove | OpRename | OpChmod | OpMove
+)
+
+// String returns the name of the op, or the names of its bits joined by "|".
+func (op Op) String() string {
+	names := []string{"CREATE", "WRITE", "REMOVE", "RENAME", "CHMOD", "MOVE"}
+	var parts []string
+	for i, name := range names {
+		if op&(1<<i) != 0 {
+			parts = append(parts, name)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "NONE"
+	}
+
+	return strings.Join(parts, "|")
+}
+
+// Event is a change reported by a Watcher.
+type Event struct {
+	// Op is the kind of change.
+	Op Op
+	// Path is the path of the changed file, or the new path for OpMove.
+	Path string
+	// OldPath is the previous path for OpMove and empty otherwise.
+	OldPath string
+}
+
+// String returns a description of the event for logging.
+func (e Event) String() string {
+	if e.Op == OpMove {
+		return e.Op.String() + " " + e.OldPath + " -> " + e.Path
+	}
+
+	return e.Op.String() + " " + e.Path
+}
+
+// DefaultMoveWindow is how long a Watcher waits for the second half of a
+// rename before reporting it as OpRename.
+const DefaultMoveWindow = 50 * time.Millisecond
+
+// WatchOptions controls the events reported by a Watcher.
+type WatchOptions struct {
+	// Ignore drops events for paths matching any of the patterns. A pattern
+	// without a slash is matched against every element of the path below the
+	// watched directory, so "node_modules" or "*.swp" ignore those entries
+	// and everything inside them. A pattern with a slash is matched against
+	// the slash separated path relative to the watched directory and may use
+	// "**" as in Glob.
+	Ignore []string
+	// Ops is the mask of event kinds to report. Zero reports all kinds.
+	// Without OpMove, moves are reported as OpRename of the old path and
+	// OpCreate of the new one.
+	Ops Op
+	// Recursive watches the subdirectories of added directories, including
+	// directories created later.
+	Recursive bool
+	// MoveWindow is how long to wait for the new name of a renamed file
+	// before reporting an OpRename. Zero uses DefaultMoveWindow.
+	MoveWindow time.Duration
+	// Poll makes NewWatcher detect changes by periodically scanning the
+	// watched paths, as PollWatcher does, instead of using the operating
+	// system's notification API.
+	Poll bool
+	// PollFallback makes a Watcher fall back to polling for paths that the
+	// notification API cannot watch, such as network file systems or when
+	// the system's watch limit is exhausted.
+	PollFallback bool
+	// PollInterval is the time between scans when polling. Zero uses
+	// DefaultPollInterval.
+	PollInterval time.Duration
+}
+
+// withDefaults returns a copy of opts, which may be nil, with the zero
+// values replaced by their defaults.
+func (opts *WatchOptions) withDefaults() (WatchOptions, error) {
+	var o WatchOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Ops == 0 {
+		o.Ops = OpAll
+	}
+
+	if o.MoveWindow <= 0 {
+		o.MoveWindow = DefaultMoveWindow
+	}
+
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultPollInterval
+	}
+
+	for _, pattern := range o.Ignore {
+		if _, err := filepath.Match(filepath.FromSlash(pattern), ""); err != nil {
+			return o, err
+		}
+	}
+
+	return o, nil
+}
+
+// Watcher reports changes to watched files and directories as Events. It
+// builds on the operating system's notification API and cleans up its raw
+// output: ignored paths and masked kinds are dropped, and a rename followed
+// by the creation of the new name is reported as a single OpMove event.
+// When a moved file's old or new name is ignored, as with editors that save
+// through a temporary file, the event is reported as OpCreate of the new name
+// or OpRename of the old one instead.
+//
+// With the Poll option, or for paths where the PollFallback option applies,
+// changes are detected by a PollWatcher whose events are delivered on the
+// same channels.
+type Watcher struct {
+	eventFilter
+	fsw     *fsnotify.Watcher
+	poll    *PollWatcher
+	events  chan Event
+	errors  chan error
+	done    chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	pending []string
+	closed  bool
+}
+
+// NewWatcher creates a Watcher with no watched paths. Call Add to watch
+// paths and Close to release the watcher.
+//
+// Parameters:
+//   - opts: the watch options, nil uses the defaults
+func NewWatcher(opts *WatchOptions) (*Watcher, error) {
+	o, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		eventFilter: eventFilter{opts: o},
+		events:      make(chan Event, 64),
+		errors:      make(chan error, 8),
+		done:        make(chan struct{}),
+	}
+
+	if !o.Poll {
+		fsw, err := fsnotify.NewWatcher()
+		if err != nil && !o.PollFallback {
+			return nil, err
+		}
+
+		if err == nil {
+			w.fsw = fsw
+			w.wg.Add(1)
+			go w.run()
+		}
+	}
+
+	return w, nil
+}
+
+// Events returns the channel on which events are delivered. It is closed
+// by Close.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Errors returns the channel on which watch errors are delivered. It is
+// closed by Close.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Add starts watching the named file or directory. Changes to the entries of
+// a directory are reported; with the Recursive option, so are changes in its
+// subdirectories.
+//
+// Parameters:
+//   - path: the file or directory to watch
+func (w *Watcher) Add(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return &os.PathError{Op: "watch", Path: path, Err: os.ErrClosed}
+	}
+
+	if w.fsw != nil {
+		w.addRoot(abs)
+		if w.opts.Recursive {
+			err = w.watchTree(abs)
+		} else {
+			err = w.watch(abs)
+		}
+
+		if err == nil || !w.opts.PollFallback {
+			return err
+		}
+
+		w.removeRoot(abs)
+		w.unwatch(abs)
+	}
+
+	return w.pollAdd(abs)
+}
+
+// pollAdd watches path with the polling watcher, starting it if needed.
+func (w *Watcher) pollAdd(path string) error {
+	if w.poll == nil {
+		poll, err := NewPollWatcher(&w.opts)
+		if err != nil {
+			return err
+		}
+
+		w.poll = poll
+		w.wg.Add(1)
+		go w.forward(poll)
+	}
+
+	return w.poll.Add(path)
+}
+
+// forward delivers the events and errors of the polling watcher.
+func (w *Watcher) forward(poll *PollWatcher) {
+	defer w.wg.Done()
+
+	events, errs := poll.Events(), poll.Errors()
+	for events != nil || errs != nil {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+
+			w.send(ev)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+
+			w.sendError(err)
+		}
+	}
+}
+
+// Remove stops watching the named file or directory, and its
+// subdirectories if they were added recursively.
+//
+// Parameters:
+//   - path: the file or directory
+func (w *Watcher) Remove(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.poll != nil {
+		w.poll.Remove(abs)
+	}
+
+	if w.fsw != nil {
+		w.removeRoot(abs)
+		w.unwatch(abs)
+	}
+
+	return nil
+}
+
+// unwatch removes the notification watches of path and, for recursive
+// watchers, of the directories below it.
+func (w *Watcher) unwatch(path string) {
+	for _, watched := range w.fsw.WatchList() {
+		if _, inside := relWithin(path, watched); inside && (watched == path || w.opts.Recursive) {
+			w.fsw.Remove(watched)
+		}
+	}
+}
+
+// Close stops the watcher and closes the Events and Errors channels.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.done)
+	var err error
+	if w.fsw != nil {
+		err = w.fsw.Close()
+	}
+
+	if w.poll != nil {
+		w.poll.Close()
+	}
+
+	w.wg.Wait()
+	close(w.events)
+	close(w.errors)
+	return err
+}
+
+func (w *Watcher) watch(path string) error {
+	if err := w.fsw.Add(path); err != nil {
+		return &os.PathError{Op: "watch", Path: path, Err: err}
+	}
+
+	return nil
+}
+
+// watchTree watches dir and the directories below it that are not ignored.
+func (w *Watcher) watchTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path != dir && os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if path != dir && w.ignored(path) {
+			return filepath.SkipDir
+		}
+
+		return w.watch(path)
+	})
+}
+
+func (w *Watcher) run() {
+	defer w.wg.Done()
+
+	timer := time.NewTimer(w.opts.MoveWindow)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case raw, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+
+			w.handle(raw, timer)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+
+			w.sendError(err)
+		case <-timer.C:
+			w.flushPending()
+		}
+	}
+}
+
+// handle turns a raw notification into events, pairing renames with the
+// creation of the new name.
+func (w *Watcher) handle(raw fsnotify.Event, timer *time.Timer) {
+	path := filepath.Clean(raw.Name)
+	switch {
+	case raw.Has(fsnotify.Create):
+		if len(w.pending) > 0 {
+			old := w.pending[0]
+			w.pending = w.pending[1:]
+			w.emit(Event{Op: OpMove, Path: path, OldPath: old})
+		} else {
+			w.emit(Event{Op: OpCreate, Path: path})
+		}
+
+		if w.opts.Recursive && IsDir(path) && !w.ignored(path) {
+			if err := w.watchTree(path); err != nil {
+				w.sendError(err)
+			}
+		}
+	case raw.Has(fsnotify.Rename):
+		w.pending = append(w.pending, path)
+		timer.Reset(w.opts.MoveWindow)
+	default:
+		w.flushPending()
+		switch {
+		case raw.Has(fsnotify.Write):
+			w.emit(Event{Op: OpWrite, Path: path})
+		case raw.Has(fsnotify.Remove):
+			w.emit(Event{Op: OpRemove, Path: path})
+		case raw.Has(fsnotify.Chmod):
+			w.emit(Event{Op: OpChmod, Path: path})
+		}
+	}
+}
+
+// flushPending reports renames whose new name never showed up.
+func (w *Watcher) flushPending() {
+	for _, old := range w.pending {
+		w.emit(Event{Op: OpRename, Path: old})
+	}
+
+	w.pending = nil
+}
+
+// emit applies the ignore patterns and the op mask and delivers the event.
+func (w *Watcher) emit(ev Event) {
+	w.apply(ev, w.send)
+}
+
+func (w *Watcher) send(ev Event) {
+	select {
+	case w.events <- ev:
+	case <-w.done:
+	}
+}
+
+func (w *Watcher) sendError(err error) {
+	select {
+	case w.errors <- err:
+	case <-w.done:
+	}
+}
+
+// eventFilter holds the watched roots and applies the ignore patterns and
+// the op mask of a watcher.
+type eventFilter struct {
+	opts    WatchOptions
+	rootsMu sync.Mutex
+	roots   []string
+}
+
+// addRoot records a watched path.
+func (f *eventFilter) addRoot(path string) {
+	f.rootsMu.Lock()
+	defer f.rootsMu.Unlock()
+	f.roots = append(f.roots, path)
+}
+
+// removeRoot forgets a watched path.
+func (f *eventFilter) removeRoot(path string) {
+	f.rootsMu.Lock()
+	defer f.rootsMu.Unlock()
+	for i, root := range f.roots {
+		if root == path {
+			f.roots = append(f.roots[:i], f.roots[i+1:]...)
+			return
+		}
+	}
+}
+
+// apply filters ev and passes the resulting events, if any, to send. Moves
+// with an ignored side or a masked OpMove are rewritten as described on
+// Watcher.
+func (f *eventFilter) apply(ev Event, send func(Event)) {
+	if ev.Op == OpMove {
+		oldIgnored, newIgnored := f.ignored(ev.OldPath), f.ignored(ev.Path)
+		switch {
+		case oldIgnored && newIgnored:
+			return
+		case oldIgnored:
+			ev = Event{Op: OpCreate, Path: ev.Path}
+		case newIgnored:
+			ev = Event{Op: OpRename, Path: ev.OldPath}
+		case f.opts.Ops&OpMove == 0:
+			f.apply(Event{Op: OpRename, Path: ev.OldPath}, send)
+			f.apply(Event{Op: OpCreate, Path: ev.Path}, send)
+			return
+		}
+	} else if f.ignored(ev.Path) {
+		return
+	}
+
+	if f.opts.Ops&ev.Op != 0 {
+		send(ev)
+	}
+}
+
+// ignored reports whether path matches one of the ignore patterns.
+func (f *eventFilter) ignored(path string) bool {
+	if len(f.opts.Ignore) == 0 {
+		return false
+	}
+
+	rel := filepath.Base(path)
+	f.rootsMu.Lock()
+	for _, root := range f.roots {
+		if r, inside := relWithin(root, path); inside && r != "." {
+			rel = r
+			break
+		}
+	}
+	f.rootsMu.Unlock()
+
+	return matchIgnore(f.opts.Ignore, filepath.ToSlash(rel))
+}
+
+// matchIgnore reports whether the slash separated relative path rel matches
+// one of the ignore patterns.
+func matchIgnore(patterns []string, rel string) bool {
+	elems := strings.Split(rel, "/")
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			for _, elem := range elems {
+				if ok, _ := filepath.Match(pattern, elem); ok {
+					return true
+				}
+			}
+
+			continue
+		}
+
+		if matchSegments(strings.Split(pattern, "/"), elems) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,75 @@
+package xfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+// nextEvent returns the next event of w or fails the test after a timeout.
+func nextEvent(t *testing.T, w *xfs.Watcher) xfs.Event {
+	t.Helper()
+	select {
+	case ev := <-w.Events():
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+		return xfs.Event{}
+	}
+}
+
+func TestWatcherMove(t *testing.T) {
+	dir := t.TempDir()
+	w, err := xfs.NewWatcher(&xfs.WatchOptions{Ops: xfs.OpCreate | xfs.OpMove | xfs.OpRename | xfs.OpRemove})
+	assert.NoError(t, err)
+	defer w.Close()
+	assert.NoError(t, w.Add(dir))
+
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	os.WriteFile(a, []byte("a"), 0644)
+	assert.Equal(t, xfs.Event{Op: xfs.OpCreate, Path: a}, nextEvent(t, w))
+
+	os.Rename(a, b)
+	assert.Equal(t, xfs.Event{Op: xfs.OpMove, Path: b, OldPath: a}, nextEvent(t, w))
+
+	os.Remove(b)
+	assert.Equal(t, xfs.Event{Op: xfs.OpRemove, Path: b}, nextEvent(t, w))
+}
+
+func TestWatcherIgnore(t *testing.T) {
+	dir := t.TempDir()
+	xfs.MkdirAllDefault(filepath.Join(dir, "node_modules"))
+
+	w, err := xfs.NewWatcher(&xfs.WatchOptions{
+		Ignore:    []string{"*.swp", "node_modules"},
+		Ops:       xfs.OpAll &^ xfs.OpChmod,
+		Recursive: true,
+	})
+	assert.NoError(t, err)
+	defer w.Close()
+	assert.NoError(t, w.Add(dir))
+
+	config := filepath.Join(dir, "config")
+	os.WriteFile(filepath.Join(dir, "node_modules", "pkg"), []byte("x"), 0644)
+	os.WriteFile(config+".swp", []byte("v1"), 0644)
+	os.Rename(config+".swp", config)
+	assert.Equal(t, xfs.Event{Op: xfs.OpCreate, Path: config}, nextEvent(t, w))
+
+	sub := filepath.Join(dir, "sub")
+	xfs.MkdirAllDefault(sub)
+	assert.Equal(t, xfs.Event{Op: xfs.OpCreate, Path: sub}, nextEvent(t, w))
+
+	time.Sleep(50 * time.Millisecond)
+	file := filepath.Join(sub, "file")
+	os.WriteFile(file, nil, 0644)
+	assert.Equal(t, xfs.Event{Op: xfs.OpCreate, Path: file}, nextEvent(t, w))
+}
+
+func TestOpString(t *testing.T) {
+	assert.Equal(t, "CREATE|MOVE", (xfs.OpCreate | xfs.OpMove).String())
+	assert.Equal(t, "NONE", xfs.Op(0).String())
+}
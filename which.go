@@ -0,0 +1,81 @@
+package xfs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Which searches the directories listed in the PATH environment variable for
+// an executable named name and returns its absolute path. If name contains a
+// path separator, it is checked directly instead. On Windows the extensions
+// listed in PATHEXT are tried when name has none of them.
+//
+// Empty and relative PATH entries are ignored, so a program in the current
+// directory is never returned unless name is a path such as "./tool".
+// If no executable is found, the error is an *exec.Error wrapping
+// exec.ErrNotFound.
+//
+// Parameters:
+//   - name: the name of the executable e.g. "git"
+func Which(name string) (string, error) {
+	paths := which(name, true)
+	if len(paths) == 0 {
+		return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+
+	return paths[0], nil
+}
+
+// WhichAll is like Which but returns the absolute paths of every matching
+// executable in PATH order, without duplicates. If none is found, it returns
+// an empty slice.
+//
+// Parameters:
+//   - name: the name of the executable e.g. "python3"
+func WhichAll(name string) []string {
+	return which(name, false)
+}
+
+func which(name string, first bool) []string {
+	if name == "" {
+		return nil
+	}
+
+	if strings.ContainsAny(name, `/\`) {
+		for _, candidate := range executableCandidates(name) {
+			if IsExecutable(candidate) {
+				if abs, err := filepath.Abs(candidate); err == nil {
+					return []string{abs}
+				}
+			}
+		}
+
+		return nil
+	}
+
+	var found []string
+	seen := map[string]bool{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || !filepath.IsAbs(dir) {
+			continue
+		}
+
+		for _, candidate := range executableCandidates(filepath.Join(dir, name)) {
+			if seen[candidate] || !IsExecutable(candidate) {
+				continue
+			}
+
+			seen[candidate] = true
+			found = append(found, candidate)
+			if first {
+				return found
+			}
+
+			break
+		}
+	}
+
+	return found
+}
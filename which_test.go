@@ -0,0 +1,54 @@
+package xfs_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhich(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test creates Unix executables")
+	}
+
+	first := t.TempDir()
+	second := t.TempDir()
+	xfs.WriteTextFile(filepath.Join(first, "tool"), "#!/bin/sh\n", 0755)
+	xfs.WriteTextFile(filepath.Join(second, "tool"), "#!/bin/sh\n", 0755)
+	xfs.WriteTextFile(filepath.Join(second, "data"), "", 0644)
+	t.Setenv("PATH", first+string(filepath.ListSeparator)+second)
+
+	path, err := xfs.Which("tool")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(first, "tool"), path)
+
+	_, err = xfs.Which("data")
+	assert.ErrorIs(t, err, exec.ErrNotFound)
+
+	_, err = xfs.Which("missing")
+	assert.ErrorIs(t, err, exec.ErrNotFound)
+
+	path, err = xfs.Which(filepath.Join(second, "tool"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(second, "tool"), path)
+}
+
+func TestWhichAll(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test creates Unix executables")
+	}
+
+	first := t.TempDir()
+	second := t.TempDir()
+	xfs.WriteTextFile(filepath.Join(first, "tool"), "#!/bin/sh\n", 0755)
+	xfs.WriteTextFile(filepath.Join(second, "tool"), "#!/bin/sh\n", 0755)
+	t.Setenv("PATH", first+string(filepath.ListSeparator)+second+string(filepath.ListSeparator)+first)
+
+	paths := xfs.WhichAll("tool")
+	assert.Equal(t, []string{filepath.Join(first, "tool"), filepath.Join(second, "tool")}, paths)
+	assert.Empty(t, xfs.WhichAll("missing"))
+}
@@ -0,0 +1,95 @@
+package xfs
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// WriteFileIfChanged writes data to the named file only when the file does
+// not exist or its content differs from data, and reports whether it wrote.
+// Leaving identical files untouched preserves their modification time, which
+// keeps code generators from triggering needless rebuilds.
+//
+// The sizes are compared first so that most changed files are detected
+// without reading them. If the file does not exist, it is created with
+// permissions perm (before umask).
+//
+// A written file is flushed to stable storage according to
+// DefaultDurability.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - data: the data to write
+//   - perm: the file permissions
+func WriteFileIfChanged(filename string, data []byte, perm FileMode) (bool, error) {
+	info, err := os.Stat(filename)
+	if err == nil && info.Mode().IsRegular() && info.Size() == int64(len(data)) {
+		current, err := os.ReadFile(filename)
+		if err != nil {
+			return false, err
+		}
+
+		if bytes.Equal(current, data) {
+			return false, nil
+		}
+	} else if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	if err := writeFile(filename, data, perm, DefaultDurability); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// WriteReaderFile copies r to the named file until EOF and returns the number
+// of bytes written. If the file does not exist, it is created with
+// permissions perm (before umask); otherwise it is truncated first, without
+// changing permissions. A failure mid-copy can leave the file partially
+// written; use WriteReaderFileAtomic to avoid that.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - r: the reader to copy from
+//   - perm: the file permissions
+func WriteReaderFile(filename string, r io.Reader, perm FileMode) (int64, error) {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return n, err
+	}
+
+	return n, f.Close()
+}
+
+// WriteReaderFileAtomic copies r to the named file until EOF like
+// WriteReaderFile, but writes through an AtomicWriter: the destination is
+// replaced only once the whole stream was copied, and left untouched if
+// reading or writing fails.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - r: the reader to copy from
+//   - perm: the file permissions
+//   - opts: the write options, nil uses the defaults
+func WriteReaderFileAtomic(filename string, r io.Reader, perm FileMode, opts *AtomicOptions) (int64, error) {
+	w, err := NewAtomicWriter(filename, perm, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer w.Close()
+
+	n, err := io.Copy(w, r)
+	if err != nil {
+		return n, err
+	}
+
+	return n, w.Commit()
+}
@@ -0,0 +1,74 @@
+package xfs_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileIfChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gen.go")
+
+	written, err := xfs.WriteFileIfChanged(path, []byte("package gen"), 0644)
+	assert.NoError(t, err)
+	assert.True(t, written)
+
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	os.Chtimes(path, old, old)
+
+	written, err = xfs.WriteFileIfChanged(path, []byte("package gen"), 0644)
+	assert.NoError(t, err)
+	assert.False(t, written)
+
+	info, _ := os.Stat(path)
+	assert.True(t, info.ModTime().Equal(old))
+
+	written, err = xfs.WriteFileIfChanged(path, []byte("package abc"), 0644)
+	assert.NoError(t, err)
+	assert.True(t, written)
+
+	data, _ := xfs.ReadTextFile(path)
+	assert.Equal(t, "package abc", data)
+}
+
+func TestWriteReaderFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "body")
+
+	n, err := xfs.WriteReaderFile(path, strings.NewReader("hello"), 0644)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestWriteReaderFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "body")
+	os.WriteFile(path, []byte("old"), 0644)
+
+	broken := io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(errors.New("connection reset")))
+	_, err := xfs.WriteReaderFileAtomic(path, broken, 0644, nil)
+	assert.Error(t, err)
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "old", string(data))
+
+	n, err := xfs.WriteReaderFileAtomic(path, strings.NewReader("new"), 0644, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	data, _ = os.ReadFile(path)
+	assert.Equal(t, "new", string(data))
+
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+}
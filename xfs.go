@@ -7,10 +7,10 @@
 package xfs
 
 import (
-	"bufio"
 	"io"
 	"io/fs"
 	"os"
+	"os/user"
 	"path/filepath"
 	"strings"
 )
@@ -34,7 +34,10 @@ type DirEntry = fs.DirEntry
 //   - uid: the new numeric posix user id
 //   - gid: the new numeric posix group id
 func Chown(filename string, uid, gid int) error {
-	return os.Chown(filename, uid, gid)
+	done := instrument("chown", filename)
+	err := os.Chown(filename, uid, gid)
+	done(0, err)
+	return err
 }
 
 // Chmod changes the mode of the named file to mode.
@@ -60,7 +63,10 @@ func Chown(filename string, uid, gid int) error {
 //   - filename: the name of the file
 //   - perm: the new file mode e.g. 0644
 func Chmod(filename string, perm FileMode) error {
-	return os.Chmod(filename, perm)
+	done := instrument("chmod", filename)
+	err := os.Chmod(filename, perm)
+	done(0, err)
+	return err
 }
 
 // Copy copies the file from src to dst. The files are only overwritten if the overwrite
@@ -85,46 +91,28 @@ func Copy(src string, dst string, overwrite bool) error {
 
 // Copy copies the file from src to dst. The files are only overwritten if the overwrite
 // parameter is true. If the file is a symbolic link, it copies the link's target.
+// Symbolic links below src are followed as well, see SymlinkDeref; use CopyDirOpt
+// to recreate or skip them instead.
 //
 // Parameters:
 //   - src: the source file
 //   - dst: the destination file
 //   - overwrite: whether to overwrite the destination file if it exists
 func CopyDir(src string, dst string, overwrite bool) error {
-	return filepath.Walk(src, func(path string, info FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		relPath, err := filepath.Rel(src, path)
-		if err != nil {
-			return err
-		}
-
-		dstPath := filepath.Join(dst, relPath)
-
-		if info.IsDir() {
-			return EnsureDir(dstPath, info.Mode())
-		}
-
-		return copyFile(path, dstPath, info, overwrite)
-	})
+	return CopyDirOpt(src, dst, &CopyOptions{Overwrite: overwrite})
 }
 
 // CopyFile copies the file from src to dst. The files are only overwritten if the overwrite
 // parameter is true. If the file is a symbolic link, it copies the link's target.
 //
+// The copy is flushed to stable storage according to DefaultDurability.
+//
 // Parameters:
 //   - src: the source file
 //   - dst: the destination file
 //   - overwrite: whether to overwrite the destination file if it exists
 func CopyFile(src string, dst string, overwrite bool) error {
-	info, err := os.Stat(src)
-	if err != nil {
-		return err
-	}
-
-	return copyFile(src, dst, info, overwrite)
+	return CopyFileOpt(src, dst, &CopyOptions{Overwrite: overwrite})
 }
 
 // Create creates or truncates the named file. If the file already exists, it is truncated.
@@ -137,7 +125,10 @@ func CopyFile(src string, dst string, overwrite bool) error {
 // Parameters:
 //   - filename: the name of the file
 func Create(filename string) (*File, error) {
-	return os.Create(filename)
+	done := instrument("create", filename)
+	f, err := os.Create(filename)
+	done(0, err)
+	return f, err
 }
 
 // CreateTemp creates a new temporary file in the directory dir with a name beginning with prefix,
@@ -173,6 +164,8 @@ func Chdir(dir string) error {
 }
 
 // Exists reports whether the named file or directory exists.
+// A check that fails for another reason than the file not existing, such as
+// missing permissions, also reports true; use ExistsE to tell these apart.
 //
 // Parameters:
 //   - filename: the name of the file or directory
@@ -202,6 +195,78 @@ func EnsureDirDefault(dir string) error {
 	return EnsureDir(dir, 0755)
 }
 
+// EnsureParentDir creates the parent directory of the named file, along with
+// any missing ancestors, with the specified permissions if it does not exist.
+// It prepares a path for a file that is about to be written.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - perm: the directory permissions
+func EnsureParentDir(filename string, perm FileMode) error {
+	return EnsureDir(filepath.Dir(filename), perm)
+}
+
+// EnsureDirExact creates the named directory, along with any necessary parents,
+// if it does not exist and makes sure the directory has exactly the permissions
+// perm. Unlike EnsureDir, the result does not depend on the process umask:
+// newly created directories are chmod-ed after creation, and an existing
+// directory with different permissions is chmod-ed as well.
+//
+// Parameters:
+//   - dir: the name of the directory
+//   - perm: the directory permissions
+func EnsureDirExact(dir string, perm FileMode) error {
+	var created []string
+	for p := filepath.Clean(dir); !Exists(p); p = filepath.Dir(p) {
+		created = append(created, p)
+		if filepath.Dir(p) == p {
+			break
+		}
+	}
+
+	if err := os.MkdirAll(dir, perm); err != nil {
+		return err
+	}
+
+	for _, p := range created {
+		if err := os.Chmod(p, perm); err != nil {
+			return err
+		}
+	}
+
+	return chmodIfDiffers(dir, perm)
+}
+
+// EnsureFileExact creates the named file if it does not exist and makes sure
+// the file has exactly the permissions perm, regardless of the process umask.
+// An existing file with different permissions is chmod-ed; its content is
+// left untouched.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - perm: the file permissions
+func EnsureFileExact(filename string, perm FileMode) error {
+	if err := EnsureFile(filename, perm); err != nil {
+		return err
+	}
+
+	return chmodIfDiffers(filename, perm)
+}
+
+func chmodIfDiffers(filename string, perm FileMode) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	mask := os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+	if info.Mode()&mask == perm&mask {
+		return nil
+	}
+
+	return os.Chmod(filename, perm)
+}
+
 // EnsureFile creates the named file with the specified permissions if it does not exist.
 //
 // Parameters:
@@ -255,6 +320,85 @@ func IsDir(filename string) bool {
 	return info.IsDir()
 }
 
+// IsBlockDevice reports whether the named file is a block device.
+//
+// Parameters:
+//   - filename: the name of the file
+func IsBlockDevice(filename string) bool {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeDevice != 0 && info.Mode()&os.ModeCharDevice == 0
+}
+
+// IsCharDevice reports whether the named file is a character device.
+//
+// Parameters:
+//   - filename: the name of the file
+func IsCharDevice(filename string) bool {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeDevice != 0 && info.Mode()&os.ModeCharDevice != 0
+}
+
+// IsDevice reports whether the named file is a block or character device.
+//
+// Parameters:
+//   - filename: the name of the file
+func IsDevice(filename string) bool {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeDevice != 0
+}
+
+// IsFifo reports whether the named file is a named pipe (FIFO).
+//
+// Parameters:
+//   - filename: the name of the file
+func IsFifo(filename string) bool {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeNamedPipe != 0
+}
+
+// IsRegular reports whether the named file is a regular file. Unlike IsFile,
+// it reports false for named pipes, sockets and devices.
+//
+// Parameters:
+//   - filename: the name of the file
+func IsRegular(filename string) bool {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode().IsRegular()
+}
+
+// IsSocket reports whether the named file is a Unix domain socket.
+//
+// Parameters:
+//   - filename: the name of the file
+func IsSocket(filename string) bool {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeSocket != 0
+}
+
 // IsSymlink reports whether the named file is a symbolic link.
 //
 // Parameters:
@@ -301,7 +445,10 @@ func Lstat(filename string) (FileInfo, error) {
 //   - dir: the name of the directory
 //   - perm: the directory permissions
 func Mkdir(dir string, perm FileMode) error {
-	return os.Mkdir(dir, perm)
+	done := instrument("mkdir", dir)
+	err := os.Mkdir(dir, perm)
+	done(0, err)
+	return err
 }
 
 // MkdirDefault creates a new directory with the specified name and default permissions.
@@ -323,7 +470,10 @@ func MkdirDefault(dir string) error {
 //   - dir: the name of the directory
 //   - perm: the directory permissions
 func MkdirAll(dir string, perm FileMode) error {
-	return os.MkdirAll(dir, perm)
+	done := instrument("mkdirall", dir)
+	err := os.MkdirAll(dir, perm)
+	done(0, err)
+	return err
 }
 
 // MkdirAll creates a directory named path, along with any necessary parents,
@@ -347,7 +497,10 @@ func MkdirAllDefault(dir string) error {
 // Parameters:
 //   - filename: the name of the file
 func Open(filename string) (*File, error) {
-	return os.Open(filename)
+	done := instrument("open", filename)
+	f, err := os.Open(filename)
+	done(0, err)
+	return f, err
 }
 
 // OpenFile is the generalized open call; most users will use Open or Create
@@ -361,14 +514,18 @@ func Open(filename string) (*File, error) {
 //   - flag: the file open flag
 //   - perm: the file permissions
 func OpenFile(filename string, flag int, perm FileMode) (*File, error) {
-	return os.OpenFile(filename, flag, perm)
+	done := instrument("open", filename)
+	f, err := os.OpenFile(filename, flag, perm)
+	done(0, err)
+	return f, err
 }
 
 // Resolves the relative path to an absolute path. If the relative path is already an absolute path,
 // it is returned as is. If the base path is not provided, the current working directory is used.
-// If the relative path starts with '~/', the home directory is used as the base path. If the relative
-// path starts with './' or '.\', the current working directory is used as the base path. Otherwise,
-// the base path is used as the base path.
+// If the relative path starts with '~/' or '~user/', the home directory of the current or the named
+// user is used as the base path (see ExpandHome). If the relative path starts with './' or '.\',
+// the current working directory is used as the base path. Otherwise, the base path is used as the
+// base path.
 //
 // Parameters:
 //   - relative: the relative path
@@ -382,28 +539,72 @@ func Resolve(relative string, base string) (string, error) {
 		base, _ = Cwd()
 	}
 
-	if relative[0] == '~' && (relative[1] == '/' || relative[1] == '\\') {
-		home, err := os.UserHomeDir()
+	if len(relative) > 0 && relative[0] == '~' {
+		expanded, err := ExpandHome(relative)
 		if err != nil {
 			return "", err
 		}
 
-		return filepath.Abs(filepath.Join(home, relative[2:]))
+		return filepath.Abs(expanded)
 	}
 
-	if relative[0] == '.' && (relative[1] == '/' || relative[1] == '\\') {
+	if len(relative) > 1 && relative[0] == '.' && (relative[1] == '/' || relative[1] == '\\') {
 		return filepath.Abs(filepath.Join(base, relative[2:]))
 	}
 
 	return filepath.Abs(filepath.Join(base, relative))
 }
 
+// ExpandHome expands a leading "~" or "~user" in path to the home directory of
+// the current user or of the named user, like a shell does. Paths that do not
+// start with "~" are returned unchanged. Both '/' and '\\' end the user name.
+//
+// Parameters:
+//   - path: the path to expand e.g. "~deploy/.ssh"
+func ExpandHome(path string) (string, error) {
+	if len(path) == 0 || path[0] != '~' {
+		return path, nil
+	}
+
+	end := strings.IndexAny(path, `/\`)
+	if end < 0 {
+		end = len(path)
+	}
+
+	name, rest := path[1:end], path[end:]
+	var home string
+	if name == "" {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		home = dir
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", err
+		}
+
+		home = u.HomeDir
+	}
+
+	if rest == "" {
+		return home, nil
+	}
+
+	return filepath.Join(home, rest[1:]), nil
+}
+
 // Remove removes the named file or (empty) directory. If there is an error, it will be of type *PathError.
 //
 // Parameters:
 //   - filename: the name of the file or directory
 func Remove(filename string) error {
-	return os.Remove(filename)
+	done := instrument("remove", filename)
+	err := os.Remove(filename)
+	done(0, err)
+	return err
 }
 
 // ReadFile reads the named file and returns the contents.
@@ -414,7 +615,10 @@ func Remove(filename string) error {
 // Parameters:
 //   - filename: the name of the file
 func ReadFile(filename string) ([]byte, error) {
-	return os.ReadFile(filename)
+	done := instrument("readfile", filename)
+	data, err := os.ReadFile(filename)
+	done(int64(len(data)), err)
+	return data, err
 }
 
 // ReadTextFile reads the named file and returns the contents as a string.
@@ -425,7 +629,9 @@ func ReadFile(filename string) ([]byte, error) {
 // Parameters:
 //   - filename: the name of the file
 func ReadTextFile(filename string) (string, error) {
+	done := instrument("readfile", filename)
 	data, err := os.ReadFile(filename)
+	done(int64(len(data)), err)
 	if err != nil {
 		return "", err
 	}
@@ -438,21 +644,12 @@ func ReadTextFile(filename string) (string, error) {
 // Because ReadFileLines reads the whole file, it does not treat an EOF from Read
 // as an error to be reported.
 //
+// Lines are not limited in size; use ReadFileLinesOpt to set a limit.
+//
 // Parameters:
 //   - filename: the name of the file
 func ReadFileLines(filename string) ([]string, error) {
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, err
-	}
-
-	var lines []string
-	scanner := bufio.NewScanner(strings.NewReader(string(data)))
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-	}
-
-	return lines, scanner.Err()
+	return ReadFileLinesOpt(filename, nil)
 }
 
 // RemoveAll removes path and any children it contains.
@@ -461,10 +658,18 @@ func ReadFileLines(filename string) ([]string, error) {
 // returns nil (no error).
 // If there is an error, it will be of type [*PathError].
 //
+// RemoveAll refuses to remove file system roots, the home directory and its
+// parents, and paths less than DefaultRemoveMinDepth elements deep, returning
+// an error wrapping ErrRefusedUnsafePath. Use RemoveAllOpt with Force set to
+// remove such paths deliberately.
+//
 // Parameters:
 //   - path: the name of the file or directory
 func RemoveAll(path string) error {
-	return os.RemoveAll(path)
+	done := instrument("removeall", path)
+	err := RemoveAllOpt(path, nil)
+	done(0, err)
+	return err
 }
 
 // Rename renames (moves) oldpath to newpath.
@@ -473,10 +678,21 @@ func RemoveAll(path string) error {
 // Even within the same directory, on non-Unix platforms Rename is not an atomic operation.
 // If there is an error, it will be of type *LinkError.
 //
+// With DefaultDurability set to DurabilityDataAndDir, Rename behaves like RenameAtomic.
+//
 // Parameters:
 //   - oldpath: the old name of the file or directory
 func Rename(oldpath, newpath string) error {
-	return os.Rename(oldpath, newpath)
+	done := instrument("rename", oldpath)
+	var err error
+	if DefaultDurability == DurabilityDataAndDir {
+		err = RenameAtomic(oldpath, newpath)
+	} else {
+		err = os.Rename(oldpath, newpath)
+	}
+
+	done(0, err)
+	return err
 }
 
 // Stat returns a [FileInfo] describing the named file.
@@ -499,6 +715,17 @@ func Symlink(oldname, newname string) error {
 	return os.Symlink(oldname, newname)
 }
 
+// Truncate changes the size of the named file. If the file is a symbolic link,
+// it changes the size of the link's target. If there is an error, it will be
+// of type *PathError.
+//
+// Parameters:
+//   - filename: the name of the file
+//   - size: the new size of the file in bytes
+func Truncate(filename string, size int64) error {
+	return os.Truncate(filename, size)
+}
+
 // WalkDir walks the file tree rooted at root, calling fn for each file or
 // directory in the tree, including root.
 //
@@ -528,12 +755,17 @@ func WalkDir(root string, walkFn fs.WalkDirFunc) error {
 // Since WriteFile requires multiple system calls to complete, a failure mid-operation
 // can leave the file in a partially written state.
 //
+// The data is flushed to stable storage according to DefaultDurability.
+//
 // Parameters:
 //   - filename: the name of the file
 //   - data: the data to write
 //   - perm: the file permissions
 func WriteFile(filename string, data []byte, perm FileMode) error {
-	return os.WriteFile(filename, data, perm)
+	done := instrument("writefile", filename)
+	err := writeFile(filename, data, perm, DefaultDurability)
+	done(int64(len(data)), err)
+	return err
 }
 
 // WriteFileLines writes the lines to the named file, creating it if necessary.
@@ -542,14 +774,15 @@ func WriteFile(filename string, data []byte, perm FileMode) error {
 // Since WriteFileLines requires multiple system calls to complete, a failure mid-operation
 // can leave the file in a partially written state.
 //
-// The lines are separated by the default end of line character for the platform.
+// The lines are terminated by DefaultEOL, the end of line sequence of the platform unless
+// it was changed.
 //
 // Parameters:
 //   - filename: the name of the file
 //   - lines: the lines to write
 //   - perm: the file permissions
 func WriteFileLines(filename string, lines []string, perm FileMode) error {
-	return WriteFileLinesSep(filename, lines, EOL, perm)
+	return WriteFileLinesSep(filename, lines, DefaultEOL, perm)
 }
 
 // WriteFileLines writes the lines to the named file, creating it if necessary.
@@ -579,37 +812,56 @@ func WriteFileLinesSep(filename string, lines []string, sep string, perm FileMod
 // Since WriteTextFile requires multiple system calls to complete, a failure mid-operation
 // can leave the file in a partially written state.
 //
+// The text is flushed to stable storage according to DefaultDurability.
+//
 // Parameters:
 //   - filename: the name of the file
 //   - data: the text to write
 //   - perm: the file permissions
 func WriteTextFile(filename string, data string, perm FileMode) error {
-	return os.WriteFile(filename, []byte(data), perm)
+	done := instrument("writefile", filename)
+	err := writeFile(filename, []byte(data), perm, DefaultDurability)
+	done(int64(len(data)), err)
+	return err
 }
 
 func copyFile(src, dst string, info FileInfo, overwrite bool) error {
+	_, err := copyFileN(src, dst, info, overwrite)
+	return err
+}
 
+// copyFileN is copyFile that also returns the number of bytes copied.
+func copyFileN(src, dst string, info FileInfo, overwrite bool) (int64, error) {
 	if Exists(dst) && !overwrite {
-		return nil
+		return 0, nil
 	}
 
 	srcFile, err := os.Open(src)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer srcFile.Close()
 
 	dstFile, err := os.Create(dst)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer dstFile.Close()
 
-	if _, err := io.Copy(dstFile, srcFile); err != nil {
-		return err
+	n, err := io.Copy(dstFile, srcFile)
+	if err != nil {
+		return n, err
+	}
+
+	if err := syncData(dstFile, DefaultDurability); err != nil {
+		return n, err
+	}
+
+	if err := os.Chmod(dst, info.Mode()); err != nil {
+		return n, err
 	}
 
-	return os.Chmod(dst, info.Mode())
+	return n, syncParent(dst, DefaultDurability)
 }
 
 // WalkDirFunc is the type of the function called by WalkDir to visit each file or directory.
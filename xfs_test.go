@@ -2,7 +2,10 @@ package xfs_test
 
 import (
 	"io/fs"
+	"net"
 	"os"
+	"os/user"
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -104,6 +107,14 @@ func TestEnsureDirDefault(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestEnsureParentDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a", "b", "file.txt")
+	err := xfs.EnsureParentDir(path, 0755)
+	assert.NoError(t, err)
+	assert.True(t, xfs.IsDir(filepath.Dir(path)))
+	assert.False(t, xfs.Exists(path))
+}
+
 func TestEnsureFile(t *testing.T) {
 	err := xfs.EnsureFile("testfile", 0644)
 	assert.NoError(t, err)
@@ -289,3 +300,81 @@ func TestWriteTextFile(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "test data10", data)
 }
+
+func TestIsRegular(t *testing.T) {
+	assert.True(t, xfs.IsRegular("testfile"))
+	assert.False(t, xfs.IsRegular("testdir"))
+}
+
+func TestIsSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix domain sockets in the file system are not tested on Windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "app.sock")
+	l, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+	defer l.Close()
+
+	assert.True(t, xfs.IsSocket(path))
+	assert.False(t, xfs.IsSocket("testfile"))
+}
+
+func TestIsDevice(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("device files are not available on Windows")
+	}
+
+	assert.True(t, xfs.IsDevice("/dev/null"))
+	assert.False(t, xfs.IsDevice("testfile"))
+}
+
+func TestIsCharDevice(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("device files are not available on Windows")
+	}
+
+	assert.True(t, xfs.IsCharDevice("/dev/null"))
+	assert.False(t, xfs.IsBlockDevice("/dev/null"))
+	assert.False(t, xfs.IsCharDevice("testfile"))
+}
+
+func TestResolveHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	assert.NoError(t, err)
+
+	path, err := xfs.Resolve("~/projects", "")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "projects"), path)
+
+	path, err = xfs.Resolve("~", "")
+	assert.NoError(t, err)
+	assert.Equal(t, home, path)
+
+	path, err = xfs.Resolve(".", "/base")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Clean("/base"), path)
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	assert.NoError(t, err)
+
+	path, err := xfs.ExpandHome("~/.config")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config"), path)
+
+	path, err = xfs.ExpandHome("relative/~")
+	assert.NoError(t, err)
+	assert.Equal(t, "relative/~", path)
+
+	current, err := user.Current()
+	assert.NoError(t, err)
+
+	path, err = xfs.ExpandHome("~" + current.Username + "/bin")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(current.HomeDir, "bin"), path)
+
+	_, err = xfs.ExpandHome("~no-such-user-xfs/bin")
+	assert.Error(t, err)
+}
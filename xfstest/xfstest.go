@@ -0,0 +1,282 @@
+// Package xfstest provides helpers for tests that work with the file system:
+// isolated sandbox directories, trees written from and compared against
+// maps, and golden files that are refreshed with the -update flag.
+package xfstest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func init() {
+	// Another package may already define the flag; share it then.
+	if flag.Lookup("update") == nil {
+		flag.Bool("update", false, "update golden files")
+	}
+}
+
+// updating reports whether the -update flag is set.
+func updating() bool {
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+
+	v, _ := strconv.ParseBool(f.Value.String())
+	return v
+}
+
+// Dir is a sandbox directory created by Sandbox.
+type Dir struct {
+	t testing.TB
+
+	// Path is the absolute path of the sandbox root.
+	Path string
+}
+
+// Sandbox returns a new, empty directory that is removed when the test and
+// its subtests complete.
+//
+// Parameters:
+//   - t: the test
+func Sandbox(t testing.TB) *Dir {
+	t.Helper()
+	return &Dir{t: t, Path: t.TempDir()}
+}
+
+// Join returns the path of the slash separated name below the sandbox root.
+//
+// Parameters:
+//   - name: the slash separated name
+func (d *Dir) Join(name string) string {
+	return filepath.Join(d.Path, filepath.FromSlash(name))
+}
+
+// WriteTree writes files below the sandbox root, see WriteTree.
+//
+// Parameters:
+//   - files: the files to write
+func (d *Dir) WriteTree(files map[string]string) {
+	d.t.Helper()
+	WriteTree(d.t, d.Path, files)
+}
+
+// ReadTree returns the tree below the sandbox root, see ReadTree.
+func (d *Dir) ReadTree() map[string]string {
+	d.t.Helper()
+	return ReadTree(d.t, d.Path)
+}
+
+// AssertTreeEqual checks the tree below the sandbox root, see
+// AssertTreeEqual.
+//
+// Parameters:
+//   - want: the expected tree
+func (d *Dir) AssertTreeEqual(want map[string]string) bool {
+	d.t.Helper()
+	return AssertTreeEqual(d.t, d.Path, want)
+}
+
+// AssertFileContent checks the content of the slash separated name below the
+// sandbox root, see AssertFileContent.
+//
+// Parameters:
+//   - name: the slash separated name
+//   - want: the expected content
+func (d *Dir) AssertFileContent(name, want string) bool {
+	d.t.Helper()
+	return AssertFileContent(d.t, d.Join(name), want)
+}
+
+// WriteTree creates the files below root. The keys are slash separated
+// names and the values the file contents; a key ending in "/" creates an
+// empty directory. Parent directories are created as needed. Failures stop
+// the test.
+//
+// Parameters:
+//   - t: the test
+//   - root: the directory to write to
+//   - files: the files to write
+func WriteTree(t testing.TB, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(strings.TrimSuffix(name, "/")))
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// ReadTree returns the regular files below root in the form accepted by
+// WriteTree. Empty directories are included with a trailing "/" and an empty
+// value, other directories are implied by the files they contain. Symbolic
+// links are reported with the value "-> target". Failures stop the test.
+//
+// Parameters:
+//   - t: the test
+//   - root: the directory to read
+func ReadTree(t testing.TB, root string) map[string]string {
+	t.Helper()
+	tree := map[string]string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(rel)
+		switch {
+		case d.IsDir():
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				return err
+			}
+
+			if len(entries) == 0 {
+				tree[name+"/"] = ""
+			}
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			tree[name] = "-> " + target
+		default:
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			tree[name] = string(data)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tree
+}
+
+// AssertTreeEqual checks that the tree below root, as returned by ReadTree,
+// equals want, and reports every missing, unexpected and differing entry.
+//
+// Parameters:
+//   - t: the test
+//   - root: the directory to check
+//   - want: the expected tree
+func AssertTreeEqual(t testing.TB, root string, want map[string]string) bool {
+	t.Helper()
+	got := ReadTree(t, root)
+
+	var problems []string
+	for name, content := range want {
+		actual, ok := got[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("missing %s", name))
+		case actual != content:
+			problems = append(problems, fmt.Sprintf("%s: got %q, want %q", name, actual, content))
+		}
+	}
+
+	for name := range got {
+		if _, ok := want[name]; !ok {
+			problems = append(problems, fmt.Sprintf("unexpected %s", name))
+		}
+	}
+
+	if len(problems) == 0 {
+		return true
+	}
+
+	slices.Sort(problems)
+	t.Errorf("tree %s differs:\n\t%s", root, strings.Join(problems, "\n\t"))
+	return false
+}
+
+// AssertFileContent checks that the named file exists and holds want.
+//
+// Parameters:
+//   - t: the test
+//   - filename: the name of the file
+//   - want: the expected content
+func AssertFileContent(t testing.TB, filename, want string) bool {
+	t.Helper()
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Errorf("reading %s: %v", filename, err)
+		return false
+	}
+
+	if string(data) != want {
+		t.Errorf("%s: got %q, want %q", filename, data, want)
+		return false
+	}
+
+	return true
+}
+
+// Golden compares got with the golden file testdata/<name>.golden, relative
+// to the package under test. With the -update flag the golden file is
+// written instead, creating testdata if needed.
+//
+// Parameters:
+//   - t: the test
+//   - name: the slash separated golden file name without extension
+//   - got: the actual output
+func Golden(t testing.TB, name string, got []byte) bool {
+	t.Helper()
+	path := filepath.Join("testdata", filepath.FromSlash(name)+".golden")
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("reading golden file (run with -update to create it): %v", err)
+		return false
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run with -update to accept):\ngot:\n%s\nwant:\n%s", path, got, want)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,61 @@
+package xfstest_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jolt9dev/go-xfs"
+	"github.com/jolt9dev/go-xfs/xfstest"
+)
+
+func TestSandbox(t *testing.T) {
+	dir := xfstest.Sandbox(t)
+	dir.WriteTree(map[string]string{
+		"a.txt":     "a",
+		"sub/b.txt": "b",
+		"empty/":    "",
+	})
+
+	dir.AssertFileContent("sub/b.txt", "b")
+	dir.AssertTreeEqual(map[string]string{
+		"a.txt":     "a",
+		"sub/b.txt": "b",
+		"empty/":    "",
+	})
+
+	if err := xfs.CopyDir(dir.Join("sub"), dir.Join("copy"), false); err != nil {
+		t.Fatal(err)
+	}
+
+	dir.AssertFileContent("copy/b.txt", "b")
+}
+
+func TestAssertTreeEqualReports(t *testing.T) {
+	dir := xfstest.Sandbox(t)
+	dir.WriteTree(map[string]string{"a": "1"})
+
+	rec := &recorder{TB: t}
+	if xfstest.AssertTreeEqual(rec, dir.Path, map[string]string{"b": "2"}) || len(rec.errors) != 1 {
+		t.Errorf("expected one mismatch report, got %q", rec.errors)
+	}
+
+	os.Remove(dir.Join("a"))
+	if !xfstest.AssertTreeEqual(t, dir.Path, map[string]string{}) {
+		t.Error("expected an empty tree")
+	}
+}
+
+func TestGolden(t *testing.T) {
+	xfstest.Golden(t, "greeting", []byte("hello golden\n"))
+}
+
+// recorder captures reported errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}